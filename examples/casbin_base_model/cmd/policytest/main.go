@@ -0,0 +1,86 @@
+// policytest 运行目录下所有 *.policytest.yaml 策略测试，有失败用例时以非 0 退出，可用于 CI
+//
+//	go run ./cmd/policytest -root ../
+//	go run ./cmd/policytest -root ../ -strict   # 同时要求每个 .conf 都有测试
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"casbin_base_model/policytest"
+)
+
+func main() {
+	root := flag.String("root", ".", "递归查找测试文件的根目录")
+	strict := flag.Bool("strict", false, "存在没有测试的模型文件时视为失败")
+	verbose := flag.Bool("v", false, "输出每条用例的结果")
+	flag.Parse()
+
+	paths, err := policytest.Discover(*root)
+	if err != nil {
+		log.Fatalf("Discover failed: %v", err)
+	}
+
+	failed := 0
+	suites := make([]*policytest.Suite, 0, len(paths))
+	for _, path := range paths {
+		suite, err := policytest.LoadSuite(path)
+		if err != nil {
+			fmt.Printf("ERROR %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		suites = append(suites, suite)
+
+		if suite.Skip != "" {
+			fmt.Printf("SKIP  %s: %s\n", path, suite.Skip)
+			continue
+		}
+
+		var report *policytest.Report
+		opts, err := policytest.ExampleOptions(suite)
+		if err == nil {
+			report, err = suite.Run(opts)
+		}
+		if err != nil {
+			fmt.Printf("ERROR %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		failures := report.Failures()
+		status := "PASS"
+		if len(failures) > 0 {
+			status = "FAIL"
+		}
+		fmt.Printf("%s  %s (%d/%d)\n", status, path, len(report.Results)-len(failures), len(report.Results))
+
+		for _, res := range report.Results {
+			if !res.Passed() {
+				fmt.Printf("    ✗ %s: %s\n", res.Case.Name, res.Explain())
+			} else if *verbose {
+				fmt.Printf("    ✓ %s\n", res.Case.Name)
+			}
+		}
+		failed += len(failures)
+	}
+
+	uncovered, err := policytest.Uncovered(*root, suites)
+	if err != nil {
+		log.Fatalf("Uncovered failed: %v", err)
+	}
+	for _, path := range uncovered {
+		fmt.Printf("WARN  %s 没有策略测试\n", path)
+	}
+	if *strict {
+		failed += len(uncovered)
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d 项失败\n", failed)
+		os.Exit(1)
+	}
+}
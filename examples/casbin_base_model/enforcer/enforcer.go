@@ -1,22 +1,46 @@
 package enforcer
 
 import (
-	"fmt"
+	"context"
+	"database/sql"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// 默认超时时间
+const (
+	defaultConnectTimeout   = 10 * time.Second
+	defaultAutoLoadInterval = 10 // 秒
+)
+
 // Config 定义权限配置
 type Config struct {
-	// 数据库配置
+	// 数据库配置，DBType 为 mysql（默认）、postgres 或 sqlite
 	DBType       string
 	DBConnection string
 
+	// 连接池配置，为 0 时使用 database/sql 的默认值
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// 创建 enforcer 时连接数据库、建表和首次加载策略的总超时时间，默认 10 秒
+	ConnectTimeout time.Duration
+	// 自动加载和 Watcher 触发的单次加载超时时间，默认为自动加载间隔
+	LoadTimeout time.Duration
+
 	// 模型配置文件路径
 	ModelPath string
 
@@ -24,144 +48,607 @@ type Config struct {
 	AutoLoad bool
 	// 自动加载间隔（秒）
 	AutoLoadInterval int
+
+	// Watcher 用于多实例间同步策略变更（如 redis-watcher），回滚后会通知其他实例重新加载
+	Watcher persist.Watcher
+
+	// 指标注册器，为空则不采集指标
+	MetricsRegisterer prometheus.Registerer
+	// 指标命名空间，默认 casbin
+	MetricsNamespace string
+
+	// 自动加载、Watcher 触发的后台加载失败时的日志输出，默认 log.Default()
+	Logger *log.Logger
+}
+
+// validate 检查必填项并填充默认值
+func (c *Config) validate() error {
+	switch c.DBType {
+	case "", "mysql", "postgres", "sqlite":
+	default:
+		return invalidConfig("unsupported DBType %q", c.DBType)
+	}
+	if c.DBConnection == "" {
+		return invalidConfig("DBConnection is required")
+	}
+	if c.ModelPath == "" {
+		return invalidConfig("ModelPath is required")
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 || c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 ||
+		c.ConnectTimeout < 0 || c.LoadTimeout < 0 || c.AutoLoadInterval < 0 {
+		return invalidConfig("pool sizes, timeouts and intervals must not be negative")
+	}
+
+	if c.ConnectTimeout == 0 {
+		c.ConnectTimeout = defaultConnectTimeout
+	}
+	if c.AutoLoad && c.AutoLoadInterval == 0 {
+		c.AutoLoadInterval = defaultAutoLoadInterval
+	}
+	if c.LoadTimeout == 0 && c.AutoLoadInterval > 0 {
+		c.LoadTimeout = time.Duration(c.AutoLoadInterval) * time.Second
+	}
+	if c.Logger == nil {
+		c.Logger = log.Default()
+	}
+	return nil
+}
+
+// dialector 按 DBType 选择数据库驱动
+func (c *Config) dialector() gorm.Dialector {
+	switch c.DBType {
+	case "postgres":
+		return postgres.Open(c.DBConnection)
+	case "sqlite":
+		return sqlite.Open(c.DBConnection)
+	default:
+		return mysql.Open(c.DBConnection)
+	}
 }
 
 // Enforcer 封装 casbin enforcer
 type Enforcer struct {
 	enforcer *casbin.Enforcer
 	adapter  *gormadapter.Adapter
-	config   *Config
-	mu       sync.RWMutex
+	// adapterCtx adapter 查询使用的 ctx，由 withAdapterContext 切换
+	adapterCtx *adapterContext
+	db         *gorm.DB
+	config     *Config
+	mu         sync.RWMutex
+
+	metrics *Metrics
+	state   loadState
+
+	// filter 不为空时只加载匹配的策略（Pool 中的单个域）
+	filter []gormadapter.Filter
+	// pool 所属的 Pool，策略数量由 Pool 汇总后更新指标
+	pool *Pool
+	// 最近一次统计的策略和分组策略数量
+	policyCount, groupingCount atomic.Int64
+	// shared 数据库连接和 Watcher 属于 Pool，Close 时不关闭
+	shared bool
+
+	// ctx 在 Close 时取消，用于停止自动加载并中断进行中的后台加载
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed atomic.Bool
 }
 
 // NewEnforcer 创建一个新的 enforcer 实例
 func NewEnforcer(config *Config) (*Enforcer, error) {
-	// 连接数据库
-	db, err := gorm.Open(mysql.Open(config.DBConnection), &gorm.Config{})
+	return NewEnforcerContext(context.Background(), config)
+}
+
+// NewEnforcerContext 创建一个新的 enforcer 实例，ctx 和 Config.ConnectTimeout 限制连接数据库和首次加载策略的时间
+func NewEnforcerContext(ctx context.Context, config *Config) (*Enforcer, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.ConnectTimeout)
+	defer cancel()
+
+	db, err := openDB(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("connect database failed: %v", err)
+		return nil, err
 	}
+	enforcer, err := newEnforcer(ctx, db, config)
+	if err != nil {
+		closeDB(db)
+		return nil, err
+	}
+	return enforcer, nil
+}
 
-	// 创建 adapter
-	adapter, err := gormadapter.NewAdapterByDB(db)
+// openDB 连接数据库并应用连接池配置，自行 Ping 以便使用 ctx 控制超时
+func openDB(ctx context.Context, config *Config) (*gorm.DB, error) {
+	db, err := gorm.Open(config.dialector(), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return nil, opError(OpConnectDatabase, err)
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("create adapter failed: %v", err)
+		return nil, opError(OpConnectDatabase, err)
 	}
+	applyPool(sqlDB, config)
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, opError(OpConnectDatabase, err)
+	}
+	return db, nil
+}
 
-	// 创建 enforcer
-	e, err := casbin.NewEnforcer(config.ModelPath, adapter)
+// closeDB 关闭底层连接
+func closeDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("create enforcer failed: %v", err)
+		return err
 	}
+	return sqlDB.Close()
+}
 
-	// 加载策略
-	if err := e.LoadPolicy(); err != nil {
-		return nil, fmt.Errorf("load policy failed: %v", err)
+func newEnforcer(ctx context.Context, db *gorm.DB, config *Config) (*Enforcer, error) {
+	// 建表，之后的 adapter 都关闭自动迁移
+	if _, err := gormadapter.NewAdapterByDB(db.WithContext(ctx)); err != nil {
+		return nil, opError(OpCreateAdapter, err)
+	}
+	adapterCtx := &adapterContext{}
+	adapter, err := newAdapter(db, adapterCtx)
+	if err != nil {
+		return nil, opError(OpCreateAdapter, err)
+	}
+
+	// 创建快照表
+	if err := db.WithContext(ctx).AutoMigrate(&PolicySnapshot{}); err != nil {
+		return nil, opError(OpMigrate, err)
+	}
+
+	// 创建 enforcer，策略在下面通过 LoadPolicyContext 加载
+	e, err := casbin.NewEnforcer(config.ModelPath)
+	if err != nil {
+		return nil, opError(OpCreateEnforcer, err)
 	}
+	e.SetAdapter(adapter)
 
 	enforcer := &Enforcer{
-		enforcer: e,
-		adapter:  adapter,
-		config:   config,
+		enforcer:   e,
+		adapter:    adapter,
+		adapterCtx: adapterCtx,
+		db:         db,
+		config:     config,
+	}
+	enforcer.ctx, enforcer.cancel = context.WithCancel(context.Background())
+
+	if config.MetricsRegisterer != nil {
+		enforcer.metrics, err = NewMetrics(config.MetricsRegisterer, config.MetricsNamespace)
+		if err != nil {
+			return nil, opError(OpRegisterMetrics, err)
+		}
+	}
+
+	// 加载策略
+	if err := enforcer.LoadPolicyContext(ctx); err != nil {
+		return nil, err
+	}
+
+	// 其他实例变更策略时重新加载（经过包装的 LoadPolicy 会加锁并记录指标）
+	if config.Watcher != nil {
+		if err := e.SetWatcher(config.Watcher); err != nil {
+			return nil, opError(OpSetWatcher, err)
+		}
+		if err := config.Watcher.SetUpdateCallback(func(string) {
+			if err := enforcer.reload(); err != nil {
+				config.Logger.Printf("Reload policy on watcher update failed: %v", err)
+			}
+		}); err != nil {
+			return nil, opError(OpSetWatcher, err)
+		}
 	}
 
 	// 如果配置了自动加载，启动自动加载协程
 	if config.AutoLoad {
+		enforcer.wg.Add(1)
 		go enforcer.autoLoad()
 	}
 
 	return enforcer, nil
 }
 
-// 检查权限
+// applyPool 应用连接池配置
+func applyPool(sqlDB *sql.DB, config *Config) {
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// newAdapter 创建关闭自动迁移的 adapter，它的查询使用 actx 当前的 ctx
+func newAdapter(db *gorm.DB, actx *adapterContext) (*gormadapter.Adapter, error) {
+	db = db.WithContext(actx)
+	gormadapter.TurnOffAutoMigrate(db)
+	return gormadapter.NewAdapterByDB(db)
+}
+
+// Enforce 检查权限
 func (e *Enforcer) Enforce(rvals ...interface{}) (bool, error) {
-	e.mu.RLock()
+	return e.EnforceContext(context.Background(), rvals...)
+}
+
+// EnforceContext 检查权限，ctx 取消时放弃等待读锁
+func (e *Enforcer) EnforceContext(ctx context.Context, rvals ...interface{}) (bool, error) {
+	if err := e.rlockContext(ctx); err != nil {
+		return false, opError(OpEnforce, err)
+	}
 	defer e.mu.RUnlock()
-	return e.enforcer.Enforce(rvals...)
+
+	start := time.Now()
+	allowed, err := e.enforcer.Enforce(rvals...)
+	e.metrics.observeEnforce(start, allowed, err)
+	return allowed, opError(OpEnforce, err)
 }
 
 // AddPolicy 添加策略
 func (e *Enforcer) AddPolicy(params ...interface{}) (bool, error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return e.enforcer.AddPolicy(params...)
+	return e.AddPolicyContext(context.Background(), params...)
+}
+
+// AddPolicyContext 添加策略，ctx 同时作用于等待写锁和写入数据库
+func (e *Enforcer) AddPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	return e.update(ctx, func() (bool, error) {
+		return e.enforcer.AddPolicy(params...)
+	})
 }
 
 // RemovePolicy 删除策略
 func (e *Enforcer) RemovePolicy(params ...interface{}) (bool, error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return e.enforcer.RemovePolicy(params...)
+	return e.RemovePolicyContext(context.Background(), params...)
+}
+
+// RemovePolicyContext 删除策略
+func (e *Enforcer) RemovePolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	return e.update(ctx, func() (bool, error) {
+		return e.enforcer.RemovePolicy(params...)
+	})
 }
 
 // AddGroupingPolicy 添加角色继承关系
 func (e *Enforcer) AddGroupingPolicy(params ...interface{}) (bool, error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return e.enforcer.AddGroupingPolicy(params...)
+	return e.AddGroupingPolicyContext(context.Background(), params...)
+}
+
+// AddGroupingPolicyContext 添加角色继承关系
+func (e *Enforcer) AddGroupingPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	return e.update(ctx, func() (bool, error) {
+		return e.enforcer.AddGroupingPolicy(params...)
+	})
 }
 
 // RemoveGroupingPolicy 删除角色继承关系
 func (e *Enforcer) RemoveGroupingPolicy(params ...interface{}) (bool, error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	return e.enforcer.RemoveGroupingPolicy(params...)
+	return e.RemoveGroupingPolicyContext(context.Background(), params...)
+}
+
+// RemoveGroupingPolicyContext 删除角色继承关系
+func (e *Enforcer) RemoveGroupingPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	return e.update(ctx, func() (bool, error) {
+		return e.enforcer.RemoveGroupingPolicy(params...)
+	})
 }
 
 // GetAllSubjects 获取所有主体
 func (e *Enforcer) GetAllSubjects() []string {
-	e.mu.RLock()
+	e.rlock()
 	defer e.mu.RUnlock()
 	return e.enforcer.GetAllSubjects()
 }
 
 // GetAllObjects 获取所有对象
 func (e *Enforcer) GetAllObjects() []string {
-	e.mu.RLock()
+	e.rlock()
 	defer e.mu.RUnlock()
 	return e.enforcer.GetAllObjects()
 }
 
 // GetAllActions 获取所有操作
 func (e *Enforcer) GetAllActions() []string {
-	e.mu.RLock()
+	e.rlock()
 	defer e.mu.RUnlock()
 	return e.enforcer.GetAllActions()
 }
 
 // GetAllRoles 获取所有角色
 func (e *Enforcer) GetAllRoles() []string {
-	e.mu.RLock()
+	e.rlock()
 	defer e.mu.RUnlock()
 	return e.enforcer.GetAllRoles()
 }
 
 // LoadPolicy 重新加载策略
 func (e *Enforcer) LoadPolicy() error {
-	e.mu.Lock()
+	return e.LoadPolicyContext(context.Background())
+}
+
+// LoadPolicyContext 重新加载策略，ctx 取消时中断查询，内存中的策略保持不变
+func (e *Enforcer) LoadPolicyContext(ctx context.Context) error {
+	if err := e.lockContext(ctx); err != nil {
+		return opError(OpLoadPolicy, err)
+	}
 	defer e.mu.Unlock()
-	return e.enforcer.LoadPolicy()
+
+	start := time.Now()
+	load := e.enforcer.LoadPolicy
+	if e.filter != nil {
+		load = e.loadFiltered
+	}
+	err := e.withAdapterContext(ctx, load)
+	e.metrics.observeReload(start, err)
+	e.state.recordLoad(start, err)
+	if err == nil {
+		e.updatePolicyCount()
+	}
+	return opError(OpLoadPolicy, err)
 }
 
 // SavePolicy 保存策略到存储
 func (e *Enforcer) SavePolicy() error {
-	e.mu.Lock()
+	return e.SavePolicyContext(context.Background())
+}
+
+// SavePolicyContext 保存策略到存储
+func (e *Enforcer) SavePolicyContext(ctx context.Context) error {
+	if err := e.lockContext(ctx); err != nil {
+		return opError(OpSavePolicy, err)
+	}
 	defer e.mu.Unlock()
-	return e.enforcer.SavePolicy()
+
+	if e.filter != nil {
+		return opError(OpSavePolicy, ErrFilteredPolicy)
+	}
+	return opError(OpSavePolicy, e.withAdapterContext(ctx, e.enforcer.SavePolicy))
 }
 
-// autoLoad 自动加载策略
-func (e *Enforcer) autoLoad() {
-	if e.config.AutoLoadInterval <= 0 {
-		e.config.AutoLoadInterval = 10 // 默认10秒
+// Close 停止自动加载，等待进行中的操作结束后关闭 Watcher 和数据库连接
+// ctx 到期时不再等待，同样关闭 Watcher 和数据库连接后返回 ctx 的错误，仍在进行的操作会因连接关闭而失败；
+// 之后的调用都返回 ErrClosed，重复调用 Close 返回 nil。
+// Pool 中的 enforcer 只标记为关闭，连接由 Pool.Close 关闭
+func (e *Enforcer) Close(ctx context.Context) (err error) {
+	if !e.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	e.cancel()
+	// 之后的 Close 直接返回，资源只有这一次释放的机会，等待超时也要释放
+	defer func() {
+		if e.shared {
+			return
+		}
+		if e.config.Watcher != nil {
+			e.config.Watcher.Close()
+		}
+		if closeErr := closeDB(e.db); err == nil {
+			err = opError(OpClose, closeErr)
+		}
+	}()
+
+	stopped := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return opError(OpClose, ctx.Err())
 	}
 
+	// 等待持有锁的操作完成
+	start := time.Now()
+	if err := acquire(ctx, e.mu.Lock, e.mu.Unlock); err != nil {
+		return opError(OpClose, err)
+	}
+	defer e.mu.Unlock()
+	e.metrics.observeLockWait("write", start)
+	return nil
+}
+
+// autoLoad 自动加载策略，Close 时退出
+func (e *Enforcer) autoLoad() {
+	defer e.wg.Done()
+
 	ticker := time.NewTicker(time.Duration(e.config.AutoLoadInterval) * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := e.LoadPolicy(); err != nil {
-			fmt.Printf("Auto load policy failed: %v\n", err)
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			// 失败次数和最近错误记录在 Health() 和 policy_reload_errors_total 中
+			if err := e.reload(); err != nil && !e.closed.Load() {
+				e.config.Logger.Printf("Auto load policy failed: %v", err)
+			}
+		}
+	}
+}
+
+// reload 后台加载策略，受 Config.LoadTimeout 限制，Close 时中断
+func (e *Enforcer) reload() error {
+	ctx, cancel := e.ctx, context.CancelFunc(func() {})
+	if e.config.LoadTimeout > 0 {
+		ctx, cancel = context.WithTimeout(e.ctx, e.config.LoadTimeout)
+	}
+	defer cancel()
+	return e.LoadPolicyContext(ctx)
+}
+
+// update 在写锁内执行策略变更，数据库写入使用 ctx
+func (e *Enforcer) update(ctx context.Context, fn func() (bool, error)) (bool, error) {
+	if err := e.lockContext(ctx); err != nil {
+		return false, opError(OpUpdatePolicy, err)
+	}
+	defer e.mu.Unlock()
+	defer e.updatePolicyCount()
+
+	var ok bool
+	err := e.withAdapterContext(ctx, func() error {
+		var err error
+		ok, err = fn()
+		return err
+	})
+	return ok, opError(OpUpdatePolicy, err)
+}
+
+// loadFiltered 按 filter 把策略加载到新的 casbin enforcer，成功后替换，失败时保留原策略，调用方需持有写锁
+func (e *Enforcer) loadFiltered() error {
+	m := e.enforcer.GetModel().Copy()
+	m.ClearPolicy()
+	ce, err := casbin.NewEnforcer(m)
+	if err != nil {
+		return err
+	}
+
+	ce.SetAdapter(e.adapter)
+	if err := ce.LoadFilteredPolicy(e.filter); err != nil {
+		return err
+	}
+
+	// 变更后通知其他实例，回调由 Pool 统一注册
+	if w := e.config.Watcher; w != nil {
+		if err := ce.SetWatcher(notifyOnly{w}); err != nil {
+			return err
 		}
 	}
+	e.enforcer = ce
+	return nil
+}
+
+// withAdapterContext 在 fn 执行期间让 adapter 的查询使用 ctx，调用方需持有写锁
+func (e *Enforcer) withAdapterContext(ctx context.Context, fn func() error) error {
+	e.adapterCtx.set(ctx)
+	defer e.adapterCtx.set(context.Background())
+	return fn()
+}
+
+// adapterContext adapter 查询使用的 ctx，转发到当前设置的 ctx，未设置时为 context.Background()
+// adapter 只在创建 enforcer 时构造一次，每次加载、变更时切换它的 ctx
+type adapterContext struct {
+	current atomic.Value // ctxHolder
+}
+
+// ctxHolder 让 atomic.Value 中始终存放同一具体类型
+type ctxHolder struct {
+	ctx context.Context
+}
+
+func (c *adapterContext) set(ctx context.Context) {
+	c.current.Store(ctxHolder{ctx: ctx})
+}
+
+func (c *adapterContext) get() context.Context {
+	if h, ok := c.current.Load().(ctxHolder); ok {
+		return h.ctx
+	}
+	return context.Background()
+}
+
+func (c *adapterContext) Deadline() (time.Time, bool) { return c.get().Deadline() }
+
+func (c *adapterContext) Done() <-chan struct{} { return c.get().Done() }
+
+func (c *adapterContext) Err() error { return c.get().Err() }
+
+func (c *adapterContext) Value(key interface{}) interface{} { return c.get().Value(key) }
+
+// rlock 获取读锁并记录等待时间
+func (e *Enforcer) rlock() {
+	start := time.Now()
+	e.mu.RLock()
+	e.metrics.observeLockWait("read", start)
+}
+
+// rlockContext 获取读锁，ctx 取消或 enforcer 已关闭时返回错误
+func (e *Enforcer) rlockContext(ctx context.Context) error {
+	start := time.Now()
+	if !e.mu.TryRLock() {
+		if err := acquire(ctx, e.mu.RLock, e.mu.RUnlock); err != nil {
+			return err
+		}
+	}
+	e.metrics.observeLockWait("read", start)
+	if e.closed.Load() {
+		e.mu.RUnlock()
+		return ErrClosed
+	}
+	return nil
+}
+
+// lockContext 获取写锁，ctx 取消或 enforcer 已关闭时返回错误
+func (e *Enforcer) lockContext(ctx context.Context) error {
+	start := time.Now()
+	if !e.mu.TryLock() {
+		if err := acquire(ctx, e.mu.Lock, e.mu.Unlock); err != nil {
+			return err
+		}
+	}
+	e.metrics.observeLockWait("write", start)
+	if e.closed.Load() {
+		e.mu.Unlock()
+		return ErrClosed
+	}
+	return nil
+}
+
+// acquire 在 ctx 取消前获取锁；放弃等待时，锁在拿到后由后台协程释放
+func acquire(ctx context.Context, lock, unlock func()) error {
+	if ctx.Done() == nil {
+		lock()
+		return nil
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
+// updatePolicyCount 更新策略数量指标，调用方需持有锁
+// Pool 中的 enforcer 只记录本域的数量，由 Pool 汇总
+func (e *Enforcer) updatePolicyCount() {
+	if e.metrics == nil {
+		return
+	}
+	model := e.enforcer.GetModel()
+	policies, groupings := 0, 0
+	for _, ast := range model["p"] {
+		policies += len(ast.Policy)
+	}
+	for _, ast := range model["g"] {
+		groupings += len(ast.Policy)
+	}
+	e.policyCount.Store(int64(policies))
+	e.groupingCount.Store(int64(groupings))
+
+	if e.pool != nil {
+		e.pool.updatePolicyCount()
+		return
+	}
+	e.metrics.setPolicyCount(policies, groupings)
 }
@@ -0,0 +1,258 @@
+package enforcer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testModelPath = "../models/rbac_with_domains.conf"
+
+// openTestDB 打开一个临时 sqlite 数据库，测试结束时关闭
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "casbin.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeDB(db) })
+	return db
+}
+
+// newTestEnforcer 基于 sqlite 创建 enforcer，config 只需设置 Metrics、AutoLoad 等可选项
+func newTestEnforcer(t *testing.T, db *gorm.DB, config *Config) *Enforcer {
+	t.Helper()
+	if config == nil {
+		config = &Config{}
+	}
+	config.DBConnection = "sqlite"
+	if config.ModelPath == "" {
+		config.ModelPath = testModelPath
+	}
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := newEnforcer(context.Background(), db, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.shared = true // 数据库由 openTestDB 关闭
+	t.Cleanup(func() { e.Close(context.Background()) })
+	return e
+}
+
+// waitUnlocked 等待 mu 被释放，确认放弃等待的 acquire 最终归还了锁
+func waitUnlocked(t *testing.T, mu *sync.RWMutex) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !mu.TryLock() {
+		if time.Now().After(deadline) {
+			t.Fatal("锁未被释放")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Unlock()
+}
+
+func TestAcquire(t *testing.T) {
+	var mu sync.RWMutex
+
+	if err := acquire(context.Background(), mu.Lock, mu.Unlock); err != nil {
+		t.Fatal(err)
+	}
+	mu.Unlock()
+
+	// 锁被占用时，ctx 到期后返回，拿到锁后由后台协程释放
+	mu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := acquire(ctx, mu.Lock, mu.Unlock); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire = %v, want DeadlineExceeded", err)
+	}
+	mu.Unlock()
+	waitUnlocked(t, &mu)
+
+	// ctx 已取消但锁空闲时可能拿到锁，也可能放弃，两种情况下锁都不会泄漏
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := acquire(canceled, mu.Lock, mu.Unlock); err == nil {
+		mu.Unlock()
+	}
+	waitUnlocked(t, &mu)
+}
+
+func TestContextWhileLocked(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), nil)
+
+	e.mu.Lock()
+	calls := map[string]func(ctx context.Context) error{
+		"EnforceContext": func(ctx context.Context) error {
+			_, err := e.EnforceContext(ctx, "alice", "domain1", "/data", "read")
+			return err
+		},
+		"AddPolicyContext": func(ctx context.Context) error {
+			_, err := e.AddPolicyContext(ctx, "admin", "domain1", "/data", "read")
+			return err
+		},
+		"LoadPolicyContext": e.LoadPolicyContext,
+		"SavePolicyContext": e.SavePolicyContext,
+	}
+	for name, call := range calls {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		err := call(ctx)
+		cancel()
+		var opErr *Error
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &opErr) {
+			t.Errorf("%s = %v, want *Error wrapping DeadlineExceeded", name, err)
+		}
+	}
+	e.mu.Unlock()
+	waitUnlocked(t, &e.mu)
+
+	if _, err := e.AddPolicy("admin", "domain1", "/data", "read"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := e.Enforce("admin", "domain1", "/data", "read"); err != nil || !ok {
+		t.Fatalf("Enforce = %v, %v, want true", ok, err)
+	}
+}
+
+func TestAdapterContext(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), nil)
+	mustAddPolicy(t, e, "admin", "domain1", "/data", "read")
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 数据库操作使用调用方的 ctx，失败时内存中的策略不变
+	if _, err := e.AddPolicyContext(canceled, "admin", "domain1", "/data", "write"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddPolicyContext = %v, want Canceled", err)
+	}
+	if err := e.LoadPolicyContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("LoadPolicyContext = %v, want Canceled", err)
+	}
+	if got := e.enforcer.GetPolicy(); len(got) != 1 {
+		t.Fatalf("policies = %v, want only the stored rule", got)
+	}
+
+	// 操作结束后 adapter 恢复为不受取消影响的 ctx
+	if err := e.adapterCtx.Err(); err != nil {
+		t.Fatalf("adapter ctx err = %v, want nil", err)
+	}
+	mustAddPolicy(t, e, "admin", "domain1", "/data", "write")
+	if err := e.LoadPolicy(); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.enforcer.GetPolicy(); len(got) != 2 {
+		t.Fatalf("policies = %v, want 2", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	db := openTestDB(t)
+	config := &Config{DBConnection: "sqlite", ModelPath: testModelPath}
+	if err := config.validate(); err != nil {
+		t.Fatal(err)
+	}
+	e, err := newEnforcer(context.Background(), db, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(context.Background()); err != nil {
+		t.Fatalf("second Close = %v, want nil", err)
+	}
+
+	if _, err := e.Enforce("alice", "domain1", "/data", "read"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Enforce after Close = %v, want ErrClosed", err)
+	}
+	if _, err := e.AddPolicy("admin", "domain1", "/data", "read"); !errors.Is(err, ErrClosed) {
+		t.Errorf("AddPolicy after Close = %v, want ErrClosed", err)
+	}
+	if err := e.LoadPolicy(); !errors.Is(err, ErrClosed) {
+		t.Errorf("LoadPolicy after Close = %v, want ErrClosed", err)
+	}
+
+	// 不属于 Pool 的 enforcer 关闭时关闭数据库连接
+	sqlDB, _ := db.DB()
+	if err := sqlDB.Ping(); err == nil {
+		t.Error("Close 后数据库连接仍然可用")
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	db := openTestDB(t)
+	watcher := &stubWatcher{}
+	e := newTestEnforcer(t, db, &Config{Watcher: watcher})
+	e.shared = false
+
+	// 进行中的操作持有读锁时，Close 在 ctx 到期后返回
+	e.mu.RLock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := e.Close(ctx)
+	var opErr *Error
+	if !errors.As(err, &opErr) || opErr.Op != OpClose || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want close error wrapping DeadlineExceeded", err)
+	}
+	e.mu.RUnlock()
+	waitUnlocked(t, &e.mu)
+
+	if _, err := e.Enforce("alice", "domain1", "/data", "read"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Enforce after timed out Close = %v, want ErrClosed", err)
+	}
+
+	// 超时后 Watcher 和数据库连接同样被释放
+	if !watcher.closed {
+		t.Error("Close 超时后 Watcher 没有关闭")
+	}
+	sqlDB, _ := db.DB()
+	if err := sqlDB.Ping(); err == nil {
+		t.Error("Close 超时后数据库连接仍然可用")
+	}
+}
+
+// stubWatcher 记录回调，测试中手动触发
+type stubWatcher struct {
+	callback func(string)
+	closed   bool
+}
+
+func (w *stubWatcher) SetUpdateCallback(fn func(string)) error {
+	w.callback = fn
+	return nil
+}
+
+func (w *stubWatcher) Update() error { return nil }
+
+func (w *stubWatcher) Close() { w.closed = true }
+
+func TestWatcherReloadFailureLogged(t *testing.T) {
+	var buf bytes.Buffer
+	watcher := &stubWatcher{}
+	db := openTestDB(t)
+	newTestEnforcer(t, db, &Config{Watcher: watcher, Logger: log.New(&buf, "", 0)})
+
+	if err := db.Migrator().DropTable("casbin_rule"); err != nil {
+		t.Fatal(err)
+	}
+	watcher.callback("")
+
+	if got := buf.String(); !strings.HasPrefix(got, "Reload policy on watcher update failed: load policy failed") {
+		t.Errorf("log = %q", got)
+	}
+}
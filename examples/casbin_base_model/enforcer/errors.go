@@ -0,0 +1,72 @@
+package enforcer
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrClosed enforcer 已关闭
+	ErrClosed = errors.New("enforcer closed")
+	// ErrInvalidConfig 配置缺少必填项或取值非法
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrSnapshotNotFound 快照不存在
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	// ErrFilteredPolicy 只加载了部分策略（Pool 中的单个域），不能整体保存、快照或回滚
+	ErrFilteredPolicy = errors.New("operation not allowed on a filtered policy")
+	// ErrRuleTooLong 规则字段多于 casbin_rule 表的 V0~V5，写入数据库会丢失多出的字段
+	ErrRuleTooLong = errors.New("rule has more fields than casbin_rule can store")
+)
+
+// 出错的操作，对应 Error.Op
+const (
+	OpValidateConfig  = "validate config"
+	OpConnectDatabase = "connect database"
+	OpCreateAdapter   = "create adapter"
+	OpMigrate         = "migrate snapshot table"
+	OpCreateEnforcer  = "create enforcer"
+	OpRegisterMetrics = "register metrics"
+	OpEnforce         = "enforce"
+	OpLoadPolicy      = "load policy"
+	OpSavePolicy      = "save policy"
+	OpUpdatePolicy    = "update policy"
+	OpSetWatcher      = "set watcher"
+	OpNotifyWatcher   = "notify watcher"
+	OpClose           = "close enforcer"
+	OpSnapshot        = "save snapshot"
+	OpListSnapshots   = "list snapshots"
+	OpGetSnapshot     = "get snapshot"
+	OpDecodeSnapshot  = "decode snapshot"
+	OpRollback        = "rollback"
+)
+
+// Error 带操作名的错误，可以用 errors.Is / errors.As 判断原因
+//
+//	var e *enforcer.Error
+//	if errors.As(err, &e) && e.Op == enforcer.OpConnectDatabase { ... }
+//	if errors.Is(err, context.DeadlineExceeded) { ... }
+type Error struct {
+	Op  string // 出错的操作，见 Op* 常量
+	Err error  // 原始错误
+}
+
+func (e *Error) Error() string {
+	return e.Op + " failed: " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// opError 包装错误，err 为 nil 时返回 nil
+func opError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Err: err}
+}
+
+// invalidConfig 返回配置错误
+func invalidConfig(format string, args ...interface{}) error {
+	return &Error{Op: OpValidateConfig, Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...)}
+}
@@ -0,0 +1,151 @@
+package enforcer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// unhealthyAfterFailures 连续加载失败达到该次数后视为不健康
+const unhealthyAfterFailures = 3
+
+// HealthStatus 健康检查结果
+type HealthStatus struct {
+	Healthy             bool      `json:"healthy"`
+	AdapterReachable    bool      `json:"adapter_reachable"`
+	AdapterError        string    `json:"adapter_error,omitempty"`
+	LastLoadAt          time.Time `json:"last_load_at"`            // 最近一次加载（无论成功与否）
+	LastSuccessfulLoad  time.Time `json:"last_successful_load_at"` // 最近一次成功加载
+	LastLoadError       string    `json:"last_load_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Stale               bool      `json:"stale"` // 开启自动加载时，超过 3 个周期未成功加载
+}
+
+// loadState 记录策略加载状态，由 LoadPolicy 更新
+type loadState struct {
+	mu                  sync.Mutex
+	lastLoadAt          time.Time
+	lastSuccess         time.Time
+	lastError           error
+	consecutiveFailures int
+}
+
+func (s *loadState) recordLoad(at time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastLoadAt = at
+	s.lastError = err
+	if err != nil {
+		s.consecutiveFailures++
+		return
+	}
+	s.lastSuccess = at
+	s.consecutiveFailures = 0
+}
+
+// Health 检查存储连通性和策略加载状态
+func (e *Enforcer) Health(ctx context.Context) HealthStatus {
+	status := e.loadStatus()
+	status.AdapterReachable, status.AdapterError = pingDB(ctx, e.db, e.metrics)
+	status.Healthy = status.healthy()
+	return status
+}
+
+// HealthHandler 返回健康检查 HTTP 处理器，健康时返回 200，否则返回 503
+func (e *Enforcer) HealthHandler() http.Handler {
+	return healthHandler(e.Health)
+}
+
+// Health 检查存储连通性和所有已加载域的加载状态，任一域不健康时整体不健康
+// 最近加载时间取各域中最新的，最近成功加载时间取各域中最旧的，失败次数和错误取失败最多的域
+func (p *Pool) Health(ctx context.Context) HealthStatus {
+	status := HealthStatus{}
+	p.mu.Lock()
+	tenants := make([]*tenant, 0, p.lru.Len())
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		tenants = append(tenants, elem.Value.(*tenant))
+	}
+	p.mu.Unlock()
+
+	for i, t := range tenants {
+		s := t.enforcer.loadStatus()
+		if s.LastLoadAt.After(status.LastLoadAt) {
+			status.LastLoadAt = s.LastLoadAt
+		}
+		if i == 0 || s.LastSuccessfulLoad.Before(status.LastSuccessfulLoad) {
+			status.LastSuccessfulLoad = s.LastSuccessfulLoad
+		}
+		if s.ConsecutiveFailures > status.ConsecutiveFailures {
+			status.ConsecutiveFailures = s.ConsecutiveFailures
+			status.LastLoadError = t.domain + ": " + s.LastLoadError
+		}
+		status.Stale = status.Stale || s.Stale
+	}
+
+	status.AdapterReachable, status.AdapterError = pingDB(ctx, p.db, p.metrics)
+	status.Healthy = status.healthy()
+	return status
+}
+
+// HealthHandler 返回 Pool 的健康检查 HTTP 处理器，健康时返回 200，否则返回 503
+func (p *Pool) HealthHandler() http.Handler {
+	return healthHandler(p.Health)
+}
+
+// loadStatus 返回策略加载状态，不检查存储连通性
+func (e *Enforcer) loadStatus() HealthStatus {
+	status := HealthStatus{}
+
+	e.state.mu.Lock()
+	status.LastLoadAt = e.state.lastLoadAt
+	status.LastSuccessfulLoad = e.state.lastSuccess
+	status.ConsecutiveFailures = e.state.consecutiveFailures
+	if e.state.lastError != nil {
+		status.LastLoadError = e.state.lastError.Error()
+	}
+	e.state.mu.Unlock()
+
+	if e.config.AutoLoad && e.config.AutoLoadInterval > 0 {
+		maxAge := 3 * time.Duration(e.config.AutoLoadInterval) * time.Second
+		status.Stale = time.Since(status.LastSuccessfulLoad) > maxAge
+	}
+	return status
+}
+
+func (s HealthStatus) healthy() bool {
+	return s.AdapterReachable &&
+		s.ConsecutiveFailures < unhealthyAfterFailures &&
+		!s.Stale
+}
+
+// pingDB 检查存储是否可达并更新指标
+func pingDB(ctx context.Context, db *gorm.DB, metrics *Metrics) (bool, string) {
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	metrics.setAdapterReachable(err == nil)
+	if err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func healthHandler(health func(ctx context.Context) HealthStatus) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := health(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if status.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
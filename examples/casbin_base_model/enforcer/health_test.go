@@ -0,0 +1,84 @@
+package enforcer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHealth(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), &Config{MetricsRegisterer: prometheus.NewRegistry()})
+
+	status := e.Health(context.Background())
+	if !status.Healthy || !status.AdapterReachable || status.LastSuccessfulLoad.IsZero() {
+		t.Fatalf("status = %+v, want healthy", status)
+	}
+	if v := testutil.ToFloat64(e.metrics.adapterReachable); v != 1 {
+		t.Errorf("adapter_up = %v, want 1", v)
+	}
+
+	// 连续失败达到阈值后不健康，成功加载后恢复
+	for i := 0; i < unhealthyAfterFailures; i++ {
+		e.state.recordLoad(time.Now(), errors.New("connection refused"))
+	}
+	status = e.Health(context.Background())
+	if status.Healthy || status.ConsecutiveFailures != unhealthyAfterFailures || status.LastLoadError != "connection refused" {
+		t.Fatalf("status = %+v, want unhealthy after %d failures", status, unhealthyAfterFailures)
+	}
+	if err := e.LoadPolicy(); err != nil {
+		t.Fatal(err)
+	}
+	if status = e.Health(context.Background()); !status.Healthy || status.ConsecutiveFailures != 0 {
+		t.Errorf("status = %+v, want healthy after reload", status)
+	}
+}
+
+func TestHealthStale(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), &Config{AutoLoad: true, AutoLoadInterval: 60})
+
+	if status := e.Health(context.Background()); !status.Healthy || status.Stale {
+		t.Fatalf("status = %+v, want fresh", status)
+	}
+
+	e.state.mu.Lock()
+	e.state.lastSuccess = time.Now().Add(-4 * time.Minute)
+	e.state.mu.Unlock()
+	if status := e.Health(context.Background()); status.Healthy || !status.Stale {
+		t.Errorf("status = %+v, want stale after 3 intervals", status)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	db := openTestDB(t)
+	e := newTestEnforcer(t, db, &Config{MetricsRegisterer: prometheus.NewRegistry()})
+
+	get := func() (int, HealthStatus) {
+		rec := httptest.NewRecorder()
+		e.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var status HealthStatus
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, status
+	}
+
+	if code, _ := get(); code != http.StatusOK {
+		t.Fatalf("code = %d, want 200", code)
+	}
+
+	closeDB(db)
+	code, status := get()
+	if code != http.StatusServiceUnavailable || status.AdapterReachable || status.AdapterError == "" {
+		t.Errorf("code = %d, status = %+v, want 503 with adapter error", code, status)
+	}
+	if v := testutil.ToFloat64(e.metrics.adapterReachable); v != 0 {
+		t.Errorf("adapter_up = %v, want 0", v)
+	}
+}
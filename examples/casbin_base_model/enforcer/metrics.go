@@ -0,0 +1,149 @@
+package enforcer
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 权限检查结果标签
+const (
+	ResultAllow = "allow"
+	ResultDeny  = "deny"
+	ResultError = "error"
+)
+
+// Metrics enforcer 的 Prometheus 指标
+// 所有方法对 nil 接收者安全，未配置指标时不产生任何开销
+type Metrics struct {
+	enforceDuration  *prometheus.HistogramVec
+	decisions        *prometheus.CounterVec
+	reloadDuration   prometheus.Histogram
+	reloadErrors     prometheus.Counter
+	lockWait         *prometheus.HistogramVec
+	policyCount      *prometheus.GaugeVec
+	lastLoadSuccess  prometheus.Gauge
+	adapterReachable prometheus.Gauge
+}
+
+// NewMetrics 创建指标并注册到 reg，namespace 为空时使用 "casbin"
+func NewMetrics(reg prometheus.Registerer, namespace string) (*Metrics, error) {
+	if namespace == "" {
+		namespace = "casbin"
+	}
+
+	m := &Metrics{
+		enforceDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "enforce_duration_seconds",
+			Help:      "Enforce 耗时（不含锁等待）",
+			Buckets:   []float64{.00005, .0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		}, []string{"result"}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "enforce_decisions_total",
+			Help:      "按结果统计的权限检查次数（allow/deny/error）",
+		}, []string{"result"}),
+		reloadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "policy_reload_duration_seconds",
+			Help:      "策略加载耗时",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		reloadErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "policy_reload_errors_total",
+			Help:      "策略加载失败次数",
+		}),
+		lockWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "lock_wait_seconds",
+			Help:      "获取读写锁的等待时间",
+			Buckets:   []float64{.00001, .0001, .001, .01, .1, 1},
+		}, []string{"mode"}),
+		policyCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policies",
+			Help:      "当前加载的策略数量，ptype 为 p 或 g",
+		}, []string{"ptype"}),
+		lastLoadSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_last_successful_load_timestamp_seconds",
+			Help:      "最近一次成功加载策略的时间戳",
+		}),
+		adapterReachable: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "adapter_up",
+			Help:      "最近一次健康检查时存储是否可达（1 可达，0 不可达）",
+		}),
+	}
+
+	collectors := []prometheus.Collector{
+		m.enforceDuration, m.decisions, m.reloadDuration, m.reloadErrors,
+		m.lockWait, m.policyCount, m.lastLoadSuccess, m.adapterReachable,
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// observeEnforce 记录一次权限检查
+func (m *Metrics) observeEnforce(start time.Time, allowed bool, err error) {
+	if m == nil {
+		return
+	}
+	result := ResultDeny
+	switch {
+	case err != nil:
+		result = ResultError
+	case allowed:
+		result = ResultAllow
+	}
+	m.enforceDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	m.decisions.WithLabelValues(result).Inc()
+}
+
+// observeReload 记录一次策略加载
+func (m *Metrics) observeReload(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.reloadDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.reloadErrors.Inc()
+		return
+	}
+	m.lastLoadSuccess.Set(float64(time.Now().Unix()))
+}
+
+// observeLockWait 记录锁等待时间，mode 为 read 或 write
+func (m *Metrics) observeLockWait(mode string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.lockWait.WithLabelValues(mode).Observe(time.Since(start).Seconds())
+}
+
+// setPolicyCount 更新策略数量
+func (m *Metrics) setPolicyCount(policies, groupings int) {
+	if m == nil {
+		return
+	}
+	m.policyCount.WithLabelValues("p").Set(float64(policies))
+	m.policyCount.WithLabelValues("g").Set(float64(groupings))
+}
+
+// setAdapterReachable 更新存储可达状态
+func (m *Metrics) setAdapterReachable(ok bool) {
+	if m == nil {
+		return
+	}
+	if ok {
+		m.adapterReachable.Set(1)
+	} else {
+		m.adapterReachable.Set(0)
+	}
+}
@@ -0,0 +1,740 @@
+package enforcer
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+)
+
+// 默认最多同时加载的域数量
+const defaultMaxDomains = 100
+
+// PoolConfig 多租户 enforcer 池配置，模型必须带域（r、p 中有 dom 字段）
+type PoolConfig struct {
+	Config
+
+	// 最多同时加载的域数量，超过后淘汰最久未使用的域，默认 100
+	MaxDomains int
+	// 域空闲超过该时间后淘汰，为 0 表示只按数量淘汰
+	IdleTimeout time.Duration
+}
+
+// Pool 按域懒加载的 enforcer 池
+// 每个域一个只加载本域策略的 Enforcer，首次访问时加载，空闲或超出容量时淘汰，
+// 所有域共用一个数据库连接池。不带域的分组策略（如 g = _, _）会加载到每个域中，
+// 通过 Pool 变更时只写入一次数据库，再同步到所有已加载的域。
+type Pool struct {
+	config  *PoolConfig
+	db      *gorm.DB
+	model   model.Model
+	metrics *Metrics
+
+	// adapter 用于写入不区分域的分组策略，由 sharedMu 保护
+	adapter    *gormadapter.Adapter
+	adapterCtx *adapterContext
+	sharedMu   sync.Mutex
+
+	// 请求和规则中域字段的位置
+	requestDomain int
+	policyDomain  map[string]int // ptype -> 域字段位置，-1 表示不区分域
+
+	mu      sync.Mutex
+	lru     *list.List               // 元素为 *tenant，最近使用的在前
+	tenants map[string]*list.Element // 域 -> lru 元素
+	loading map[string]*loadCall
+	// sharedVersion 不区分域的分组策略每次变更后加一，加载期间有变更的域重新加载
+	sharedVersion uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed atomic.Bool
+}
+
+type tenant struct {
+	domain   string
+	enforcer *Enforcer
+	lastUsed time.Time
+}
+
+// loadCall 进行中的域加载，同一个域的并发请求共用一次加载
+type loadCall struct {
+	done     chan struct{}
+	enforcer *Enforcer
+	err      error
+}
+
+// NewPool 创建 enforcer 池
+func NewPool(config *PoolConfig) (*Pool, error) {
+	return NewPoolContext(context.Background(), config)
+}
+
+// NewPoolContext 创建 enforcer 池，ctx 和 Config.ConnectTimeout 限制连接数据库和建表的时间
+func NewPoolContext(ctx context.Context, config *PoolConfig) (*Pool, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.ConnectTimeout)
+	defer cancel()
+
+	db, err := openDB(ctx, &config.Config)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := newPool(ctx, db, config)
+	if err != nil {
+		closeDB(db)
+		return nil, err
+	}
+	return pool, nil
+}
+
+// validate 检查池配置并填充默认值
+func (c *PoolConfig) validate() error {
+	if err := c.Config.validate(); err != nil {
+		return err
+	}
+	if c.MaxDomains < 0 || c.IdleTimeout < 0 {
+		return invalidConfig("MaxDomains and IdleTimeout must not be negative")
+	}
+	if c.MaxDomains == 0 {
+		c.MaxDomains = defaultMaxDomains
+	}
+	return nil
+}
+
+func newPool(ctx context.Context, db *gorm.DB, config *PoolConfig) (*Pool, error) {
+	// 建表，之后的 adapter 都关闭自动迁移
+	if _, err := gormadapter.NewAdapterByDB(db.WithContext(ctx)); err != nil {
+		return nil, opError(OpCreateAdapter, err)
+	}
+	adapterCtx := &adapterContext{}
+	adapter, err := newAdapter(db, adapterCtx)
+	if err != nil {
+		return nil, opError(OpCreateAdapter, err)
+	}
+
+	m, err := model.NewModelFromFile(config.ModelPath)
+	if err != nil {
+		return nil, opError(OpCreateEnforcer, err)
+	}
+
+	pool := &Pool{
+		config:       config,
+		db:           db,
+		model:        m,
+		adapter:      adapter,
+		adapterCtx:   adapterCtx,
+		policyDomain: make(map[string]int),
+		lru:          list.New(),
+		tenants:      make(map[string]*list.Element),
+		loading:      make(map[string]*loadCall),
+	}
+	if err := pool.resolveDomainFields(); err != nil {
+		return nil, err
+	}
+	pool.ctx, pool.cancel = context.WithCancel(context.Background())
+
+	if config.MetricsRegisterer != nil {
+		pool.metrics, err = NewMetrics(config.MetricsRegisterer, config.MetricsNamespace)
+		if err != nil {
+			return nil, opError(OpRegisterMetrics, err)
+		}
+	}
+
+	// 其他实例变更策略时重新加载已加载的域
+	if config.Watcher != nil {
+		if err := config.Watcher.SetUpdateCallback(func(string) {
+			pool.reloadAll()
+		}); err != nil {
+			return nil, opError(OpSetWatcher, err)
+		}
+	}
+
+	if config.AutoLoad {
+		pool.wg.Add(1)
+		go pool.autoLoad()
+	}
+	if config.IdleTimeout > 0 {
+		pool.wg.Add(1)
+		go pool.evictIdle()
+	}
+	return pool, nil
+}
+
+// resolveDomainFields 根据模型确定请求和各类规则中域字段的位置
+func (p *Pool) resolveDomainFields() error {
+	p.requestDomain = -1
+	if ast, ok := p.model["r"]["r"]; ok {
+		p.requestDomain = indexOf(ast.Tokens, "r_dom")
+	}
+	if p.requestDomain < 0 {
+		return invalidConfig("model %s has no dom field in request_definition", p.config.ModelPath)
+	}
+
+	for ptype, ast := range p.model["p"] {
+		index := indexOf(ast.Tokens, ptype+"_dom")
+		if index < 0 {
+			return invalidConfig("model %s has no dom field in %s", p.config.ModelPath, ptype)
+		}
+		p.policyDomain[ptype] = index
+	}
+	for ptype, ast := range p.model["g"] {
+		// g = _, _, _ 第三个字段为域，g = _, _ 不区分域
+		p.policyDomain[ptype] = -1
+		if len(ast.Tokens) > 2 {
+			p.policyDomain[ptype] = 2
+		}
+	}
+
+	for ptype, index := range p.policyDomain {
+		if index > 5 {
+			return invalidConfig("dom field of %s is out of adapter columns", ptype)
+		}
+	}
+	return nil
+}
+
+// filter 返回只加载某个域策略的过滤条件
+func (p *Pool) filter(domain string) []gormadapter.Filter {
+	filters := make([]gormadapter.Filter, 0, len(p.policyDomain))
+	for ptype, index := range p.policyDomain {
+		f := gormadapter.Filter{Ptype: []string{ptype}}
+		if index >= 0 {
+			fields := []*[]string{&f.V0, &f.V1, &f.V2, &f.V3, &f.V4, &f.V5}
+			*fields[index] = []string{domain}
+		}
+		filters = append(filters, f)
+	}
+	return filters
+}
+
+// Enforce 按请求中的域路由权限检查
+func (p *Pool) Enforce(rvals ...interface{}) (bool, error) {
+	return p.EnforceContext(context.Background(), rvals...)
+}
+
+// EnforceContext 按请求中的域路由权限检查，域未加载时先加载
+func (p *Pool) EnforceContext(ctx context.Context, rvals ...interface{}) (bool, error) {
+	domain, err := domainArg(rvals, p.requestDomain)
+	if err != nil {
+		return false, opError(OpEnforce, err)
+	}
+	e, err := p.Domain(ctx, domain)
+	if err != nil {
+		return false, err
+	}
+	return e.EnforceContext(ctx, rvals...)
+}
+
+// AddPolicy 添加策略到规则所属的域
+func (p *Pool) AddPolicy(params ...interface{}) (bool, error) {
+	return p.AddPolicyContext(context.Background(), params...)
+}
+
+// AddPolicyContext 添加策略到规则所属的域
+func (p *Pool) AddPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	e, err := p.ruleDomain(ctx, "p", params)
+	if err != nil {
+		return false, err
+	}
+	return e.AddPolicyContext(ctx, params...)
+}
+
+// RemovePolicy 删除规则所属域中的策略
+func (p *Pool) RemovePolicy(params ...interface{}) (bool, error) {
+	return p.RemovePolicyContext(context.Background(), params...)
+}
+
+// RemovePolicyContext 删除规则所属域中的策略
+func (p *Pool) RemovePolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	e, err := p.ruleDomain(ctx, "p", params)
+	if err != nil {
+		return false, err
+	}
+	return e.RemovePolicyContext(ctx, params...)
+}
+
+// AddGroupingPolicy 在规则所属的域中添加角色继承关系
+func (p *Pool) AddGroupingPolicy(params ...interface{}) (bool, error) {
+	return p.AddGroupingPolicyContext(context.Background(), params...)
+}
+
+// AddGroupingPolicyContext 在规则所属的域中添加角色继承关系，g 不带域时添加到所有域
+func (p *Pool) AddGroupingPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	if p.sharedGrouping("g") {
+		return p.updateShared(ctx, "g", true, params)
+	}
+	e, err := p.ruleDomain(ctx, "g", params)
+	if err != nil {
+		return false, err
+	}
+	return e.AddGroupingPolicyContext(ctx, params...)
+}
+
+// RemoveGroupingPolicy 删除规则所属域中的角色继承关系
+func (p *Pool) RemoveGroupingPolicy(params ...interface{}) (bool, error) {
+	return p.RemoveGroupingPolicyContext(context.Background(), params...)
+}
+
+// RemoveGroupingPolicyContext 删除规则所属域中的角色继承关系，g 不带域时从所有域删除
+func (p *Pool) RemoveGroupingPolicyContext(ctx context.Context, params ...interface{}) (bool, error) {
+	if p.sharedGrouping("g") {
+		return p.updateShared(ctx, "g", false, params)
+	}
+	e, err := p.ruleDomain(ctx, "g", params)
+	if err != nil {
+		return false, err
+	}
+	return e.RemoveGroupingPolicyContext(ctx, params...)
+}
+
+// ruleDomain 返回规则所属域的 enforcer
+func (p *Pool) ruleDomain(ctx context.Context, ptype string, params []interface{}) (*Enforcer, error) {
+	index, ok := p.policyDomain[ptype]
+	if !ok || index < 0 {
+		return nil, opError(OpUpdatePolicy, fmt.Errorf("%s has no dom field", ptype))
+	}
+	domain, err := domainArg(params, index)
+	if err != nil {
+		return nil, opError(OpUpdatePolicy, err)
+	}
+	return p.Domain(ctx, domain)
+}
+
+// sharedGrouping 返回分组策略是否不区分域
+func (p *Pool) sharedGrouping(ptype string) bool {
+	index, ok := p.policyDomain[ptype]
+	return ok && index < 0
+}
+
+// updateShared 变更不区分域的分组策略：写入一次数据库，再同步到所有已加载的域
+// 同步失败（如 ctx 在等待某个域的锁时到期）的域会被淘汰，下次访问时重新加载
+func (p *Pool) updateShared(ctx context.Context, ptype string, add bool, params []interface{}) (bool, error) {
+	rule, err := ruleArgs(params)
+	if err == nil && len(rule) < 2 {
+		err = fmt.Errorf("expected at least 2 values, got %d", len(rule))
+	}
+	if err != nil {
+		return false, opError(OpUpdatePolicy, err)
+	}
+	if p.closed.Load() {
+		return false, opError(OpUpdatePolicy, ErrClosed)
+	}
+
+	p.sharedMu.Lock()
+	defer p.sharedMu.Unlock()
+
+	// 表名取自 gormadapter.CasbinRule，与 adapter 保持一致
+	line, err := toCasbinRule(append([]string{ptype}, rule...))
+	if err != nil {
+		return false, opError(OpUpdatePolicy, err)
+	}
+	var count int64
+	err = p.db.WithContext(ctx).Model(&gormadapter.CasbinRule{}).
+		Where(&line, "Ptype", "V0", "V1", "V2", "V3", "V4", "V5").Count(&count).Error
+	if err != nil {
+		return false, opError(OpUpdatePolicy, err)
+	}
+	if (count > 0) == add {
+		return false, nil
+	}
+
+	p.adapterCtx.set(ctx)
+	if add {
+		err = p.adapter.AddPolicy("g", ptype, rule)
+	} else {
+		err = p.adapter.RemovePolicy("g", ptype, rule)
+	}
+	p.adapterCtx.set(context.Background())
+	if err != nil {
+		return false, opError(OpUpdatePolicy, err)
+	}
+
+	p.mu.Lock()
+	p.sharedVersion++
+	tenants := make([]*tenant, 0, p.lru.Len())
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		tenants = append(tenants, elem.Value.(*tenant))
+	}
+	p.mu.Unlock()
+
+	for _, t := range tenants {
+		err := t.enforcer.updateLocal(ctx, func(ce *casbin.Enforcer) (bool, error) {
+			if add {
+				return ce.AddNamedGroupingPolicy(ptype, rule)
+			}
+			return ce.RemoveNamedGroupingPolicy(ptype, rule)
+		})
+		if err != nil {
+			p.evictEnforcer(t.domain, t.enforcer)
+		}
+	}
+
+	if w := p.config.Watcher; w != nil {
+		if err := w.Update(); err != nil {
+			return true, opError(OpNotifyWatcher, err)
+		}
+	}
+	return true, nil
+}
+
+// Domain 返回某个域的 enforcer，未加载时从数据库加载该域的策略
+// 返回的 enforcer 只包含本域的策略，不能对其调用 SavePolicy、CreateSnapshot 和 Rollback
+func (p *Pool) Domain(ctx context.Context, domain string) (*Enforcer, error) {
+	p.mu.Lock()
+	if p.closed.Load() {
+		p.mu.Unlock()
+		return nil, opError(OpLoadPolicy, ErrClosed)
+	}
+	if elem, ok := p.tenants[domain]; ok {
+		t := elem.Value.(*tenant)
+		t.lastUsed = time.Now()
+		p.lru.MoveToFront(elem)
+		p.mu.Unlock()
+		return t.enforcer, nil
+	}
+
+	call, ok := p.loading[domain]
+	if !ok {
+		call = &loadCall{done: make(chan struct{})}
+		p.loading[domain] = call
+		// 加载不受单个请求取消的影响，其他等待的请求仍可使用结果
+		go p.load(domain, call)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.enforcer, call.err
+	case <-ctx.Done():
+		return nil, opError(OpLoadPolicy, ctx.Err())
+	}
+}
+
+// load 加载一个域并放入 LRU
+func (p *Pool) load(domain string, call *loadCall) {
+	ctx, cancel := p.ctx, context.CancelFunc(func() {})
+	if p.config.LoadTimeout > 0 {
+		ctx, cancel = context.WithTimeout(p.ctx, p.config.LoadTimeout)
+	}
+	defer cancel()
+
+	p.mu.Lock()
+	version := p.sharedVersion
+	p.mu.Unlock()
+
+	call.enforcer, call.err = p.newDomainEnforcer(ctx, domain)
+
+	p.mu.Lock()
+	// 加载期间不区分域的分组策略有变更时重新加载，避免漏掉同步
+	for call.err == nil && version != p.sharedVersion {
+		version = p.sharedVersion
+		p.mu.Unlock()
+		call.err = call.enforcer.LoadPolicyContext(ctx)
+		p.mu.Lock()
+	}
+	delete(p.loading, domain)
+	if call.err == nil && !p.closed.Load() {
+		elem := p.lru.PushFront(&tenant{domain: domain, enforcer: call.enforcer, lastUsed: time.Now()})
+		p.tenants[domain] = elem
+		for p.lru.Len() > p.config.MaxDomains {
+			p.removeElement(p.lru.Back())
+		}
+		p.updatePolicyCountLocked()
+	} else if call.enforcer != nil {
+		// 池已关闭或重新加载失败，丢弃该 enforcer
+		call.enforcer.cancel()
+		call.enforcer = nil
+		if call.err == nil {
+			call.err = opError(OpLoadPolicy, ErrClosed)
+		}
+	}
+	p.mu.Unlock()
+	close(call.done)
+}
+
+// newDomainEnforcer 创建只加载一个域策略的 enforcer
+func (p *Pool) newDomainEnforcer(ctx context.Context, domain string) (*Enforcer, error) {
+	ce, err := casbin.NewEnforcer(p.model.Copy())
+	if err != nil {
+		return nil, opError(OpCreateEnforcer, err)
+	}
+	// 每个域的 enforcer 独立加锁，各自持有 adapter 以便切换查询的 ctx
+	adapterCtx := &adapterContext{}
+	adapter, err := newAdapter(p.db, adapterCtx)
+	if err != nil {
+		return nil, opError(OpCreateAdapter, err)
+	}
+	ce.SetAdapter(adapter)
+
+	e := &Enforcer{
+		enforcer:   ce,
+		adapter:    adapter,
+		adapterCtx: adapterCtx,
+		db:         p.db,
+		config:     &p.config.Config,
+		metrics:    p.metrics,
+		filter:     p.filter(domain),
+		pool:       p,
+		shared:     true,
+	}
+	e.ctx, e.cancel = context.WithCancel(p.ctx)
+
+	if err := e.LoadPolicyContext(ctx); err != nil {
+		e.cancel()
+		return nil, err
+	}
+	return e, nil
+}
+
+// updateLocal 只变更内存中的策略，不写数据库也不通知 Watcher，用于同步 Pool 已写入的规则
+func (e *Enforcer) updateLocal(ctx context.Context, fn func(ce *casbin.Enforcer) (bool, error)) error {
+	if err := e.lockContext(ctx); err != nil {
+		return opError(OpUpdatePolicy, err)
+	}
+	defer e.mu.Unlock()
+	defer e.updatePolicyCount()
+
+	e.enforcer.EnableAutoSave(false)
+	e.enforcer.EnableAutoNotifyWatcher(false)
+	defer e.enforcer.EnableAutoSave(true)
+	defer e.enforcer.EnableAutoNotifyWatcher(true)
+
+	_, err := fn(e.enforcer)
+	return opError(OpUpdatePolicy, err)
+}
+
+// Evict 淘汰一个域，下次访问时重新加载；返回该域是否已加载
+func (p *Pool) Evict(domain string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elem, ok := p.tenants[domain]
+	if ok {
+		p.removeElement(elem)
+	}
+	return ok
+}
+
+// evictEnforcer 淘汰仍在使用 e 的域，域已被淘汰或重新加载时不做处理
+func (p *Pool) evictEnforcer(domain string, e *Enforcer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.tenants[domain]; ok && elem.Value.(*tenant).enforcer == e {
+		p.removeElement(elem)
+	}
+}
+
+// Domains 返回已加载的域，最近使用的在前
+func (p *Pool) Domains() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	domains := make([]string, 0, p.lru.Len())
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		domains = append(domains, elem.Value.(*tenant).domain)
+	}
+	return domains
+}
+
+// removeElement 从 LRU 中移除，调用方需持有 p.mu
+// 被淘汰的 enforcer 不会关闭，仍持有它的调用方可以继续使用
+func (p *Pool) removeElement(elem *list.Element) {
+	t := p.lru.Remove(elem).(*tenant)
+	delete(p.tenants, t.domain)
+	t.enforcer.cancel()
+	p.updatePolicyCountLocked()
+}
+
+// updatePolicyCount 汇总已加载域的策略数量更新指标，不区分域的分组策略在每个域各计一次
+func (p *Pool) updatePolicyCount() {
+	if p.metrics == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updatePolicyCountLocked()
+}
+
+// updatePolicyCountLocked 同 updatePolicyCount，调用方需持有 p.mu
+func (p *Pool) updatePolicyCountLocked() {
+	if p.metrics == nil {
+		return
+	}
+	var policies, groupings int64
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*tenant).enforcer
+		policies += e.policyCount.Load()
+		groupings += e.groupingCount.Load()
+	}
+	p.metrics.setPolicyCount(int(policies), int(groupings))
+}
+
+// loaded 返回已加载的 enforcer
+func (p *Pool) loaded() []*Enforcer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	enforcers := make([]*Enforcer, 0, p.lru.Len())
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		enforcers = append(enforcers, elem.Value.(*tenant).enforcer)
+	}
+	return enforcers
+}
+
+// reloadAll 重新加载所有已加载的域
+func (p *Pool) reloadAll() {
+	for _, e := range p.loaded() {
+		if err := e.reload(); err != nil && !p.closed.Load() {
+			p.config.Logger.Printf("Reload domain policy failed: %v", err)
+		}
+	}
+}
+
+// autoLoad 定期重新加载已加载的域，Close 时退出
+func (p *Pool) autoLoad() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(p.config.AutoLoadInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.reloadAll()
+		}
+	}
+}
+
+// evictIdle 定期淘汰空闲超过 IdleTimeout 的域，Close 时退出
+func (p *Pool) evictIdle() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.mu.Lock()
+			for elem := p.lru.Back(); elem != nil; {
+				t := elem.Value.(*tenant)
+				if now.Sub(t.lastUsed) < p.config.IdleTimeout {
+					break
+				}
+				prev := elem.Prev()
+				p.removeElement(elem)
+				elem = prev
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// Close 停止后台任务，等待各个域进行中的操作结束后关闭 Watcher 和数据库连接
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed.CompareAndSwap(false, true) {
+		p.mu.Unlock()
+		return nil
+	}
+	enforcers := make([]*Enforcer, 0, p.lru.Len())
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		enforcers = append(enforcers, elem.Value.(*tenant).enforcer)
+	}
+	p.mu.Unlock()
+	p.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return opError(OpClose, ctx.Err())
+	}
+
+	for _, e := range enforcers {
+		if err := e.Close(ctx); err != nil {
+			return err
+		}
+	}
+
+	if p.config.Watcher != nil {
+		p.config.Watcher.Close()
+	}
+	return opError(OpClose, closeDB(p.db))
+}
+
+// notifyOnly 只转发 Update 的 Watcher，避免每个域的 casbin enforcer 覆盖 Pool 注册的回调
+type notifyOnly struct {
+	persist.Watcher
+}
+
+func (notifyOnly) SetUpdateCallback(func(string)) error { return nil }
+
+func (notifyOnly) Close() {}
+
+// ruleArgs 把规则参数转换为 []string，参数也可以是一个 []string
+func ruleArgs(params []interface{}) ([]string, error) {
+	if len(params) == 1 {
+		if rule, ok := params[0].([]string); ok {
+			return rule, nil
+		}
+	}
+	rule := make([]string, len(params))
+	for i, v := range params {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("rule value at position %d must be a string, got %v", i, v)
+		}
+		rule[i] = s
+	}
+	return rule, nil
+}
+
+// domainArg 取出参数中的域，参数也可以是一个 []string
+func domainArg(params []interface{}, index int) (string, error) {
+	if len(params) == 1 {
+		if rule, ok := params[0].([]string); ok {
+			params = make([]interface{}, len(rule))
+			for i, v := range rule {
+				params[i] = v
+			}
+		}
+	}
+	if index >= len(params) {
+		return "", fmt.Errorf("expected dom at position %d, got %d values", index, len(params))
+	}
+	domain, ok := params[index].(string)
+	if !ok || domain == "" {
+		return "", fmt.Errorf("dom at position %d must be a non-empty string, got %v", index, params[index])
+	}
+	return domain, nil
+}
+
+func indexOf(tokens []string, token string) int {
+	for i, t := range tokens {
+		if t == token {
+			return i
+		}
+	}
+	return -1
+}
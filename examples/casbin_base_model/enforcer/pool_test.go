@@ -0,0 +1,413 @@
+package enforcer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// sharedGroupingModel g 不带域，分组策略对所有域生效
+const sharedGroupingModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`
+
+// newTestPool 基于 sqlite 文件创建 Pool，ModelPath 为空时使用 rbac_with_domains.conf
+func newTestPool(t *testing.T, config *PoolConfig) *Pool {
+	t.Helper()
+	config.DBType = "sqlite"
+	config.DBConnection = filepath.Join(t.TempDir(), "casbin.db")
+	config.MaxOpenConns = 1
+	if config.ModelPath == "" {
+		config.ModelPath = testModelPath
+	}
+
+	pool, err := NewPool(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pool.Close(context.Background()) })
+	return pool
+}
+
+// writeModel 把模型写入临时文件并返回路径
+func writeModel(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model.conf")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// seedRules 直接写入数据库，不经过任何 enforcer
+func seedRules(t *testing.T, p *Pool, rules ...[]string) {
+	t.Helper()
+	lines := make([]gormadapter.CasbinRule, len(rules))
+	for i, rule := range rules {
+		line, err := toCasbinRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines[i] = line
+	}
+	if err := p.db.Create(&lines).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustDomain(t *testing.T, p *Pool, domain string) *Enforcer {
+	t.Helper()
+	e, err := p.Domain(context.Background(), domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestPoolEnforce(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{})
+	seedRules(t, p,
+		[]string{"p", "admin", "domain1", "/data", "read"},
+		[]string{"p", "admin", "domain2", "/data", "write"},
+		[]string{"g", "alice", "admin", "domain1"},
+	)
+
+	tests := []struct {
+		rvals []interface{}
+		want  bool
+	}{
+		{[]interface{}{"alice", "domain1", "/data", "read"}, true},
+		{[]interface{}{"alice", "domain2", "/data", "write"}, false},
+		{[]interface{}{"admin", "domain2", "/data", "write"}, true},
+	}
+	for _, tt := range tests {
+		got, err := p.Enforce(tt.rvals...)
+		if err != nil || got != tt.want {
+			t.Errorf("Enforce(%v) = %v, %v, want %v", tt.rvals, got, err, tt.want)
+		}
+	}
+
+	if got, want := p.Domains(), []string{"domain2", "domain1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Domains() = %v, want %v", got, want)
+	}
+	if got := mustDomain(t, p, "domain1").enforcer.GetPolicy(); len(got) != 1 {
+		t.Errorf("domain1 policies = %v, want only its own rule", got)
+	}
+
+	if _, err := p.Enforce("alice", "", "/data", "read"); err == nil {
+		t.Error("空域应返回错误")
+	}
+}
+
+func TestPoolAddPolicyRoutesByDomain(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{})
+
+	if ok, err := p.AddPolicy("admin", "domain1", "/data", "read"); err != nil || !ok {
+		t.Fatalf("AddPolicy = %v, %v", ok, err)
+	}
+	if ok, err := p.AddGroupingPolicy("alice", "admin", "domain1"); err != nil || !ok {
+		t.Fatalf("AddGroupingPolicy = %v, %v", ok, err)
+	}
+	if ok, _ := p.Enforce("alice", "domain1", "/data", "read"); !ok {
+		t.Error("alice 应能读取 domain1 的 /data")
+	}
+
+	// 淘汰后从数据库重新加载，规则仍然存在
+	p.Evict("domain1")
+	if ok, _ := p.Enforce("alice", "domain1", "/data", "read"); !ok {
+		t.Error("重新加载后 alice 应能读取 domain1 的 /data")
+	}
+	if ok, err := p.RemoveGroupingPolicy("alice", "admin", "domain1"); err != nil || !ok {
+		t.Fatalf("RemoveGroupingPolicy = %v, %v", ok, err)
+	}
+	if ok, _ := p.Enforce("alice", "domain1", "/data", "read"); ok {
+		t.Error("删除分组后 alice 不应能读取")
+	}
+}
+
+func TestPoolSharedGrouping(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{Config: Config{ModelPath: writeModel(t, sharedGroupingModel)}})
+	seedRules(t, p,
+		[]string{"p", "admin", "domain1", "/data", "read"},
+		[]string{"p", "admin", "domain2", "/data", "read"},
+	)
+	mustDomain(t, p, "domain1")
+	mustDomain(t, p, "domain2")
+
+	if ok, err := p.AddGroupingPolicy("alice", "admin"); err != nil || !ok {
+		t.Fatalf("AddGroupingPolicy = %v, %v", ok, err)
+	}
+	if ok, err := p.AddGroupingPolicy("alice", "admin"); err != nil || ok {
+		t.Fatalf("重复 AddGroupingPolicy = %v, %v, want false", ok, err)
+	}
+
+	var count int64
+	p.db.Model(&gormadapter.CasbinRule{}).Where("ptype = ?", "g").Count(&count)
+	if count != 1 {
+		t.Fatalf("stored g rules = %d, want 1", count)
+	}
+
+	// 已加载和之后加载的域都能看到该分组
+	seedRules(t, p, []string{"p", "admin", "domain3", "/data", "read"})
+	for _, domain := range []string{"domain1", "domain2", "domain3"} {
+		if ok, err := p.Enforce("alice", domain, "/data", "read"); err != nil || !ok {
+			t.Errorf("Enforce(alice, %s) = %v, %v, want true", domain, ok, err)
+		}
+	}
+
+	if ok, err := p.RemoveGroupingPolicy("alice", "admin"); err != nil || !ok {
+		t.Fatalf("RemoveGroupingPolicy = %v, %v", ok, err)
+	}
+	for _, domain := range []string{"domain1", "domain2", "domain3"} {
+		if ok, _ := p.Enforce("alice", domain, "/data", "read"); ok {
+			t.Errorf("删除分组后 Enforce(alice, %s) = true", domain)
+		}
+	}
+	p.db.Model(&gormadapter.CasbinRule{}).Where("ptype = ?", "g").Count(&count)
+	if count != 0 {
+		t.Errorf("stored g rules = %d, want 0", count)
+	}
+
+	if _, err := p.AddGroupingPolicy("alice"); err == nil {
+		t.Error("参数不足时应返回错误")
+	}
+}
+
+func TestPoolSharedGroupingDuringLoad(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{Config: Config{ModelPath: writeModel(t, sharedGroupingModel)}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := p.Domain(context.Background(), fmt.Sprintf("domain%d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	if _, err := p.AddGroupingPolicy("alice", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+
+	// 与加载并发的变更不会被任何域漏掉
+	for _, domain := range p.Domains() {
+		if !mustDomain(t, p, domain).enforcer.HasGroupingPolicy("alice", "admin") {
+			t.Errorf("%s 缺少 alice -> admin", domain)
+		}
+	}
+}
+
+func TestPoolConcurrentDomainLoad(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{})
+	seedRules(t, p, []string{"p", "admin", "domain1", "/data", "read"})
+
+	const n = 50
+	enforcers := make([]*Enforcer, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e, err := p.Domain(context.Background(), "domain1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			enforcers[i] = e
+		}(i)
+	}
+	wg.Wait()
+
+	// 同一个域的并发请求共用一次加载
+	for i, e := range enforcers {
+		if e != enforcers[0] {
+			t.Fatalf("enforcers[%d] differs from enforcers[0]", i)
+		}
+	}
+}
+
+func TestPoolConcurrentEviction(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{MaxDomains: 3})
+	for i := 0; i < 10; i++ {
+		seedRules(t, p, []string{"p", "admin", fmt.Sprintf("domain%d", i), "/data", "read"})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			domain := fmt.Sprintf("domain%d", i%10)
+			// 被淘汰的 enforcer 仍可被已经拿到它的调用方使用
+			ok, err := p.Enforce("admin", domain, "/data", "read")
+			if err != nil || !ok {
+				t.Errorf("Enforce(%s) = %v, %v", domain, ok, err)
+			}
+			if i%7 == 0 {
+				p.Evict(domain)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := len(p.Domains()); n > 3 {
+		t.Errorf("loaded domains = %d, want at most 3", n)
+	}
+}
+
+func TestPoolEviction(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{MaxDomains: 2})
+
+	d1 := mustDomain(t, p, "domain1")
+	mustDomain(t, p, "domain2")
+	mustDomain(t, p, "domain1") // domain1 最近使用
+	mustDomain(t, p, "domain3")
+
+	if got, want := p.Domains(), []string{"domain3", "domain1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Domains() = %v, want %v", got, want)
+	}
+	if !p.Evict("domain1") || p.Evict("domain1") {
+		t.Error("Evict 应只在域已加载时返回 true")
+	}
+	// 淘汰后停止后台加载，但仍可使用
+	if d1.ctx.Err() == nil {
+		t.Error("被淘汰的 enforcer 未取消")
+	}
+	if _, err := d1.Enforce("alice", "domain1", "/data", "read"); err != nil {
+		t.Errorf("被淘汰的 enforcer Enforce = %v", err)
+	}
+}
+
+func TestPoolIdleEviction(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{IdleTimeout: 20 * time.Millisecond})
+	mustDomain(t, p, "domain1")
+
+	deadline := time.Now().Add(time.Second)
+	for len(p.Domains()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("空闲的域未被淘汰")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPoolPolicyCountMetric(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := newTestPool(t, &PoolConfig{Config: Config{MetricsRegisterer: reg}})
+	seedRules(t, p,
+		[]string{"p", "admin", "domain1", "/a", "read"},
+		[]string{"p", "admin", "domain1", "/b", "read"},
+		[]string{"p", "admin", "domain2", "/a", "read"},
+		[]string{"g", "alice", "admin", "domain2"},
+	)
+	policies := func() (float64, float64) {
+		return testutil.ToFloat64(p.metrics.policyCount.WithLabelValues("p")),
+			testutil.ToFloat64(p.metrics.policyCount.WithLabelValues("g"))
+	}
+
+	mustDomain(t, p, "domain1")
+	mustDomain(t, p, "domain2")
+	if pc, gc := policies(); pc != 3 || gc != 1 {
+		t.Fatalf("policies = p:%v g:%v, want p:3 g:1", pc, gc)
+	}
+
+	if _, err := p.AddPolicy("admin", "domain2", "/b", "read"); err != nil {
+		t.Fatal(err)
+	}
+	if pc, _ := policies(); pc != 4 {
+		t.Errorf("policies after AddPolicy = %v, want 4", pc)
+	}
+
+	p.Evict("domain1")
+	if pc, gc := policies(); pc != 2 || gc != 1 {
+		t.Errorf("policies after Evict = p:%v g:%v, want p:2 g:1", pc, gc)
+	}
+}
+
+func TestPoolHealth(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{})
+	if status := p.Health(context.Background()); !status.Healthy {
+		t.Fatalf("未加载任何域时 Health = %+v, want healthy", status)
+	}
+
+	d1 := mustDomain(t, p, "domain1")
+	mustDomain(t, p, "domain2")
+	if status := p.Health(context.Background()); !status.Healthy || status.LastSuccessfulLoad.IsZero() {
+		t.Fatalf("Health = %+v, want healthy", status)
+	}
+
+	if err := p.db.Migrator().DropTable("casbin_rule"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < unhealthyAfterFailures; i++ {
+		d1.LoadPolicy()
+	}
+
+	status := p.Health(context.Background())
+	if status.Healthy || status.ConsecutiveFailures != unhealthyAfterFailures {
+		t.Errorf("Health = %+v, want unhealthy with %d failures", status, unhealthyAfterFailures)
+	}
+	if !strings.HasPrefix(status.LastLoadError, "domain1: ") {
+		t.Errorf("LastLoadError = %q, want domain1 prefix", status.LastLoadError)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	p := newTestPool(t, &PoolConfig{})
+	e := mustDomain(t, p, "domain1")
+
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("second Close = %v, want nil", err)
+	}
+	if _, err := p.Domain(context.Background(), "domain2"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Domain after Close = %v, want ErrClosed", err)
+	}
+	if _, err := e.Enforce("alice", "domain1", "/data", "read"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Enforce after Close = %v, want ErrClosed", err)
+	}
+	if _, err := p.AddGroupingPolicy("alice", "admin", "domain1"); !errors.Is(err, ErrClosed) {
+		t.Errorf("AddGroupingPolicy after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestConfigDBType(t *testing.T) {
+	for _, dbType := range []string{"", "mysql", "postgres", "sqlite"} {
+		config := &Config{DBType: dbType, DBConnection: "dsn", ModelPath: testModelPath}
+		if err := config.validate(); err != nil {
+			t.Errorf("DBType %q: %v", dbType, err)
+		}
+	}
+	config := &Config{DBType: "oracle", DBConnection: "dsn", ModelPath: testModelPath}
+	if err := config.validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("DBType oracle: %v, want ErrInvalidConfig", err)
+	}
+}
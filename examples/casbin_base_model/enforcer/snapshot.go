@@ -0,0 +1,339 @@
+package enforcer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+)
+
+// PolicySnapshot 策略快照，保存某一时刻的完整策略集
+type PolicySnapshot struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Author    string    `json:"author" gorm:"size:100"`
+	Comment   string    `json:"comment" gorm:"size:500"`
+	RuleCount int       `json:"rule_count"`
+	Checksum  string    `json:"checksum" gorm:"size:64;index"`
+	Rules     string    `json:"-" gorm:"type:text"` // JSON 编码的规则，每条规则第一个字段为 ptype
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 快照表名
+func (PolicySnapshot) TableName() string {
+	return "casbin_policy_snapshot"
+}
+
+// PolicyRules 解析快照中的规则
+func (s *PolicySnapshot) PolicyRules() ([][]string, error) {
+	var rules [][]string
+	if err := json.Unmarshal([]byte(s.Rules), &rules); err != nil {
+		return nil, opError(OpDecodeSnapshot, fmt.Errorf("snapshot %d: %w", s.ID, err))
+	}
+	return rules, nil
+}
+
+// PolicyDiff 两个策略集之间的差异，规则第一个字段为 ptype
+type PolicyDiff struct {
+	Added   [][]string `json:"added"`
+	Removed [][]string `json:"removed"`
+}
+
+// Empty 是否没有差异
+func (d *PolicyDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// CreateSnapshot 保存当前内存中的完整策略集
+func (e *Enforcer) CreateSnapshot(author, comment string) (*PolicySnapshot, error) {
+	return e.CreateSnapshotContext(context.Background(), author, comment)
+}
+
+// CreateSnapshotContext 保存当前内存中的完整策略集
+func (e *Enforcer) CreateSnapshotContext(ctx context.Context, author, comment string) (*PolicySnapshot, error) {
+	if e.filter != nil {
+		return nil, opError(OpSnapshot, ErrFilteredPolicy)
+	}
+	if err := e.rlockContext(ctx); err != nil {
+		return nil, opError(OpSnapshot, err)
+	}
+	rules := e.currentRules()
+	e.mu.RUnlock()
+
+	return e.saveSnapshot(e.db.WithContext(ctx), rules, author, comment)
+}
+
+// ListSnapshots 按时间倒序列出快照，limit <= 0 表示不限制
+func (e *Enforcer) ListSnapshots(limit int) ([]PolicySnapshot, error) {
+	return e.ListSnapshotsContext(context.Background(), limit)
+}
+
+// ListSnapshotsContext 按时间倒序列出快照
+func (e *Enforcer) ListSnapshotsContext(ctx context.Context, limit int) ([]PolicySnapshot, error) {
+	if e.closed.Load() {
+		return nil, opError(OpListSnapshots, ErrClosed)
+	}
+
+	var snapshots []PolicySnapshot
+	query := e.db.WithContext(ctx).Omit("rules").Order("id desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&snapshots).Error; err != nil {
+		return nil, opError(OpListSnapshots, err)
+	}
+	return snapshots, nil
+}
+
+// GetSnapshot 获取快照，不存在时返回 ErrSnapshotNotFound
+func (e *Enforcer) GetSnapshot(id uint) (*PolicySnapshot, error) {
+	return e.GetSnapshotContext(context.Background(), id)
+}
+
+// GetSnapshotContext 获取快照
+func (e *Enforcer) GetSnapshotContext(ctx context.Context, id uint) (*PolicySnapshot, error) {
+	if e.closed.Load() {
+		return nil, opError(OpGetSnapshot, ErrClosed)
+	}
+
+	var snapshot PolicySnapshot
+	if err := e.db.WithContext(ctx).First(&snapshot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrSnapshotNotFound
+		}
+		return nil, opError(OpGetSnapshot, fmt.Errorf("snapshot %d: %w", id, err))
+	}
+	return &snapshot, nil
+}
+
+// DiffSnapshots 比较两个快照，返回从 fromID 到 toID 新增和删除的规则
+func (e *Enforcer) DiffSnapshots(fromID, toID uint) (*PolicyDiff, error) {
+	from, err := e.snapshotRules(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := e.snapshotRules(toID)
+	if err != nil {
+		return nil, err
+	}
+	return diffRules(from, to), nil
+}
+
+// DiffWithCurrent 比较快照和当前策略，返回从快照到当前新增和删除的规则
+func (e *Enforcer) DiffWithCurrent(id uint) (*PolicyDiff, error) {
+	from, err := e.snapshotRules(id)
+	if err != nil {
+		return nil, err
+	}
+
+	e.rlock()
+	current := e.currentRules()
+	e.mu.RUnlock()
+
+	return diffRules(from, current), nil
+}
+
+// Rollback 将策略恢复到指定快照
+// 回滚前会在同一事务内备份数据库中的当前策略（包括其他实例写入、本实例尚未加载的规则），然后替换策略，
+// 内存中的策略在持有写锁期间重新加载，其他实例通过 Watcher 收到通知
+func (e *Enforcer) Rollback(id uint, author string) (*PolicySnapshot, error) {
+	return e.RollbackContext(context.Background(), id, author)
+}
+
+// RollbackContext 将策略恢复到指定快照，ctx 取消时事务回滚，内存中的策略保持不变
+func (e *Enforcer) RollbackContext(ctx context.Context, id uint, author string) (*PolicySnapshot, error) {
+	if e.filter != nil {
+		return nil, opError(OpRollback, ErrFilteredPolicy)
+	}
+	target, err := e.GetSnapshotContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := target.PolicyRules()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.lockContext(ctx); err != nil {
+		return nil, opError(OpRollback, err)
+	}
+	defer e.mu.Unlock()
+
+	var backup *PolicySnapshot
+	err = e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := storedRules(tx)
+		if err != nil {
+			return err
+		}
+		backup, err = e.saveSnapshot(tx, current, author, fmt.Sprintf("回滚到快照 %d 前自动备份", id))
+		if err != nil {
+			return err
+		}
+
+		// 表名取自 gormadapter.CasbinRule，与 adapter 保持一致
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&gormadapter.CasbinRule{}).Error; err != nil {
+			return fmt.Errorf("clear policies: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+
+		lines := make([]gormadapter.CasbinRule, 0, len(rules))
+		for _, rule := range rules {
+			line, err := toCasbinRule(rule)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+		if err := tx.CreateInBatches(lines, 500).Error; err != nil {
+			return fmt.Errorf("restore policies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, opError(OpRollback, fmt.Errorf("snapshot %d: %w", id, err))
+	}
+
+	start := time.Now()
+	err = e.withAdapterContext(ctx, e.enforcer.LoadPolicy)
+	e.metrics.observeReload(start, err)
+	e.state.recordLoad(start, err)
+	if err != nil {
+		return nil, opError(OpLoadPolicy, err)
+	}
+	e.updatePolicyCount()
+
+	if w := e.config.Watcher; w != nil {
+		if err := w.Update(); err != nil {
+			return backup, opError(OpNotifyWatcher, err)
+		}
+	}
+	return backup, nil
+}
+
+// currentRules 返回内存中的全部规则，调用方需持有锁
+func (e *Enforcer) currentRules() [][]string {
+	model := e.enforcer.GetModel()
+	rules := make([][]string, 0)
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range model[sec] {
+			for _, rule := range ast.Policy {
+				rules = append(rules, append([]string{ptype}, rule...))
+			}
+		}
+	}
+	sortRules(rules)
+	return rules
+}
+
+// storedRules 返回数据库中的全部规则，格式与 currentRules 相同
+func storedRules(db *gorm.DB) ([][]string, error) {
+	var lines []gormadapter.CasbinRule
+	if err := db.Order("id").Find(&lines).Error; err != nil {
+		return nil, fmt.Errorf("read policies: %w", err)
+	}
+	rules := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		rules = append(rules, fromCasbinRule(line))
+	}
+	sortRules(rules)
+	return rules, nil
+}
+
+func (e *Enforcer) saveSnapshot(db *gorm.DB, rules [][]string, author, comment string) (*PolicySnapshot, error) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, opError(OpSnapshot, err)
+	}
+	sum := sha256.Sum256(data)
+
+	snapshot := &PolicySnapshot{
+		Author:    author,
+		Comment:   comment,
+		RuleCount: len(rules),
+		Checksum:  hex.EncodeToString(sum[:]),
+		Rules:     string(data),
+	}
+	if err := db.Create(snapshot).Error; err != nil {
+		return nil, opError(OpSnapshot, err)
+	}
+	return snapshot, nil
+}
+
+func (e *Enforcer) snapshotRules(id uint) ([][]string, error) {
+	snapshot, err := e.GetSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.PolicyRules()
+}
+
+func diffRules(from, to [][]string) *PolicyDiff {
+	key := func(rule []string) string {
+		return strings.Join(rule, "\x00")
+	}
+	inFrom := make(map[string]bool, len(from))
+	for _, rule := range from {
+		inFrom[key(rule)] = true
+	}
+	inTo := make(map[string]bool, len(to))
+	for _, rule := range to {
+		inTo[key(rule)] = true
+	}
+
+	diff := &PolicyDiff{Added: [][]string{}, Removed: [][]string{}}
+	for _, rule := range to {
+		if !inFrom[key(rule)] {
+			diff.Added = append(diff.Added, rule)
+		}
+	}
+	for _, rule := range from {
+		if !inTo[key(rule)] {
+			diff.Removed = append(diff.Removed, rule)
+		}
+	}
+	return diff
+}
+
+func sortRules(rules [][]string) {
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
+// fromCasbinRule 与 adapter 加载规则时一致，去掉末尾的空字段
+func fromCasbinRule(line gormadapter.CasbinRule) []string {
+	rule := []string{line.Ptype, line.V0, line.V1, line.V2, line.V3, line.V4, line.V5}
+	n := len(rule)
+	for n > 1 && rule[n-1] == "" {
+		n--
+	}
+	return rule[:n]
+}
+
+// toCasbinRule 把 ptype 开头的规则转换为数据库行，字段多于 V0~V5 时返回 ErrRuleTooLong
+func toCasbinRule(rule []string) (gormadapter.CasbinRule, error) {
+	line := gormadapter.CasbinRule{Ptype: rule[0]}
+	fields := []*string{&line.V0, &line.V1, &line.V2, &line.V3, &line.V4, &line.V5}
+	if len(rule)-1 > len(fields) {
+		return line, fmt.Errorf("%w: %v", ErrRuleTooLong, rule)
+	}
+	for i, v := range rule[1:] {
+		*fields[i] = v
+	}
+	return line, nil
+}
@@ -0,0 +1,184 @@
+package enforcer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
+)
+
+func mustAddPolicy(t *testing.T, e *Enforcer, params ...interface{}) {
+	t.Helper()
+	if _, err := e.AddPolicy(params...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), nil)
+
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "read")
+	if _, err := e.AddGroupingPolicy("alice", "admin", "domain1"); err != nil {
+		t.Fatal(err)
+	}
+	first, err := e.CreateSnapshot("ops", "初始策略")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.RuleCount != 2 || first.Checksum == "" {
+		t.Errorf("first = %+v", first)
+	}
+
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "write")
+	if _, err := e.RemoveGroupingPolicy("alice", "admin", "domain1"); err != nil {
+		t.Fatal(err)
+	}
+	second, err := e.CreateSnapshot("ops", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := e.DiffSnapshots(first.ID, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PolicyDiff{
+		Added:   [][]string{{"p", "admin", "domain1", "/data/*", "write"}},
+		Removed: [][]string{{"g", "alice", "admin", "domain1"}},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffSnapshots = %+v, want %+v", diff, want)
+	}
+	if diff, err := e.DiffWithCurrent(second.ID); err != nil || !diff.Empty() {
+		t.Errorf("DiffWithCurrent = %+v, %v, want empty", diff, err)
+	}
+
+	list, err := e.ListSnapshots(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != second.ID || list[0].Rules != "" {
+		t.Errorf("ListSnapshots(1) = %+v, want latest snapshot without rules", list)
+	}
+
+	if _, err := e.GetSnapshot(999); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("GetSnapshot(999) error = %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+func TestRollbackBacksUpStoredRules(t *testing.T) {
+	db := openTestDB(t)
+	e := newTestEnforcer(t, db, nil)
+
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "read")
+	snapshot, err := e.CreateSnapshot("ops", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "write")
+
+	// 其他实例写入、本实例尚未加载的规则
+	if err := db.Create(&gormadapter.CasbinRule{Ptype: "p", V0: "bob", V1: "domain1", V2: "/logs", V3: "read"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := e.Rollback(snapshot.ID, "ops")
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	backupRules, err := backup.PolicyRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantBackup := [][]string{
+		{"p", "admin", "domain1", "/data/*", "read"},
+		{"p", "admin", "domain1", "/data/*", "write"},
+		{"p", "bob", "domain1", "/logs", "read"},
+	}
+	if !reflect.DeepEqual(backupRules, wantBackup) {
+		t.Errorf("backup = %v, want %v", backupRules, wantBackup)
+	}
+
+	if ok, _ := e.Enforce("admin", "domain1", "/data/1", "write"); ok {
+		t.Error("回滚后不应保留快照之后添加的规则")
+	}
+	stored, err := storedRules(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"p", "admin", "domain1", "/data/*", "read"}}; !reflect.DeepEqual(stored, want) {
+		t.Errorf("stored = %v, want %v", stored, want)
+	}
+
+	// 回滚到自动备份可以恢复所有规则
+	if _, err := e.Rollback(backup.ID, "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := e.Enforce("bob", "domain1", "/logs", "read"); !ok {
+		t.Error("回滚到备份后应恢复其他实例写入的规则")
+	}
+}
+
+func TestRollbackFailureKeepsPolicies(t *testing.T) {
+	db := openTestDB(t)
+	e := newTestEnforcer(t, db, nil)
+
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "read")
+	snapshot, err := e.CreateSnapshot("ops", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustAddPolicy(t, e, "admin", "domain1", "/data/*", "write")
+
+	errRestore := errors.New("disk full")
+	db.Callback().Create().Before("gorm:create").Register("test:fail_restore", func(tx *gorm.DB) {
+		if tx.Statement.Table == "casbin_rule" {
+			tx.AddError(errRestore)
+		}
+	})
+
+	if _, err := e.Rollback(snapshot.ID, "ops"); !errors.Is(err, errRestore) {
+		t.Fatalf("Rollback() error = %v, want %v", err, errRestore)
+	}
+	if ok, _ := e.Enforce("admin", "domain1", "/data/1", "write"); !ok {
+		t.Error("回滚失败时内存中的策略应保持不变")
+	}
+	stored, err := storedRules(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("stored = %v, want 2 rules", stored)
+	}
+	if list, _ := e.ListSnapshots(0); len(list) != 1 {
+		t.Errorf("snapshots = %d, want 1（备份随事务回滚）", len(list))
+	}
+}
+
+func TestSnapshotFilteredPolicy(t *testing.T) {
+	e := newTestEnforcer(t, openTestDB(t), nil)
+	e.filter = []gormadapter.Filter{{V1: []string{"domain1"}}}
+
+	if _, err := e.CreateSnapshot("ops", ""); !errors.Is(err, ErrFilteredPolicy) {
+		t.Errorf("CreateSnapshot() error = %v, want ErrFilteredPolicy", err)
+	}
+	if _, err := e.Rollback(1, "ops"); !errors.Is(err, ErrFilteredPolicy) {
+		t.Errorf("Rollback() error = %v, want ErrFilteredPolicy", err)
+	}
+}
+
+func TestFromCasbinRule(t *testing.T) {
+	rule := gormadapter.CasbinRule{Ptype: "p", V0: "alice", V1: "", V2: "/data", V3: "read"}
+	if got, want := fromCasbinRule(rule), []string{"p", "alice", "", "/data", "read"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fromCasbinRule = %v, want %v", got, want)
+	}
+	if got, err := toCasbinRule(fromCasbinRule(rule)); err != nil || got != rule {
+		t.Errorf("toCasbinRule(fromCasbinRule) = %+v, %v, want %+v", got, err, rule)
+	}
+	long := []string{"p", "v0", "v1", "v2", "v3", "v4", "v5", "v6"}
+	if _, err := toCasbinRule(long); !errors.Is(err, ErrRuleTooLong) {
+		t.Errorf("toCasbinRule(%v) error = %v, want ErrRuleTooLong", long, err)
+	}
+}
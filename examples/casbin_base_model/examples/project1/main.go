@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -21,6 +22,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create enforcer: %v", err)
 	}
+	defer e.Close(context.Background())
 
 	// 添加策略
 	// 在 project1 域中定义权限
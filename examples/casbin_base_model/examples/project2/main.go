@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -21,6 +22,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create enforcer: %v", err)
 	}
+	defer e.Close(context.Background())
 
 	// 在 project2 域中定义权限
 	// 这个项目使用不同的权限结构
@@ -0,0 +1,65 @@
+package policytest
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Discover 递归查找 root 下所有测试文件
+func Discover(root string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, FileSuffix) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discover suites failed: %v", err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Uncovered 返回 root 下没有被任何测试文件引用的模型文件（*.conf）
+func Uncovered(root string, suites []*Suite) ([]string, error) {
+	covered := make(map[string]bool, len(suites))
+	for _, s := range suites {
+		covered[s.ModelPath()] = true
+	}
+
+	var uncovered []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".conf") {
+			return nil
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if !covered[abs] {
+			uncovered = append(uncovered, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan models failed: %v", err)
+	}
+	sort.Strings(uncovered)
+	return uncovered, nil
+}
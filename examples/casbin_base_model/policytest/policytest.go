@@ -0,0 +1,334 @@
+// Package policytest 基于 YAML 用例表的 Casbin 策略测试工具
+//
+// 每个测试文件（*.policytest.yaml）指定一个模型和策略文件，以及一组期望结果：
+//
+//	name: api 权限
+//	model: api_model.conf      # 相对测试文件所在目录
+//	policy: api_policy.csv
+//	cases:
+//	  - {sub: alice, obj: /api/v1/users, act: GET, expect: allow}
+//	  - {sub: charles, obj: /api/v1/products/1, act: DELETE, expect: deny}
+//
+// ABAC 模型可以打开 accept_json，用 JSON 字符串给出请求中的结构体，规则里的 r.sub.Age 等属性从 JSON 中取值：
+//
+//	accept_json: true
+//	cases:
+//	  - {sub: '{"Age": 20}', obj: /data1, act: read, expect: allow}
+//
+// 不匹配的用例会给出命中的规则和主体的隐式角色，便于定位原因。
+package policytest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
+	"gopkg.in/yaml.v3"
+)
+
+// FileSuffix 测试文件后缀
+const FileSuffix = ".policytest.yaml"
+
+// 期望结果
+const (
+	ExpectAllow = "allow"
+	ExpectDeny  = "deny"
+)
+
+// Suite 一个测试文件
+type Suite struct {
+	Name   string `yaml:"name"`
+	Model  string `yaml:"model"`  // 模型文件，相对测试文件所在目录
+	Policy string `yaml:"policy"` // 策略文件，可为空
+	// Policies 额外的策略行（CSV 格式，如 "p, admin, data1, read"），在策略文件之后加载
+	Policies []string `yaml:"policies"`
+	// AcceptJSON 请求字段可以是 JSON 字符串，用于表达 ABAC 模型中的结构体
+	AcceptJSON bool `yaml:"accept_json"`
+	// Skip 不为空时跳过整个测试文件，内容为跳过原因
+	Skip  string `yaml:"skip"`
+	Cases []Case `yaml:"cases"`
+
+	path string
+}
+
+// Case 一条用例
+// 请求参数按 request_definition 的字段名填充：sub、dom、obj、act 直接给出，其他字段放在 extra 中；
+// 也可以用 request 按顺序给出完整请求
+type Case struct {
+	Name    string            `yaml:"name"`
+	Sub     string            `yaml:"sub"`
+	Dom     string            `yaml:"dom"`
+	Obj     string            `yaml:"obj"`
+	Act     string            `yaml:"act"`
+	Extra   map[string]string `yaml:"extra"`
+	Request []string          `yaml:"request"`
+	Expect  string            `yaml:"expect"` // allow 或 deny
+}
+
+// Options 运行选项
+type Options struct {
+	// Functions 模型中用到的自定义函数
+	Functions map[string]govaluate.ExpressionFunction
+}
+
+// Result 一条用例的执行结果
+type Result struct {
+	Case    Case
+	Request []interface{}
+	Got     bool
+	Err     error
+	Matched []string // 决定结果的规则（EnforceEx 的解释）
+	Roles   []string // 主体的隐式角色
+}
+
+// Passed 用例是否通过
+func (r Result) Passed() bool {
+	return r.Err == nil && r.Got == (r.Case.Expect == ExpectAllow)
+}
+
+// Explain 返回不匹配的原因
+func (r Result) Explain() string {
+	if r.Err != nil {
+		return fmt.Sprintf("执行失败: %v", r.Err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "请求 %v 期望 %s，实际 %s", r.Request, r.Case.Expect, decision(r.Got))
+	if len(r.Matched) > 0 {
+		fmt.Fprintf(&b, "；命中规则 %v", r.Matched)
+	} else {
+		b.WriteString("；没有命中任何规则")
+	}
+	if len(r.Roles) > 0 {
+		fmt.Fprintf(&b, "；主体角色 %v", r.Roles)
+	}
+	return b.String()
+}
+
+// Report 一个测试文件的执行结果
+type Report struct {
+	Suite   *Suite
+	Results []Result
+}
+
+// Failures 返回未通过的用例
+func (r *Report) Failures() []Result {
+	failures := make([]Result, 0)
+	for _, res := range r.Results {
+		if !res.Passed() {
+			failures = append(failures, res)
+		}
+	}
+	return failures
+}
+
+// LoadSuite 读取测试文件
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read suite failed: %v", err)
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("parse suite %s failed: %v", path, err)
+	}
+	suite.path = path
+	if suite.Name == "" {
+		suite.Name = strings.TrimSuffix(filepath.Base(path), FileSuffix)
+	}
+
+	if suite.Model == "" {
+		return nil, fmt.Errorf("suite %s: model is required", path)
+	}
+	for i, c := range suite.Cases {
+		if c.Expect != ExpectAllow && c.Expect != ExpectDeny {
+			return nil, fmt.Errorf("suite %s: case %d: expect must be %q or %q, got %q",
+				path, i, ExpectAllow, ExpectDeny, c.Expect)
+		}
+		if c.Name == "" {
+			suite.Cases[i].Name = fmt.Sprintf("#%d %s %s %s", i+1, c.Sub, c.Act, c.Obj)
+		}
+	}
+	return &suite, nil
+}
+
+// Path 测试文件路径
+func (s *Suite) Path() string {
+	return s.path
+}
+
+// ModelPath 模型文件的绝对路径
+func (s *Suite) ModelPath() string {
+	return s.resolve(s.Model)
+}
+
+// PolicyPath 策略文件的绝对路径，未指定时返回空字符串
+func (s *Suite) PolicyPath() string {
+	if s.Policy == "" {
+		return ""
+	}
+	return s.resolve(s.Policy)
+}
+
+func (s *Suite) resolve(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	abs, err := filepath.Abs(filepath.Join(filepath.Dir(s.path), p))
+	if err != nil {
+		return filepath.Join(filepath.Dir(s.path), p)
+	}
+	return abs
+}
+
+// Run 加载模型和策略并执行所有用例
+func (s *Suite) Run(opts Options) (*Report, error) {
+	e, err := s.newEnforcer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	if ast, ok := e.GetModel()["r"]["r"]; ok {
+		tokens = ast.Tokens
+	}
+	withDomain := false
+	if ast, ok := e.GetModel()["g"]["g"]; ok {
+		withDomain = len(ast.Tokens) > 2
+	}
+
+	report := &Report{Suite: s}
+	for _, c := range s.Cases {
+		res := Result{Case: c}
+		res.Request, res.Err = buildRequest(tokens, c)
+		if res.Err == nil {
+			res.Got, res.Matched, res.Err = e.EnforceEx(res.Request...)
+		}
+		if !res.Passed() && c.Sub != "" {
+			if withDomain {
+				res.Roles, _ = e.GetImplicitRolesForUser(c.Sub, c.Dom)
+			} else {
+				res.Roles, _ = e.GetImplicitRolesForUser(c.Sub)
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+func (s *Suite) newEnforcer(opts Options) (*casbin.Enforcer, error) {
+	var (
+		e   *casbin.Enforcer
+		err error
+	)
+	if policy := s.PolicyPath(); policy != "" {
+		e, err = casbin.NewEnforcer(s.ModelPath(), policy)
+	} else {
+		e, err = casbin.NewEnforcer(s.ModelPath())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create enforcer failed: %v", err)
+	}
+
+	for name, fn := range opts.Functions {
+		e.AddFunction(name, fn)
+	}
+	e.EnableAcceptJsonRequest(s.AcceptJSON)
+
+	if len(s.Policies) == 0 {
+		return e, nil
+	}
+	// 与策略文件一样由 Casbin 的 CSV 规则解析，支持引号包裹的字段和逗号后的空格
+	m := e.GetModel()
+	for _, line := range s.Policies {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ptype := strings.TrimSpace(strings.SplitN(line, ",", 2)[0])
+		if ptype == "" || m[ptype[:1]][ptype] == nil {
+			return nil, fmt.Errorf("add policy %q failed: unknown policy type %q", line, ptype)
+		}
+		if err := persist.LoadPolicyLine(line, m); err != nil {
+			return nil, fmt.Errorf("add policy %q failed: %v", line, err)
+		}
+	}
+	if err := e.BuildRoleLinks(); err != nil {
+		return nil, fmt.Errorf("build role links failed: %v", err)
+	}
+	return e, nil
+}
+
+// ParentFunction 返回 g2_parent 一类的自定义函数：参数为成员，返回它在 ptype 关系中的第一个上级
+// （如用户所在的用户组），没有上级时返回空字符串。上级关系取自测试文件的策略
+func (s *Suite) ParentFunction(ptype string) (govaluate.ExpressionFunction, error) {
+	e, err := s.newEnforcer(Options{})
+	if err != nil {
+		return nil, err
+	}
+	return func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s_parent expects 1 argument, got %d", ptype, len(args))
+		}
+		member, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s_parent expects a string argument, got %T", ptype, args[0])
+		}
+		for _, rule := range e.GetFilteredNamedGroupingPolicy(ptype, 0, member) {
+			return rule[1], nil
+		}
+		return "", nil
+	}, nil
+}
+
+// ExampleOptions 返回 examples 下模型用到的自定义函数（g2_parent）
+func ExampleOptions(s *Suite) (Options, error) {
+	g2Parent, err := s.ParentFunction("g2")
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{Functions: map[string]govaluate.ExpressionFunction{"g2_parent": g2Parent}}, nil
+}
+
+// buildRequest 按 request_definition 的字段顺序组装请求
+func buildRequest(tokens []string, c Case) ([]interface{}, error) {
+	if len(c.Request) > 0 {
+		if len(c.Request) != len(tokens) {
+			return nil, fmt.Errorf("request has %d fields, model expects %d (%v)", len(c.Request), len(tokens), tokens)
+		}
+		rvals := make([]interface{}, len(c.Request))
+		for i, v := range c.Request {
+			rvals[i] = v
+		}
+		return rvals, nil
+	}
+
+	rvals := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		switch name := strings.TrimPrefix(token, "r_"); name {
+		case "sub":
+			rvals = append(rvals, c.Sub)
+		case "dom":
+			rvals = append(rvals, c.Dom)
+		case "obj":
+			rvals = append(rvals, c.Obj)
+		case "act":
+			rvals = append(rvals, c.Act)
+		default:
+			rvals = append(rvals, c.Extra[name])
+		}
+	}
+	return rvals, nil
+}
+
+func decision(allowed bool) string {
+	if allowed {
+		return ExpectAllow
+	}
+	return ExpectDeny
+}
@@ -0,0 +1,320 @@
+package policytest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModel = `[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestSuiteRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "model.conf", testModel)
+	writeFile(t, dir, "policy.csv", "p, admin, data1, read\ng, alice, admin\n")
+	path := writeFile(t, dir, "rbac"+FileSuffix, `
+model: model.conf
+policy: policy.csv
+policies:
+  - p, admin, data2, write
+cases:
+  - {name: 通过, sub: alice, obj: data1, act: read, expect: allow}
+  - {name: 额外策略, sub: alice, obj: data2, act: write, expect: allow}
+  - {name: 期望错误, sub: alice, obj: data1, act: write, expect: allow}
+  - {name: 完整请求, request: [bob, data1, read], expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	if suite.Name != "rbac" {
+		t.Errorf("Name = %q; 期望值 %q", suite.Name, "rbac")
+	}
+
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	failures := report.Failures()
+	if len(failures) != 1 || failures[0].Case.Name != "期望错误" {
+		t.Fatalf("Failures = %v; 期望只有 \"期望错误\"", failures)
+	}
+
+	explain := failures[0].Explain()
+	for _, want := range []string{"期望 allow", "实际 deny", "没有命中任何规则", "[admin]"} {
+		if !strings.Contains(explain, want) {
+			t.Errorf("Explain() = %q; 缺少 %q", explain, want)
+		}
+	}
+}
+
+func TestSuiteRunMatchedRule(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "model.conf", testModel)
+	path := writeFile(t, dir, "matched"+FileSuffix, `
+model: model.conf
+policies:
+  - p, alice, data1, read
+cases:
+  - {sub: alice, obj: data1, act: read, expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	failures := report.Failures()
+	if len(failures) != 1 {
+		t.Fatalf("Failures = %d; 期望值 1", len(failures))
+	}
+	if !strings.Contains(failures[0].Explain(), "命中规则 [alice data1 read]") {
+		t.Errorf("Explain() = %q; 期望包含命中的规则", failures[0].Explain())
+	}
+}
+
+func TestSuiteRunAcceptJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "abac.conf", `[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub_rule, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = eval(p.sub_rule) && r.obj == p.obj && r.act == p.act
+`)
+	path := writeFile(t, dir, "abac"+FileSuffix, `
+model: abac.conf
+accept_json: true
+policies:
+  - p, r.sub.Age > 18 && r.sub.Dept == 'IT', data1, read
+cases:
+  - {sub: '{"Age": 20, "Dept": "IT"}', obj: data1, act: read, expect: allow}
+  - {sub: '{"Age": 16, "Dept": "IT"}', obj: data1, act: read, expect: deny}
+  - {sub: '{"Age": 20, "Dept": "HR"}', obj: data1, act: read, expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, res := range report.Failures() {
+		t.Errorf("%s: %s", res.Case.Name, res.Explain())
+	}
+}
+
+func TestExampleOptionsParentFunction(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "group.conf", `[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+g2 = _, _
+g3 = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = (g2(r.sub, p.sub) || g3(g2_parent(r.sub), p.sub)) && r.obj == p.obj && r.act == p.act
+`)
+	path := writeFile(t, dir, "group"+FileSuffix, `
+model: group.conf
+policies:
+  - p, staff, data1, read
+  - g2, alice, dev
+  - g3, dev, staff
+cases:
+  - {name: 通过父用户组继承, sub: alice, obj: data1, act: read, expect: allow}
+  - {name: 没有用户组, sub: bob, obj: data1, act: read, expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	// 没有提供 g2_parent 时用例执行失败
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res := report.Results[0]; res.Err == nil {
+		t.Errorf("缺少 g2_parent 时应返回错误，got %+v", res)
+	}
+
+	opts, err := ExampleOptions(suite)
+	if err != nil {
+		t.Fatalf("ExampleOptions: %v", err)
+	}
+	report, err = suite.Run(opts)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, res := range report.Failures() {
+		t.Errorf("%s: %s", res.Case.Name, res.Explain())
+	}
+
+	parent := opts.Functions["g2_parent"]
+	if _, err := parent("alice", "bob"); err == nil {
+		t.Error("g2_parent 参数个数错误时应返回错误")
+	}
+}
+
+func TestLoadSuiteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"缺少模型", "cases: []", "model is required"},
+		{"非法期望值", "model: m.conf\ncases:\n  - {sub: a, expect: yes}", "expect must be"},
+		{"YAML 格式错误", "model: [", "parse suite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "bad"+FileSuffix, tt.content)
+			_, err := LoadSuite(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadSuite() error = %v; 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequestLengthMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "model.conf", testModel)
+	path := writeFile(t, dir, "len"+FileSuffix, `
+model: model.conf
+cases:
+  - {request: [alice, data1], expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res := report.Results[0]; res.Passed() || res.Err == nil {
+		t.Errorf("字段数量不匹配的请求应当失败，got %+v", res)
+	}
+}
+
+func TestPoliciesCSV(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "model.conf", testModel)
+	path := writeFile(t, dir, "csv"+FileSuffix, `
+model: model.conf
+policies:
+  - 'p,  admin, "data1,data2", read'
+  - '# 注释行'
+  - g,alice,admin
+cases:
+  - {name: 引号中的逗号, sub: alice, obj: 'data1,data2', act: read, expect: allow}
+  - {name: 不再按逗号拆开, sub: alice, obj: data1, act: read, expect: deny}
+`)
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	report, err := suite.Run(Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for _, res := range report.Failures() {
+		t.Errorf("%s: %s", res.Case.Name, res.Explain())
+	}
+
+	tests := []struct {
+		name    string
+		line    string
+		wantErr string
+	}{
+		{"未知策略类型", "x, alice, data1, read", "unknown policy type"},
+		{"缺少策略类型", ", alice, data1, read", "unknown policy type"},
+		{"字段数量错误", "p, alice, data1", "invalid policy rule size"},
+		{"引号未闭合", `p, alice, "data1, read`, "add policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite.Policies = []string{tt.line}
+			if _, err := suite.Run(Options{}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Run() error = %v; 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiscoverAndUncovered(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "model.conf", testModel)
+	writeFile(t, dir, "other.conf", testModel)
+	path := writeFile(t, dir, "model"+FileSuffix, "model: model.conf\ncases: []\n")
+
+	paths, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != path {
+		t.Fatalf("Discover = %v; 期望值 [%s]", paths, path)
+	}
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	uncovered, err := Uncovered(dir, []*Suite{suite})
+	if err != nil {
+		t.Fatalf("Uncovered: %v", err)
+	}
+	if len(uncovered) != 1 || filepath.Base(uncovered[0]) != "other.conf" {
+		t.Errorf("Uncovered = %v; 期望值 [other.conf]", uncovered)
+	}
+}
@@ -0,0 +1,59 @@
+package policytest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// examplesRoot 仓库 examples 目录，包含所有 Casbin 示例的模型和策略
+const examplesRoot = "../.."
+
+// TestRepositoryFixtures 运行 examples 下所有 *.policytest.yaml
+func TestRepositoryFixtures(t *testing.T) {
+	paths, err := Discover(examplesRoot)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("在 %s 下没有找到策略测试", examplesRoot)
+	}
+
+	suites := make([]*Suite, 0, len(paths))
+	for _, path := range paths {
+		suite, err := LoadSuite(path)
+		if err != nil {
+			t.Errorf("LoadSuite(%s): %v", path, err)
+			continue
+		}
+		suites = append(suites, suite)
+
+		rel, _ := filepath.Rel(examplesRoot, path)
+		t.Run(rel, func(t *testing.T) {
+			if suite.Skip != "" {
+				t.Skip(suite.Skip)
+			}
+
+			opts, err := ExampleOptions(suite)
+			if err != nil {
+				t.Fatalf("ExampleOptions: %v", err)
+			}
+			report, err := suite.Run(opts)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			for _, res := range report.Results {
+				if !res.Passed() {
+					t.Errorf("%s: %s", res.Case.Name, res.Explain())
+				}
+			}
+		})
+	}
+
+	uncovered, err := Uncovered(examplesRoot, suites)
+	if err != nil {
+		t.Fatalf("Uncovered: %v", err)
+	}
+	for _, path := range uncovered {
+		t.Errorf("模型 %s 没有对应的 %s", path, FileSuffix)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/middleware"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+)
+
+// maxCapabilities 单次批量检查的能力数量上限
+const maxCapabilities = 200
+
+// PermissionHandler 当前用户权限查询接口，供前端渲染菜单和按钮
+type PermissionHandler struct {
+	authService   *service.AuthService
+	defaultDomain string
+}
+
+// NewPermissionHandler 创建权限查询处理器
+func NewPermissionHandler(authService *service.AuthService, defaultDomain string) *PermissionHandler {
+	return &PermissionHandler{
+		authService:   authService,
+		defaultDomain: defaultDomain,
+	}
+}
+
+// PermissionsResponse 有效权限响应
+type PermissionsResponse struct {
+	Subject     string                    `json:"subject"`
+	Permissions []service.PermissionEntry `json:"permissions"`
+}
+
+// CheckRequest 批量能力检查请求
+type CheckRequest struct {
+	Capabilities []service.Capability `json:"capabilities"`
+}
+
+// CheckResponse 批量能力检查响应，key 为能力标识
+type CheckResponse struct {
+	Results map[string]bool `json:"results"`
+}
+
+// RegisterRoutes 注册 /me/permissions 相关路由
+// 当前用户来自 RouteAuth 写入上下文的 userID，路由需要放在 RouteAuth 之后，
+// 并加入 RouteAuthConfig.AuthOnly，否则每个用户都需要单独的策略才能查询自己的权限
+func (h *PermissionHandler) RegisterRoutes(r gin.IRouter) {
+	me := r.Group("/me/permissions")
+	{
+		me.GET("", h.GetPermissions)
+		me.POST("/check", h.CheckCapabilities)
+	}
+}
+
+// GetPermissions 返回当前用户的有效权限
+func (h *PermissionHandler) GetPermissions(c *gin.Context) {
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.authService.GetSubjectPermissions(sub)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, middleware.ErrorResponse{
+			Code:  middleware.CodeEnforceFailed,
+			Error: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, PermissionsResponse{Subject: sub, Permissions: entries})
+}
+
+// CheckCapabilities 批量检查界面能力，前端一次请求即可得到所有菜单和按钮的可见性
+func (h *PermissionHandler) CheckCapabilities(c *gin.Context) {
+	sub, ok := currentSubject(c)
+	if !ok {
+		return
+	}
+
+	var req CheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Capabilities) > maxCapabilities {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "能力数量超过上限"})
+		return
+	}
+
+	domain := h.defaultDomain
+	if d := c.GetString("domain"); d != "" {
+		domain = d
+	}
+
+	results, err := h.authService.CheckSubjectCapabilities(sub, domain, req.Capabilities)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, CheckResponse{Results: results})
+}
+
+// currentSubject 读取 RouteAuth 认证后写入上下文的主体（如 user:1），
+// 不读取请求头，避免客户端伪造 X-User-ID 查询他人的权限
+func currentSubject(c *gin.Context) (string, bool) {
+	sub := c.GetString("userID")
+	if sub == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, middleware.ErrorResponse{
+			Code:  middleware.CodeUnauthenticated,
+			Error: "未认证",
+		})
+		return "", false
+	}
+	return sub, true
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/middleware"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newPermissionRouter(t *testing.T, withAuth bool) *gin.Engine {
+	t.Helper()
+	e, err := casbin.NewEnforcer("../../doc_restful_model.conf", "../../doc_restful_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	if withAuth {
+		r.Use(middleware.RouteAuth(middleware.RouteAuthConfig{
+			Enforcer:           e,
+			SubjectFunc:        middleware.HeaderSubject("X-User-ID"),
+			MatchRouteTemplate: true,
+			AuthOnly:           []string{"/me/permissions", "/me/permissions/check"},
+		}))
+	}
+	NewPermissionHandler(service.NewAuthService(nil, e), "").RegisterRoutes(r)
+	return r
+}
+
+func do(r *gin.Engine, method, target, user string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	if user != "" {
+		req.Header.Set("X-User-ID", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetPermissions(t *testing.T) {
+	r := newPermissionRouter(t, true)
+
+	if w := do(r, "GET", "/me/permissions", "", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: %d", w.Code)
+	}
+
+	w := do(r, "GET", "/me/permissions", "charles", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("charles: %d %s", w.Code, w.Body)
+	}
+	var resp PermissionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Subject != "charles" || len(resp.Permissions) != 2 {
+		t.Fatalf("charles permissions = %+v", resp)
+	}
+	if p := resp.Permissions[0]; p.Object != "/api/v1/documents/public/*" || p.Actions[0] != "GET" || p.Sources[0] != "user" {
+		t.Fatalf("first entry = %+v", p)
+	}
+}
+
+func TestGetPermissionsIgnoresHeaderWithoutAuth(t *testing.T) {
+	// 没有 RouteAuth 时不能靠伪造 X-User-ID 查询他人权限
+	r := newPermissionRouter(t, false)
+	if w := do(r, "GET", "/me/permissions", "alice", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("got %d, want 401", w.Code)
+	}
+	if w := do(r, "POST", "/me/permissions/check", "alice", CheckRequest{}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("check: got %d, want 401", w.Code)
+	}
+}
+
+func TestGetPermissionsAdminBypass(t *testing.T) {
+	r := newPermissionRouter(t, true)
+	w := do(r, "GET", "/me/permissions", "alice", nil)
+	var resp PermissionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// 匹配器中的 g(r.sub, "admin") && p.eft == "allow" 让管理员匹配所有 allow 规则
+	found := false
+	for _, p := range resp.Permissions {
+		if p.Object == "/api/v1/documents/private/*" {
+			found = len(p.Sources) == 1 && p.Sources[0] == "admin"
+		}
+	}
+	if !found {
+		t.Fatalf("admin permissions missing bypassed rules: %+v", resp.Permissions)
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	r := newPermissionRouter(t, true)
+	req := CheckRequest{Capabilities: []service.Capability{
+		{Key: "doc.read", Object: "/api/v1/documents/public/1", Action: "GET"},
+		{Key: "doc.delete", Object: "/api/v1/documents/public/1", Action: "DELETE"},
+		{Key: "comment.create", Object: "/api/v1/documents/public/1/comments", Action: "POST"},
+	}}
+	w := do(r, "POST", "/me/permissions/check", "charles", req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("%d %s", w.Code, w.Body)
+	}
+	var resp CheckResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"doc.read": true, "doc.delete": false, "comment.create": true}
+	for k, v := range want {
+		if resp.Results[k] != v {
+			t.Fatalf("results = %v, want %v", resp.Results, want)
+		}
+	}
+
+	if w := do(r, "POST", "/me/permissions/check", "charles", CheckRequest{Capabilities: []service.Capability{{Object: "/x", Action: "GET"}}}); w.Code != http.StatusBadRequest {
+		t.Fatalf("missing key: %d", w.Code)
+	}
+	caps := make([]service.Capability, maxCapabilities+1)
+	if w := do(r, "POST", "/me/permissions/check", "charles", CheckRequest{Capabilities: caps}); w.Code != http.StatusBadRequest {
+		t.Fatalf("too many: %d", w.Code)
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// 统一错误码
+const (
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeEnforceFailed   = "enforce_failed"
+)
+
+// ErrorResponse 权限中间件统一错误响应
+type ErrorResponse struct {
+	Code   string `json:"code"`
+	Error  string `json:"error"`
+	Object string `json:"object,omitempty"`
+	Action string `json:"action,omitempty"`
+}
+
+// RouteAuthConfig 路由权限中间件配置
+type RouteAuthConfig struct {
+	Enforcer *casbin.Enforcer
+
+	// SubjectFunc 获取当前用户，必须设置，通常读取认证中间件写入上下文的用户
+	SubjectFunc func(c *gin.Context) string
+
+	// MatchRouteTemplate 为 true 时用 c.FullPath()（如 /api/v1/documents/:id）作为权限对象，
+	// 策略无需枚举具体路径；默认使用请求路径 c.Request.URL.Path
+	MatchRouteTemplate bool
+
+	// DomainParam 从路由参数获取域（如 /tenants/:tenant 中的 "tenant"），优先于请求头
+	DomainParam string
+	// DomainHeader 从请求头获取域，默认 X-Domain
+	DomainHeader string
+	// DefaultDomain 未指定域时使用的默认域
+	DefaultDomain string
+
+	// OwnerFunc 获取资源所有者，例如根据 c.Param("id") 查询文档
+	OwnerFunc func(c *gin.Context) string
+	// SelfOwner 所有者为当前用户时传给 enforcer 的值（如 "self"），为空则传原值
+	SelfOwner string
+
+	// Actions 按路由覆盖操作，key 为 "METHOD /path/template"，默认使用请求方法
+	Actions map[string]string
+	// Skip 跳过权限检查的路由，格式为 "METHOD /path/template" 或 "/path/template"（所有方法）
+	Skip []string
+	// AuthOnly 只要求登录、不检查策略的路由，格式同 Skip，例如当前用户查询自己的权限
+	AuthOnly []string
+}
+
+// RouteAuth 创建可配置的权限中间件
+// 默认按请求路径检查权限，设置 MatchRouteTemplate 后按路由模板检查；Actions、Skip 和 AuthOnly 始终按路由模板配置。
+// 未设置 SubjectFunc 时 panic，避免默默信任客户端可以伪造的请求头
+func RouteAuth(config RouteAuthConfig) gin.HandlerFunc {
+	if config.SubjectFunc == nil {
+		panic("middleware: RouteAuthConfig.SubjectFunc 不能为空")
+	}
+	if config.DomainHeader == "" {
+		config.DomainHeader = "X-Domain"
+	}
+
+	skip := make(map[string]bool, len(config.Skip))
+	for _, route := range config.Skip {
+		skip[route] = true
+	}
+	authOnly := make(map[string]bool, len(config.AuthOnly))
+	for _, route := range config.AuthOnly {
+		authOnly[route] = true
+	}
+
+	// 根据 request_definition 决定传给 enforcer 的参数
+	var tokens []string
+	if ast, ok := config.Enforcer.GetModel()["r"]["r"]; ok {
+		tokens = ast.Tokens
+	}
+
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		method := c.Request.Method
+
+		obj := c.Request.URL.Path
+		if config.MatchRouteTemplate {
+			// 未匹配的路由没有模板，交给 404 处理
+			if path == "" {
+				c.Next()
+				return
+			}
+			obj = path
+		}
+
+		if skip[path] || skip[method+" "+path] {
+			c.Next()
+			return
+		}
+
+		sub := config.SubjectFunc(c)
+		if sub == "" {
+			abortWithError(c, http.StatusUnauthorized, ErrorResponse{
+				Code:  CodeUnauthenticated,
+				Error: "未认证",
+			})
+			return
+		}
+
+		domain := config.DefaultDomain
+		if config.DomainParam != "" && c.Param(config.DomainParam) != "" {
+			domain = c.Param(config.DomainParam)
+		} else if h := c.GetHeader(config.DomainHeader); h != "" {
+			domain = h
+		}
+
+		if authOnly[path] || authOnly[method+" "+path] {
+			c.Set("userID", sub)
+			c.Set("domain", domain)
+			c.Next()
+			return
+		}
+
+		act := method
+		if override, ok := config.Actions[method+" "+path]; ok {
+			act = override
+		}
+
+		var owner string
+		if config.OwnerFunc != nil {
+			owner = config.OwnerFunc(c)
+			if owner != "" && owner == sub && config.SelfOwner != "" {
+				owner = config.SelfOwner
+			}
+		}
+
+		rvals := make([]interface{}, 0, len(tokens))
+		for _, token := range tokens {
+			switch strings.TrimPrefix(token, "r_") {
+			case "sub":
+				rvals = append(rvals, sub)
+			case "dom":
+				rvals = append(rvals, domain)
+			case "obj":
+				rvals = append(rvals, obj)
+			case "act":
+				rvals = append(rvals, act)
+			case "owner":
+				rvals = append(rvals, owner)
+			default:
+				rvals = append(rvals, "")
+			}
+		}
+
+		allowed, err := config.Enforcer.Enforce(rvals...)
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, ErrorResponse{
+				Code:  CodeEnforceFailed,
+				Error: "权限检查失败",
+			})
+			return
+		}
+		if !allowed {
+			abortWithError(c, http.StatusForbidden, ErrorResponse{
+				Code:   CodeForbidden,
+				Error:  "没有权限",
+				Object: obj,
+				Action: act,
+			})
+			return
+		}
+
+		// 将用户信息存储在上下文中
+		c.Set("userID", sub)
+		c.Set("domain", domain)
+
+		c.Next()
+	}
+}
+
+// HeaderSubject 从请求头读取当前用户
+// 客户端可以伪造请求头，只应在网关已完成认证并重写该请求头的部署中使用
+func HeaderSubject(name string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+func abortWithError(c *gin.Context, status int, resp ErrorResponse) {
+	c.AbortWithStatusJSON(status, resp)
+}
@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+const domainOwnerModel = `
+[request_definition]
+r = sub, dom, obj, act, owner
+
+[policy_definition]
+p = sub, dom, obj, act, owner
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.dom == p.dom && r.obj == p.obj && r.act == p.act && (p.owner == "*" || r.owner == p.owner)
+`
+
+func newEnforcerFromString(t *testing.T, text string, policies ...[]string) *casbin.Enforcer {
+	t.Helper()
+	m, err := model.NewModelFromString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range policies {
+		if _, err := e.AddPolicy(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return e
+}
+
+// serve 用给定的中间件配置注册路由并发起请求，返回状态码和响应体
+func serve(t *testing.T, config RouteAuthConfig, routes []string, method, target string, header map[string]string) (int, ErrorResponse) {
+	t.Helper()
+	r := gin.New()
+	r.Use(RouteAuth(config))
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	for _, route := range routes {
+		r.Handle(http.MethodGet, route, ok)
+		r.Handle(http.MethodPost, route, ok)
+		r.Handle(http.MethodDelete, route, ok)
+	}
+
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	if w.Code != http.StatusOK && w.Code != http.StatusNotFound {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code, resp
+}
+
+func TestRouteAuthRestfulModel(t *testing.T) {
+	e, err := casbin.NewEnforcer("../../doc_restful_model.conf", "../../doc_restful_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := []string{
+		"/api/v1/documents/public",
+		"/api/v1/documents/public/:id",
+		"/api/v1/documents/public/:id/comments",
+		"/api/v1/documents/private/:id",
+	}
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		user     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "anonymous", method: "GET", target: "/api/v1/documents/public/1", wantCode: 401, wantBody: CodeUnauthenticated},
+		{name: "anonymous list is not skipped", method: "GET", target: "/api/v1/documents/public", wantCode: 401, wantBody: CodeUnauthenticated},
+		{name: "user reads public doc", method: "GET", target: "/api/v1/documents/public/1", user: "charles", wantCode: 200},
+		{name: "user comments", method: "POST", target: "/api/v1/documents/public/1/comments", user: "charles", wantCode: 200},
+		{name: "user cannot delete", method: "DELETE", target: "/api/v1/documents/public/1", user: "charles", wantCode: 403, wantBody: CodeForbidden},
+		{name: "user cannot read private", method: "GET", target: "/api/v1/documents/private/1", user: "david", wantCode: 403, wantBody: CodeForbidden},
+		{name: "leader reads private", method: "GET", target: "/api/v1/documents/private/1", user: "bob", wantCode: 200},
+		{name: "admin deletes", method: "DELETE", target: "/api/v1/documents/private/1", user: "alice", wantCode: 200},
+	}
+	// 策略使用通配符，按请求路径和按路由模板检查的结果相同
+	for _, template := range []bool{false, true} {
+		config := RouteAuthConfig{Enforcer: e, SubjectFunc: HeaderSubject("X-User-ID"), MatchRouteTemplate: template}
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/template=%v", tt.name, template), func(t *testing.T) {
+				header := map[string]string{}
+				if tt.user != "" {
+					header["X-User-ID"] = tt.user
+				}
+				code, resp := serve(t, config, routes, tt.method, tt.target, header)
+				if code != tt.wantCode || resp.Code != tt.wantBody {
+					t.Fatalf("got %d %q, want %d %q", code, resp.Code, tt.wantCode, tt.wantBody)
+				}
+				if code == http.StatusForbidden && (resp.Object == "" || resp.Object[0] != '/' || resp.Action != tt.method) {
+					t.Fatalf("forbidden response = %+v", resp)
+				}
+			})
+		}
+	}
+
+	t.Run("unknown route", func(t *testing.T) {
+		header := map[string]string{"X-User-ID": "david"}
+		config := RouteAuthConfig{Enforcer: e, SubjectFunc: HeaderSubject("X-User-ID")}
+		if code, _ := serve(t, config, routes, "GET", "/nope", header); code != http.StatusForbidden {
+			t.Fatalf("request path mode got %d, want 403", code)
+		}
+		config.MatchRouteTemplate = true
+		if code, _ := serve(t, config, routes, "GET", "/nope", header); code != http.StatusNotFound {
+			t.Fatalf("route template mode got %d, want 404", code)
+		}
+	})
+}
+
+func TestAuthMiddlewareMatchesRequestPath(t *testing.T) {
+	e := newEnforcerFromString(t, `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.dom == p.dom && r.obj == p.obj && r.act == p.act
+`, []string{"u1", "platform", "/docs/1", "GET"})
+	r := gin.New()
+	r.Use(AuthMiddleware(e))
+	r.GET("/docs/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	tests := []struct {
+		target   string
+		wantCode int
+	}{
+		{target: "/docs/1", wantCode: 200},
+		{target: "/docs/2", wantCode: 403},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("X-User-ID", "u1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.wantCode {
+			t.Fatalf("GET %s got %d, want %d", tt.target, w.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestRouteAuthRequiresSubjectFunc(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RouteAuth without SubjectFunc did not panic")
+		}
+	}()
+	RouteAuth(RouteAuthConfig{Enforcer: newEnforcerFromString(t, domainOwnerModel)})
+}
+
+func TestRouteAuthConfig(t *testing.T) {
+	e := newEnforcerFromString(t, domainOwnerModel,
+		[]string{"u1", "t1", "/tenants/:tenant/docs/:id", "read", "self"},
+		[]string{"u1", "t2", "/tenants/:tenant/docs/:id", "read", "*"},
+		[]string{"u1", "hdr", "/docs/:id", "GET", "*"},
+	)
+	owner := func(c *gin.Context) string { return c.Query("owner") }
+	base := RouteAuthConfig{
+		Enforcer:           e,
+		SubjectFunc:        HeaderSubject("X-User-ID"),
+		MatchRouteTemplate: true,
+		DomainParam:        "tenant",
+		OwnerFunc:          owner,
+		SelfOwner:          "self",
+		Actions:            map[string]string{"GET /tenants/:tenant/docs/:id": "read"},
+		Skip:               []string{"GET /health", "/public"},
+		AuthOnly:           []string{"GET /me"},
+	}
+	routes := []string{"/tenants/:tenant/docs/:id", "/docs/:id", "/health", "/public", "/me"}
+	user := map[string]string{"X-User-ID": "u1"}
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		header   map[string]string
+		wantCode int
+	}{
+		{name: "own doc in t1 via action override", method: "GET", target: "/tenants/t1/docs/1?owner=u1", header: user, wantCode: 200},
+		{name: "other's doc in t1", method: "GET", target: "/tenants/t1/docs/1?owner=u2", header: user, wantCode: 403},
+		{name: "any doc in t2", method: "GET", target: "/tenants/t2/docs/1?owner=u2", header: user, wantCode: 200},
+		{name: "action not overridden for DELETE", method: "DELETE", target: "/tenants/t2/docs/1", header: user, wantCode: 403},
+		{name: "domain from header", method: "GET", target: "/docs/1", header: map[string]string{"X-User-ID": "u1", "X-Domain": "hdr"}, wantCode: 200},
+		{name: "default domain", method: "GET", target: "/docs/1", header: user, wantCode: 403},
+		{name: "skip by method and path", method: "GET", target: "/health", wantCode: 200},
+		{name: "skip only listed method", method: "POST", target: "/health", wantCode: 401},
+		{name: "skip all methods", method: "DELETE", target: "/public", wantCode: 200},
+		{name: "auth only without policy", method: "GET", target: "/me", header: user, wantCode: 200},
+		{name: "auth only still needs a user", method: "GET", target: "/me", wantCode: 401},
+		{name: "auth only lists methods", method: "POST", target: "/me", header: user, wantCode: 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := serve(t, base, routes, tt.method, tt.target, tt.header)
+			if code != tt.wantCode {
+				t.Fatalf("got %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+
+	t.Run("custom subject and default domain", func(t *testing.T) {
+		config := base
+		config.SubjectFunc = func(c *gin.Context) string { return c.Query("as") }
+		config.DefaultDomain = "hdr"
+		if code, _ := serve(t, config, routes, "GET", "/docs/1?as=u1", nil); code != 200 {
+			t.Fatalf("got %d, want 200", code)
+		}
+	})
+}
+
+func TestRouteAuthEnforceError(t *testing.T) {
+	e := newEnforcerFromString(t, `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && undefinedFunc(r.obj)
+`, []string{"u1", "/x", "GET"})
+	code, resp := serve(t, RouteAuthConfig{Enforcer: e, SubjectFunc: HeaderSubject("X-User-ID")}, []string{"/x"}, "GET", "/x", map[string]string{"X-User-ID": "u1"})
+	if code != http.StatusInternalServerError || resp.Code != CodeEnforceFailed {
+		t.Fatalf("got %d %+v", code, resp)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// 权限申请目标类型
+const (
+	AccessTargetRole  = "role"
+	AccessTargetGroup = "group"
+)
+
+// 权限申请状态
+const (
+	AccessStatusPending   = "pending"   // 待审批
+	AccessStatusApproved  = "approved"  // 已批准并授权
+	AccessStatusRejected  = "rejected"  // 已拒绝
+	AccessStatusCancelled = "cancelled" // 申请人已撤回
+	AccessStatusExpired   = "expired"   // 授权已到期并回收
+	AccessStatusRevoked   = "revoked"   // 授权被提前回收
+)
+
+// AccessRequest 权限申请，用户申请加入角色或用户组，审批通过后才授权
+type AccessRequest struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	RequesterID     uint       `json:"requester_id" gorm:"index;not null"`
+	TargetType      string     `json:"target_type" gorm:"size:20;not null"` // role, group
+	TargetID        uint       `json:"target_id" gorm:"not null"`
+	Domain          string     `json:"domain"` // 取自目标角色所在的域，用户组不区分域
+	Reason          string     `json:"reason"`
+	DurationSeconds int64      `json:"duration_seconds"` // 授权时长，0 表示永久
+	Status          string     `json:"status" gorm:"size:20;index;not null"`
+	ApproverID      *uint      `json:"approver_id"`
+	DecisionComment string     `json:"decision_comment"`
+	DecidedAt       *time.Time `json:"decided_at"`
+	ExpiresAt       *time.Time `json:"expires_at" gorm:"index"` // 审批通过后根据授权时长计算
+	GrantID         *uint      `json:"grant_id"`                // 授权时创建的用户角色或用户组成员记录，回收时只删除这一条
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AccessAuditLog 权限申请审计日志，记录申请、审批、回收的每一步
+type AccessAuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	RequestID uint      `json:"request_id" gorm:"index;not null"`
+	ActorID   uint      `json:"actor_id"` // 0 表示系统（如到期回收）
+	Action    string    `json:"action" gorm:"size:20;not null"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -34,12 +34,33 @@ type UserGroupMember struct {
 // Role 角色模型
 type Role struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name" gorm:"unique"`
+	Name        string    `json:"name" gorm:"uniqueIndex:idx_role_domain_name"`
 	Description string    `json:"description"`
+	Domain      string    `json:"domain" gorm:"uniqueIndex:idx_role_domain_name"` // 所属域，为空表示全局角色
+	ParentID    *uint     `json:"parent_id"`                                      // 父角色ID，继承父角色的权限
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// RoleTemplate 角色模板，按域预置一组权限，用于快速创建角色
+type RoleTemplate struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex:idx_role_template_domain_name"`
+	Domain      string    `json:"domain" gorm:"uniqueIndex:idx_role_template_domain_name"` // 为空表示所有域可用
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RoleTemplatePermission 角色模板中的权限
+type RoleTemplatePermission struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	TemplateID uint   `json:"template_id" gorm:"index"`
+	Object     string `json:"object"`
+	Action     string `json:"action"`
+	Effect     string `json:"effect" gorm:"default:allow"`
+}
+
 // UserRole 用户角色关系
 type UserRole struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -47,3 +68,14 @@ type UserRole struct {
 	RoleID    uint      `json:"role_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// UserProfile 用户属性（用于 ABAC）
+type UserProfile struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"uniqueIndex"`
+	Department string    `json:"department"`
+	Title      string    `json:"title"`
+	JoinDate   time.Time `json:"join_date"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz/permissionservice"
+)
+
+// maxCacheEntries 远程检查结果缓存的条目上限，超过后清空重新缓存
+const maxCacheEntries = 4096
+
+// Authorizer 客户端授权中间件使用的权限检查
+type Authorizer interface {
+	Authorize(ctx context.Context, req service.EnforceRequest) (bool, error)
+}
+
+// LocalAuthorizer 在本进程内通过 AuthService 检查，适用于持有策略的服务
+type LocalAuthorizer struct {
+	authService *service.AuthService
+}
+
+// NewLocalAuthorizer 创建本地权限检查
+func NewLocalAuthorizer(authService *service.AuthService) *LocalAuthorizer {
+	return &LocalAuthorizer{authService: authService}
+}
+
+// Authorize 实现 Authorizer
+func (a *LocalAuthorizer) Authorize(_ context.Context, req service.EnforceRequest) (bool, error) {
+	return a.authService.Enforce(req)
+}
+
+// RemoteAuthorizer 调用权限服务检查，ttl 大于 0 时在本地缓存结果
+// 缓存期间策略变更不会生效，ttl 应小于可以接受的权限生效延迟
+type RemoteAuthorizer struct {
+	cli permissionservice.Client
+	ttl time.Duration
+
+	mu    sync.Mutex
+	cache map[service.EnforceRequest]cachedResult
+}
+
+// cachedResult 缓存的检查结果
+type cachedResult struct {
+	allowed bool
+	expires time.Time
+}
+
+// NewRemoteAuthorizer 创建远程权限检查
+func NewRemoteAuthorizer(cli permissionservice.Client, ttl time.Duration) *RemoteAuthorizer {
+	return &RemoteAuthorizer{
+		cli:   cli,
+		ttl:   ttl,
+		cache: make(map[service.EnforceRequest]cachedResult),
+	}
+}
+
+// Authorize 实现 Authorizer
+func (a *RemoteAuthorizer) Authorize(ctx context.Context, req service.EnforceRequest) (bool, error) {
+	if a.ttl > 0 {
+		a.mu.Lock()
+		r, ok := a.cache[req]
+		a.mu.Unlock()
+		if ok && time.Now().Before(r.expires) {
+			return r.allowed, nil
+		}
+	}
+
+	resp, err := a.cli.Enforce(ctx, &authz.EnforceRequest{
+		Sub: req.Subject,
+		Dom: req.Domain,
+		Obj: req.Object,
+		Act: req.Action,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if a.ttl > 0 {
+		a.mu.Lock()
+		if len(a.cache) >= maxCacheEntries {
+			a.cache = make(map[service.EnforceRequest]cachedResult)
+		}
+		a.cache[req] = cachedResult{allowed: resp.Allowed, expires: time.Now().Add(a.ttl)}
+		a.mu.Unlock()
+	}
+	return resp.Allowed, nil
+}
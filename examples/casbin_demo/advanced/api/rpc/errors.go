@@ -0,0 +1,21 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+// 客户端授权中间件的错误，服务端授权中间件返回对应的 UNAUTHENTICATED、PERMISSION_DENIED 业务错误，与 HTTP 中间件的 CodeUnauthenticated、CodeForbidden 对应
+var (
+	// ErrUnauthenticated ctx 中没有调用方主体
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden 调用方没有权限调用该方法
+	ErrForbidden = errors.New("permission denied")
+)
+
+// bizError 创建业务错误，客户端通过 kerrors.FromBizStatusError 取出错误码
+func bizError(code authz.ErrorCode, msg string) error {
+	return kerrors.NewGRPCBizStatusError(int32(code), msg)
+}
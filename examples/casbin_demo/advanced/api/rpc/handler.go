@@ -0,0 +1,124 @@
+// Package rpc 以 Kitex 服务的形式提供权限检查，并提供客户端和服务端授权中间件，
+// 让服务间调用和 HTTP 请求使用同一套 Casbin 规则
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+// maxBatchSize 单次批量检查的数量上限
+const maxBatchSize = 200
+
+// PermissionServiceImpl 实现 authz.PermissionService，规则由 AuthService 检查
+type PermissionServiceImpl struct {
+	authService *service.AuthService
+}
+
+// NewPermissionService 创建权限服务
+func NewPermissionService(authService *service.AuthService) *PermissionServiceImpl {
+	return &PermissionServiceImpl{authService: authService}
+}
+
+// Enforce 检查一次权限
+func (s *PermissionServiceImpl) Enforce(ctx context.Context, req *authz.EnforceRequest) (*authz.EnforceResponse, error) {
+	if msg := validate(req); msg != "" {
+		return nil, bizError(authz.ErrorCode_INVALID_ARGUMENT, msg)
+	}
+
+	allowed, err := s.authService.Enforce(enforceRequest(req))
+	if err != nil {
+		return nil, bizError(authz.ErrorCode_ENFORCE_FAILED, err.Error())
+	}
+	return &authz.EnforceResponse{Allowed: allowed}, nil
+}
+
+// BatchEnforce 批量检查权限，任意一条请求不合法时整体失败
+func (s *PermissionServiceImpl) BatchEnforce(ctx context.Context, req *authz.BatchEnforceRequest) (*authz.BatchEnforceResponse, error) {
+	if len(req.Requests) > maxBatchSize {
+		return nil, bizError(authz.ErrorCode_TOO_MANY_REQUESTS, fmt.Sprintf("at most %d requests per batch", maxBatchSize))
+	}
+
+	reqs := make([]service.EnforceRequest, len(req.Requests))
+	for i, r := range req.Requests {
+		if msg := validate(r); msg != "" {
+			return nil, bizError(authz.ErrorCode_INVALID_ARGUMENT, fmt.Sprintf("request %d: %s", i, msg))
+		}
+		reqs[i] = enforceRequest(r)
+	}
+
+	allowed, err := s.authService.BatchEnforce(reqs)
+	if err != nil {
+		return nil, bizError(authz.ErrorCode_ENFORCE_FAILED, err.Error())
+	}
+	return &authz.BatchEnforceResponse{Allowed: allowed}, nil
+}
+
+// GetRoles 查询主体直接和间接继承的角色
+func (s *PermissionServiceImpl) GetRoles(ctx context.Context, req *authz.GetRolesRequest) (*authz.GetRolesResponse, error) {
+	if req.Sub == "" {
+		return nil, bizError(authz.ErrorCode_INVALID_ARGUMENT, "sub is required")
+	}
+	return &authz.GetRolesResponse{Roles: s.authService.GetSubjectRoles(req.Sub, req.Dom)}, nil
+}
+
+// GetPermissions 查询主体的有效权限
+func (s *PermissionServiceImpl) GetPermissions(ctx context.Context, req *authz.GetPermissionsRequest) (*authz.GetPermissionsResponse, error) {
+	if req.Sub == "" {
+		return nil, bizError(authz.ErrorCode_INVALID_ARGUMENT, "sub is required")
+	}
+
+	entries, err := s.authService.GetSubjectPermissions(req.Sub)
+	if err != nil {
+		return nil, bizError(authz.ErrorCode_ENFORCE_FAILED, err.Error())
+	}
+	perms := make([]*authz.Permission, len(entries))
+	for i, e := range entries {
+		perms[i] = &authz.Permission{
+			Dom:     e.Domain,
+			Obj:     e.Object,
+			Actions: e.Actions,
+			Effect:  e.Effect,
+			Sources: e.Sources,
+		}
+	}
+	return &authz.GetPermissionsResponse{Permissions: perms}, nil
+}
+
+// GetPolicies 查询直接作用于主体的策略
+func (s *PermissionServiceImpl) GetPolicies(ctx context.Context, req *authz.GetPoliciesRequest) (*authz.GetPoliciesResponse, error) {
+	if req.Sub == "" {
+		return nil, bizError(authz.ErrorCode_INVALID_ARGUMENT, "sub is required")
+	}
+	rules, err := s.authService.GetSubjectPolicies(req.Sub)
+	if err != nil {
+		return nil, bizError(authz.ErrorCode_ENFORCE_FAILED, err.Error())
+	}
+	policies := make([]*authz.Policy, len(rules))
+	for i, rule := range rules {
+		policies[i] = &authz.Policy{Fields: rule}
+	}
+	return &authz.GetPoliciesResponse{Policies: policies}, nil
+}
+
+// validate 检查必填字段，返回错误说明，dom 在不带域的模型中可以为空
+func validate(req *authz.EnforceRequest) string {
+	switch {
+	case req == nil:
+		return "empty request"
+	case req.Sub == "":
+		return "sub is required"
+	case req.Obj == "":
+		return "obj is required"
+	case req.Act == "":
+		return "act is required"
+	}
+	return ""
+}
+
+func enforceRequest(req *authz.EnforceRequest) service.EnforceRequest {
+	return service.EnforceRequest{Subject: req.Sub, Domain: req.Dom, Object: req.Obj, Action: req.Act}
+}
@@ -0,0 +1,25 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+func TestGetPoliciesRequiresSub(t *testing.T) {
+	s := NewPermissionService(newTestAuthService(t))
+
+	_, err := s.GetPolicies(context.Background(), &authz.GetPoliciesRequest{})
+	if code := bizCode(err); code != authz.ErrorCode_INVALID_ARGUMENT {
+		t.Fatalf("GetPolicies(\"\") err = %v, want INVALID_ARGUMENT", err)
+	}
+
+	resp, err := s.GetPolicies(context.Background(), &authz.GetPoliciesRequest{Sub: "role:calc_caller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Policies) != 2 {
+		t.Errorf("policies = %v, want 2 rules of role:calc_caller", resp.Policies)
+	}
+}
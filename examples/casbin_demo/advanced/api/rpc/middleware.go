@@ -0,0 +1,185 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+// ActionCall 服务间调用的操作
+const ActionCall = "CALL"
+
+// SubjectKey 调用方主体在 metainfo 中的键，只传递给直接下游
+// gRPC 传输会把键转换为 HTTP 头再转换回来，只有大写加下划线的键能保持不变
+const SubjectKey = "AUTH_SUBJECT"
+
+// WithSubject 指定本次调用代表的主体（如代表 user:1 发起调用），下游可以通过 SubjectFromContext 取出
+func WithSubject(ctx context.Context, sub string) context.Context {
+	return metainfo.WithValue(ctx, SubjectKey, sub)
+}
+
+// SubjectFromContext 返回 WithSubject 设置的主体，服务端收到的上游主体也可以这样取出
+func SubjectFromContext(ctx context.Context) string {
+	sub, _ := metainfo.GetValue(ctx, SubjectKey)
+	return sub
+}
+
+// ClientAuthConfig 客户端授权中间件配置
+type ClientAuthConfig struct {
+	Authorizer Authorizer
+
+	// Subject 本服务的身份，如 svc:order，ctx 中没有主体时使用
+	Subject string
+	// SubjectFunc 获取调用方主体，默认优先使用 WithSubject 设置的主体，其次使用 Subject
+	SubjectFunc func(ctx context.Context) string
+
+	// Domain 权限检查使用的域
+	Domain string
+	// Action 权限检查使用的操作，默认 CALL
+	Action string
+	// Skip 跳过检查的方法名
+	Skip []string
+}
+
+// ClientAuth 创建 Kitex 客户端授权中间件，调用前检查调用方是否有权限调用目标方法
+// 对象为 /rpc/<服务名>/<方法名>，与 HTTP 接口的路由模板写在同一套策略中；
+// 没有权限时不发出请求，返回包装了 ErrForbidden 的错误
+func ClientAuth(config ClientAuthConfig) endpoint.Middleware {
+	if config.SubjectFunc == nil {
+		config.SubjectFunc = func(ctx context.Context) string {
+			if sub := SubjectFromContext(ctx); sub != "" {
+				return sub
+			}
+			return config.Subject
+		}
+	}
+	if config.Action == "" {
+		config.Action = ActionCall
+	}
+
+	skip := methodSet(config.Skip)
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			ri := rpcinfo.GetRPCInfo(ctx)
+			if ri == nil || skip[ri.To().Method()] {
+				return next(ctx, req, resp)
+			}
+
+			sub := config.SubjectFunc(ctx)
+			if sub == "" {
+				return ErrUnauthenticated
+			}
+
+			er := service.EnforceRequest{
+				Subject: sub,
+				Domain:  config.Domain,
+				Object:  Object(ri.To().ServiceName(), ri.To().Method()),
+				Action:  config.Action,
+			}
+			allowed, err := config.Authorizer.Authorize(ctx, er)
+			if err != nil {
+				return fmt.Errorf("authorize %s: %w", er.Object, err)
+			}
+			if !allowed {
+				return fmt.Errorf("%w: %s cannot %s %s", ErrForbidden, er.Subject, er.Action, er.Object)
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// ServerAuthConfig 服务端授权中间件配置
+type ServerAuthConfig struct {
+	Authorizer Authorizer
+
+	// SubjectFunc 获取调用方主体，默认使用上游通过 WithSubject 声明的主体
+	// 声明的主体由调用方自行填写，不能证明调用方的身份；跨信任边界部署时应在这里校验凭证
+	// （如 mTLS 证书、签名令牌）后返回主体，校验失败返回空字符串
+	SubjectFunc func(ctx context.Context) string
+
+	// Domain 权限检查使用的域
+	Domain string
+	// Action 权限检查使用的操作，默认 CALL
+	Action string
+	// Skip 跳过检查的方法名
+	Skip []string
+}
+
+// ServerAuth 创建 Kitex 服务端授权中间件，执行方法前检查调用方是否有权限调用本服务的方法
+// 对象与 ClientAuth 相同，服务名取自 server.WithServerBasicInfo；ClientAuth 只能拦住使用中间件的客户端，
+// 服务端仍需要 ServerAuth 拒绝直接发来的请求。没有主体时返回 UNAUTHENTICATED 业务错误，没有权限时返回 PERMISSION_DENIED 业务错误
+func ServerAuth(config ServerAuthConfig) endpoint.Middleware {
+	if config.SubjectFunc == nil {
+		config.SubjectFunc = SubjectFromContext
+	}
+	if config.Action == "" {
+		config.Action = ActionCall
+	}
+	skip := methodSet(config.Skip)
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			ri := rpcinfo.GetRPCInfo(ctx)
+			if ri == nil {
+				return bizError(authz.ErrorCode_UNAUTHENTICATED, "missing rpc info")
+			}
+			method := ri.Invocation().MethodName()
+			if skip[method] {
+				return next(ctx, req, resp)
+			}
+
+			sub := config.SubjectFunc(ctx)
+			if sub == "" {
+				return reject(ri, authz.ErrorCode_UNAUTHENTICATED, "subject is required")
+			}
+
+			er := service.EnforceRequest{
+				Subject: sub,
+				Domain:  config.Domain,
+				Object:  Object(ri.To().ServiceName(), method),
+				Action:  config.Action,
+			}
+			allowed, err := config.Authorizer.Authorize(ctx, er)
+			if err != nil {
+				return reject(ri, authz.ErrorCode_ENFORCE_FAILED, fmt.Sprintf("authorize %s: %v", er.Object, err))
+			}
+			if !allowed {
+				return reject(ri, authz.ErrorCode_PERMISSION_DENIED, fmt.Sprintf("%s cannot %s %s", er.Subject, er.Action, er.Object))
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// reject 把业务错误写入 Invocation 后返回 nil，与 Kitex 处理方法返回的业务错误一致；
+// 中间件直接返回的错误会被当作框架错误，客户端取不到错误码
+func reject(ri rpcinfo.RPCInfo, code authz.ErrorCode, msg string) error {
+	err := bizError(code, msg)
+	bizErr, _ := kerrors.FromBizStatusError(err)
+	if setter, ok := ri.Invocation().(rpcinfo.InvocationSetter); ok {
+		setter.SetBizStatusErr(bizErr)
+		return nil
+	}
+	return err
+}
+
+// methodSet 把方法名列表转换为集合
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+	return set
+}
+
+// Object 返回服务方法对应的权限对象
+func Object(serviceName, method string) string {
+	return "/rpc/" + serviceName + "/" + method
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+func newTestAuthService(t *testing.T) *service.AuthService {
+	t.Helper()
+	e, err := casbin.NewEnforcer("../../rpc_model.conf", "../../rpc_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service.NewAuthService(nil, e)
+}
+
+// callServer 以 sub 的身份调用服务端 method，返回是否执行了方法和返回给客户端的业务错误码
+func callServer(t *testing.T, mw endpoint.Middleware, method, sub string) (bool, authz.ErrorCode) {
+	t.Helper()
+	to := rpcinfo.NewEndpointInfo("authz-service", method, nil, nil)
+	ri := rpcinfo.NewRPCInfo(nil, to, rpcinfo.NewInvocation("authz-service", method), nil, nil)
+	ctx := rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+	if sub != "" {
+		ctx = WithSubject(ctx, sub)
+	}
+
+	called := false
+	err := mw(func(ctx context.Context, req, resp interface{}) error {
+		called = true
+		return nil
+	})(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("middleware returned %v, want biz error set on invocation", err)
+	}
+	if bizErr := ri.Invocation().BizStatusErr(); bizErr != nil {
+		return called, authz.ErrorCode(bizErr.BizStatusCode())
+	}
+	return called, authz.ErrorCode_OK
+}
+
+// bizCode 返回业务错误码，不是业务错误时返回 OK
+func bizCode(err error) authz.ErrorCode {
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		return authz.ErrorCode(bizErr.BizStatusCode())
+	}
+	return authz.ErrorCode_OK
+}
+
+func TestServerAuth(t *testing.T) {
+	mw := ServerAuth(ServerAuthConfig{
+		Authorizer: NewLocalAuthorizer(newTestAuthService(t)),
+		Domain:     "platform",
+		Skip:       []string{"Enforce"},
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		sub      string
+		wantCode authz.ErrorCode
+	}{
+		{name: "admin", method: "GetPolicies", sub: "user:1", wantCode: authz.ErrorCode_OK},
+		{name: "no permission", method: "GetPolicies", sub: "svc:order", wantCode: authz.ErrorCode_PERMISSION_DENIED},
+		{name: "no subject", method: "GetPolicies", wantCode: authz.ErrorCode_UNAUTHENTICATED},
+		{name: "skipped method", method: "Enforce", wantCode: authz.ErrorCode_OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called, code := callServer(t, mw, tt.method, tt.sub)
+			if code != tt.wantCode {
+				t.Errorf("code = %v, want %v", code, tt.wantCode)
+			}
+			if want := tt.wantCode == authz.ErrorCode_OK; called != want {
+				t.Errorf("method called = %v, want %v", called, want)
+			}
+		})
+	}
+}
+
+func TestServerAuthSubjectFunc(t *testing.T) {
+	// 只信任校验过凭证的主体，调用方自行声明的主体被忽略
+	mw := ServerAuth(ServerAuthConfig{
+		Authorizer:  NewLocalAuthorizer(newTestAuthService(t)),
+		SubjectFunc: func(ctx context.Context) string { return "" },
+		Domain:      "platform",
+	})
+	if _, code := callServer(t, mw, "GetPolicies", "user:1"); code != authz.ErrorCode_UNAUTHENTICATED {
+		t.Errorf("code = %v, want UNAUTHENTICATED", code)
+	}
+}
+
+type errAuthorizer struct{ err error }
+
+func (a errAuthorizer) Authorize(context.Context, service.EnforceRequest) (bool, error) {
+	return false, a.err
+}
+
+func TestServerAuthAuthorizerError(t *testing.T) {
+	mw := ServerAuth(ServerAuthConfig{Authorizer: errAuthorizer{errors.New("unavailable")}})
+	if _, code := callServer(t, mw, "GetRoles", "user:1"); code != authz.ErrorCode_ENFORCE_FAILED {
+		t.Errorf("code = %v, want ENFORCE_FAILED", code)
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+	"gorm.io/gorm"
+)
+
+// SubjectAttributes 主体属性，规则中通过 r.sub.X 访问
+type SubjectAttributes struct {
+	ID         uint
+	Name       string
+	Department string
+	Title      string
+	JoinDate   time.Time
+	TenureDays int // 入职天数，代替规则中的 time.Now().Sub(...)
+}
+
+// ResourceAttributes 资源属性，规则中通过 r.obj.X 访问
+type ResourceAttributes struct {
+	Name    string // 资源路径，与策略中的 obj 匹配
+	Type    string // public, private
+	OwnerID uint
+}
+
+// EnvironmentAttributes 环境属性，规则中通过 r.env.X 访问
+type EnvironmentAttributes struct {
+	Time    time.Time
+	Hour    int // 0-23
+	Weekday int // 0 表示周日
+	IP      string
+}
+
+// AttributeProvider 属性提供者，负责为 ABAC 检查加载主体、资源和环境属性
+type AttributeProvider interface {
+	Subject(ctx context.Context, userID uint) (*SubjectAttributes, error)
+	Resource(ctx context.Context, obj string) (*ResourceAttributes, error)
+	Environment(ctx context.Context) (*EnvironmentAttributes, error)
+}
+
+// ErrMissingEnvironment 请求上下文中没有环境属性或来源 IP，规则无法判断网络位置，按拒绝处理
+var ErrMissingEnvironment = errors.New("缺少请求环境属性（来源 IP），请使用 WithEnvironment 写入 context")
+
+type envContextKey struct{}
+
+// WithEnvironment 将请求环境（来源 IP、请求时间）写入 context
+func WithEnvironment(ctx context.Context, ip string, at time.Time) context.Context {
+	return context.WithValue(ctx, envContextKey{}, &EnvironmentAttributes{
+		Time:    at,
+		Hour:    at.Hour(),
+		Weekday: int(at.Weekday()),
+		IP:      ip,
+	})
+}
+
+// DBAttributeProvider 从数据库加载用户和文档属性，从 context 加载环境属性
+type DBAttributeProvider struct {
+	db *gorm.DB
+}
+
+// NewDBAttributeProvider 创建数据库属性提供者
+func NewDBAttributeProvider(db *gorm.DB) *DBAttributeProvider {
+	return &DBAttributeProvider{db: db}
+}
+
+// Subject 加载用户及其 UserProfile
+func (p *DBAttributeProvider) Subject(ctx context.Context, userID uint) (*SubjectAttributes, error) {
+	var user models.User
+	if err := p.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("用户不存在: %w", err)
+	}
+
+	attrs := &SubjectAttributes{
+		ID:   user.ID,
+		Name: user.Username,
+	}
+
+	var profile models.UserProfile
+	err := p.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("获取用户属性失败: %w", err)
+	}
+	if err == nil {
+		attrs.Department = profile.Department
+		attrs.Title = profile.Title
+		attrs.JoinDate = profile.JoinDate
+		if !profile.JoinDate.IsZero() {
+			attrs.TenureDays = int(time.Since(profile.JoinDate).Hours() / 24)
+		}
+	}
+	return attrs, nil
+}
+
+var documentPathPattern = regexp.MustCompile(`/documents/(?:\w+/)?(\d+)$`)
+
+// Resource 加载资源属性，文档路径会补充文档类型和创建者
+func (p *DBAttributeProvider) Resource(ctx context.Context, obj string) (*ResourceAttributes, error) {
+	attrs := &ResourceAttributes{Name: obj}
+
+	m := documentPathPattern.FindStringSubmatch(obj)
+	if m == nil {
+		return attrs, nil
+	}
+	docID, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return attrs, nil
+	}
+
+	var doc models.Document
+	if err := p.db.WithContext(ctx).First(&doc, docID).Error; err != nil {
+		return nil, fmt.Errorf("文档不存在: %w", err)
+	}
+	attrs.Type = doc.Type
+	attrs.OwnerID = doc.CreatorID
+	return attrs, nil
+}
+
+// Environment 从 context 读取环境属性，未设置或来源 IP 为空时返回 ErrMissingEnvironment
+func (p *DBAttributeProvider) Environment(ctx context.Context) (*EnvironmentAttributes, error) {
+	env, ok := ctx.Value(envContextKey{}).(*EnvironmentAttributes)
+	if !ok || env.IP == "" {
+		return nil, ErrMissingEnvironment
+	}
+	return env, nil
+}
+
+// ABACService 基于属性的权限服务
+// 模型见 abac_attr_model.conf：r = sub, obj, act, env；p = rule, obj, act
+type ABACService struct {
+	enforcer *casbin.Enforcer
+	provider AttributeProvider
+}
+
+// NewABACService 创建 ABAC 服务，并校验已加载的所有规则
+func NewABACService(enforcer *casbin.Enforcer, provider AttributeProvider) (*ABACService, error) {
+	for _, policy := range enforcer.GetPolicy() {
+		if len(policy) == 0 {
+			continue
+		}
+		if err := ValidateABACRule(policy[0]); err != nil {
+			return nil, fmt.Errorf("策略 %v 不合法: %w", policy, err)
+		}
+	}
+
+	return &ABACService{
+		enforcer: enforcer,
+		provider: provider,
+	}, nil
+}
+
+// AddRule 添加 ABAC 规则，规则在写入前会经过校验
+func (s *ABACService) AddRule(rule, obj, act string) error {
+	if err := ValidateABACRule(rule); err != nil {
+		return err
+	}
+
+	if _, err := s.enforcer.AddPolicy(rule, obj, act); err != nil {
+		return fmt.Errorf("添加策略失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveRule 删除 ABAC 规则
+func (s *ABACService) RemoveRule(rule, obj, act string) error {
+	if _, err := s.enforcer.RemovePolicy(rule, obj, act); err != nil {
+		return fmt.Errorf("删除策略失败: %w", err)
+	}
+	return nil
+}
+
+// Enforce 加载属性后执行 ABAC 检查
+func (s *ABACService) Enforce(ctx context.Context, userID uint, obj, act string) (bool, error) {
+	sub, err := s.provider.Subject(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	res, err := s.provider.Resource(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	env, err := s.provider.Environment(ctx)
+	if err != nil {
+		return false, err
+	}
+	// 内置 ipMatch 遇到非法 IP 会 panic，在进入规则前拒绝
+	if env == nil || env.IP == "" {
+		return false, ErrMissingEnvironment
+	}
+	if net.ParseIP(env.IP) == nil {
+		return false, fmt.Errorf("来源 IP 不合法: %q", env.IP)
+	}
+
+	return s.enforcer.Enforce(sub, res, act, env)
+}
+
+// abacRuleFunctions 规则中允许调用的函数
+var abacRuleFunctions = map[string]govaluate.ExpressionFunction{
+	"ipMatch":    util.IPMatchFunc,
+	"regexMatch": util.RegexMatchFunc,
+	"keyMatch":   util.KeyMatchFunc,
+	"keyMatch2":  util.KeyMatch2Func,
+	"globMatch":  util.GlobMatchFunc,
+}
+
+// abacRuleAttributes 规则中允许访问的属性：r.sub / r.obj / r.env 的导出字段
+var abacRuleAttributes = map[string]map[string]bool{
+	"r_sub": structFields(SubjectAttributes{}),
+	"r_obj": structFields(ResourceAttributes{}),
+	"r_env": structFields(EnvironmentAttributes{}),
+}
+
+// ValidateABACRule 校验 eval 规则，只允许访问白名单属性、调用白名单函数，
+// 禁止方法调用、未知变量以及 time.Now() 之类的任意表达式
+func ValidateABACRule(rule string) error {
+	if rule == "" {
+		return fmt.Errorf("规则不能为空")
+	}
+
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(util.EscapeAssertion(rule), abacRuleFunctions)
+	if err != nil {
+		return fmt.Errorf("规则语法错误: %w", err)
+	}
+
+	for _, token := range expr.Tokens() {
+		switch token.Kind {
+		case govaluate.ACCESSOR:
+			path, _ := token.Value.([]string)
+			if len(path) != 2 {
+				return fmt.Errorf("不允许的属性访问: %v", token.Value)
+			}
+			fields, ok := abacRuleAttributes[path[0]]
+			if !ok || !fields[path[1]] {
+				return fmt.Errorf("未知属性: %s.%s", strings.Replace(path[0], "_", ".", 1), path[1])
+			}
+		case govaluate.VARIABLE:
+			return fmt.Errorf("不允许的变量: %v", token.Value)
+		}
+	}
+	return nil
+}
+
+func structFields(v interface{}) map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			fields[t.Field(i).Name] = true
+		}
+	}
+	return fields
+}
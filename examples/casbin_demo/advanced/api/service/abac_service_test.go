@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// staticProvider 返回固定属性的 AttributeProvider
+type staticProvider struct {
+	sub *SubjectAttributes
+	env *EnvironmentAttributes
+}
+
+func (p staticProvider) Subject(context.Context, uint) (*SubjectAttributes, error) {
+	return p.sub, nil
+}
+
+func (p staticProvider) Resource(_ context.Context, obj string) (*ResourceAttributes, error) {
+	return &ResourceAttributes{Name: obj}, nil
+}
+
+func (p staticProvider) Environment(context.Context) (*EnvironmentAttributes, error) {
+	return p.env, nil
+}
+
+func newABACTestService(t *testing.T, provider AttributeProvider) *ABACService {
+	t.Helper()
+	e, err := casbin.NewEnforcer("../../abac_attr_model.conf", "../../abac_attr_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewABACService(e, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestDBAttributeProviderEnvironment(t *testing.T) {
+	p := NewDBAttributeProvider(nil)
+	at := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	if _, err := p.Environment(context.Background()); !errors.Is(err, ErrMissingEnvironment) {
+		t.Fatalf("no env: err = %v, want ErrMissingEnvironment", err)
+	}
+	if _, err := p.Environment(WithEnvironment(context.Background(), "", at)); !errors.Is(err, ErrMissingEnvironment) {
+		t.Fatalf("empty ip: err = %v, want ErrMissingEnvironment", err)
+	}
+	env, err := p.Environment(WithEnvironment(context.Background(), "10.1.2.3", at))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.IP != "10.1.2.3" || env.Hour != 10 || env.Weekday != int(time.Monday) {
+		t.Fatalf("env = %+v", env)
+	}
+}
+
+func TestABACEnforceIPRule(t *testing.T) {
+	veteran := &SubjectAttributes{ID: 1, TenureDays: 800}
+	tests := []struct {
+		name    string
+		ip      string
+		want    bool
+		wantErr bool
+	}{
+		{name: "intranet", ip: "10.1.2.3", want: true},
+		{name: "outside", ip: "192.168.1.1", want: false},
+		// 内置 ipMatch 对空或非法 IP 会 panic，Enforce 应当直接返回错误
+		{name: "empty ip", ip: "", wantErr: true},
+		{name: "invalid ip", ip: "not-an-ip", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newABACTestService(t, staticProvider{sub: veteran, env: &EnvironmentAttributes{IP: tt.ip}})
+			ok, err := s.Enforce(context.Background(), 1, "/api/internal/report", "access")
+			if tt.wantErr {
+				if err == nil || ok || strings.Contains(err.Error(), "panic") {
+					t.Fatalf("Enforce = %v, %v, want a plain error", ok, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Fatalf("Enforce = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateABACRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		wantErr bool
+	}{
+		{rule: "r.sub.Department == 'IT' && r.sub.Title == 'Senior Engineer'"},
+		{rule: "r.sub.TenureDays > 730 && ipMatch(r.env.IP, '10.0.0.0/8')"},
+		{rule: "r.obj.Type == 'private' && r.obj.OwnerID == r.sub.ID"},
+		{rule: "r.env.Hour >= 9 && r.env.Hour < 18 && regexMatch(r.obj.Name, '^/api/')"},
+		{rule: "", wantErr: true},
+		{rule: "r.sub.Department ==", wantErr: true},
+		{rule: "r.sub.Salary > 100", wantErr: true},
+		{rule: "r.user.Name == 'x'", wantErr: true},
+		{rule: "r.sub.JoinDate.Year > 2020", wantErr: true},
+		{rule: "secret == 'x'", wantErr: true},
+		{rule: "exec('rm -rf /')", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := ValidateABACRule(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateABACRule(%q) err = %v, wantErr %v", tt.rule, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 审计日志中的操作
+const (
+	AuditActionRequest = "request"
+	AuditActionApprove = "approve"
+	AuditActionReject  = "reject"
+	AuditActionCancel  = "cancel"
+	AuditActionExpire  = "expire"
+	AuditActionRevoke  = "revoke"
+)
+
+// ApproveAction 审批权限在 Casbin 中对应的操作
+// 审批人需要拥有 (user:审批人, 域, /access-requests/role/:id 或 /access-requests/group/:id, approve) 权限，
+// 域取自目标角色，用户组申请的域为空，例如:
+// p, role:security, platform, /access-requests/role/*, approve, allow
+// p, role:security, , /access-requests/group/*, approve, allow
+const ApproveAction = "approve"
+
+// AccessRequestService 权限申请审批流程：申请 -> 审批/拒绝 -> 授权 -> 到期回收
+// 授权和回收的数据库变更与申请状态在同一事务中提交，Casbin 变更在事务成功后执行，每一步都会写入审计日志
+type AccessRequestService struct {
+	db          *gorm.DB
+	authService *AuthService
+}
+
+// NewAccessRequestService 创建权限申请服务
+func NewAccessRequestService(db *gorm.DB, authService *AuthService) *AccessRequestService {
+	return &AccessRequestService{
+		db:          db,
+		authService: authService,
+	}
+}
+
+// RequestAccess 提交权限申请，duration 为 0 表示永久授权
+// 申请的域取自目标角色，domain 不为空时必须与之一致，避免申请人选择审批人所在的域
+func (s *AccessRequestService) RequestAccess(requesterID uint, targetType string, targetID uint, domain, reason string, duration time.Duration) (*models.AccessRequest, error) {
+	if duration < 0 {
+		return nil, fmt.Errorf("授权时长不能为负数")
+	}
+
+	var request *models.AccessRequest
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, requesterID).Error; err != nil {
+			return fmt.Errorf("用户不存在: %w", err)
+		}
+
+		targetDomain, err := s.targetDomain(tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if domain != "" && domain != targetDomain {
+			return fmt.Errorf("%s不属于域 %s", targetName(targetType), domain)
+		}
+
+		granted, err := s.hasGrant(tx, requesterID, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if granted {
+			return fmt.Errorf("用户已拥有该%s", targetName(targetType))
+		}
+
+		var pending int64
+		if err := tx.Model(&models.AccessRequest{}).
+			Where("requester_id = ? AND target_type = ? AND target_id = ? AND status = ?",
+				requesterID, targetType, targetID, models.AccessStatusPending).
+			Count(&pending).Error; err != nil {
+			return fmt.Errorf("检查待审批申请失败: %w", err)
+		}
+		if pending > 0 {
+			return fmt.Errorf("已有待审批的相同申请")
+		}
+
+		request = &models.AccessRequest{
+			RequesterID:     requesterID,
+			TargetType:      targetType,
+			TargetID:        targetID,
+			Domain:          targetDomain,
+			Reason:          reason,
+			DurationSeconds: int64(duration / time.Second),
+			Status:          models.AccessStatusPending,
+		}
+		if err := tx.Create(request).Error; err != nil {
+			return fmt.Errorf("创建权限申请失败: %w", err)
+		}
+		return s.audit(tx, request.ID, requesterID, AuditActionRequest, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Approve 审批通过并授权，审批人由 Casbin 判定，且不能审批自己的申请
+func (s *AccessRequestService) Approve(requestID, approverID uint, comment string) (*models.AccessRequest, error) {
+	return s.decide(requestID, approverID, comment, true)
+}
+
+// Reject 拒绝申请
+func (s *AccessRequestService) Reject(requestID, approverID uint, comment string) (*models.AccessRequest, error) {
+	return s.decide(requestID, approverID, comment, false)
+}
+
+// Cancel 申请人撤回待审批的申请
+func (s *AccessRequestService) Cancel(requestID, requesterID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		request, err := s.lockRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.RequesterID != requesterID {
+			return fmt.Errorf("只能撤回自己的申请")
+		}
+		if request.Status != models.AccessStatusPending {
+			return fmt.Errorf("申请状态为 %s，无法撤回", request.Status)
+		}
+
+		if err := tx.Model(request).Update("status", models.AccessStatusCancelled).Error; err != nil {
+			return fmt.Errorf("撤回申请失败: %w", err)
+		}
+		return s.audit(tx, requestID, requesterID, AuditActionCancel, "")
+	})
+}
+
+// Revoke 提前回收已批准的授权
+func (s *AccessRequestService) Revoke(requestID, operatorID uint, comment string) error {
+	return withPolicyTransaction(s.db, s.authService.enforcer, func(tx *gorm.DB, changes *policyChanges) error {
+		request, err := s.lockRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != models.AccessStatusApproved {
+			return fmt.Errorf("申请状态为 %s，无法回收", request.Status)
+		}
+		if err := s.checkApprover(request, operatorID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(request).Update("status", models.AccessStatusRevoked).Error; err != nil {
+			return fmt.Errorf("更新申请状态失败: %w", err)
+		}
+		if err := s.audit(tx, requestID, operatorID, AuditActionRevoke, comment); err != nil {
+			return err
+		}
+		return s.revoke(tx, changes, request)
+	})
+}
+
+// ExpireGrants 回收所有到期的授权，返回回收的数量
+func (s *AccessRequestService) ExpireGrants(now time.Time) (int, error) {
+	var expired []models.AccessRequest
+	err := s.db.Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.AccessStatusApproved, now).
+		Find(&expired).Error
+	if err != nil {
+		return 0, fmt.Errorf("获取到期授权失败: %w", err)
+	}
+
+	count := 0
+	for _, r := range expired {
+		expired := false
+		err := withPolicyTransaction(s.db, s.authService.enforcer, func(tx *gorm.DB, changes *policyChanges) error {
+			request, err := s.lockRequest(tx, r.ID)
+			if err != nil {
+				return err
+			}
+			// 可能已被其他实例回收
+			if request.Status != models.AccessStatusApproved {
+				return nil
+			}
+
+			if err := tx.Model(request).Update("status", models.AccessStatusExpired).Error; err != nil {
+				return fmt.Errorf("更新申请状态失败: %w", err)
+			}
+			if err := s.audit(tx, request.ID, 0, AuditActionExpire, fmt.Sprintf("到期时间 %s", request.ExpiresAt.Format(time.RFC3339))); err != nil {
+				return err
+			}
+			if err := s.revoke(tx, changes, request); err != nil {
+				return err
+			}
+			expired = true
+			return nil
+		})
+		if err != nil {
+			return count, fmt.Errorf("回收授权 %d 失败: %w", r.ID, err)
+		}
+		if expired {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// StartExpiryWorker 定期回收到期授权，直到 ctx 被取消
+func (s *AccessRequestService) StartExpiryWorker(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if n, err := s.ExpireGrants(now); err != nil {
+					log.Printf("回收到期授权失败: %v", err)
+				} else if n > 0 {
+					log.Printf("已回收 %d 个到期授权", n)
+				}
+			}
+		}
+	}()
+}
+
+// ListRequests 按状态查询申请，status 为空时返回全部
+func (s *AccessRequestService) ListRequests(status string) ([]models.AccessRequest, error) {
+	var requests []models.AccessRequest
+	query := s.db.Order("id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("获取权限申请失败: %w", err)
+	}
+	return requests, nil
+}
+
+// ListPendingForApprover 返回审批人有权审批的待审批申请
+func (s *AccessRequestService) ListPendingForApprover(approverID uint) ([]models.AccessRequest, error) {
+	pending, err := s.ListRequests(models.AccessStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.AccessRequest, 0, len(pending))
+	for _, r := range pending {
+		if s.checkApprover(&r, approverID) == nil {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// GetAuditLog 获取申请的审计日志
+func (s *AccessRequestService) GetAuditLog(requestID uint) ([]models.AccessAuditLog, error) {
+	var logs []models.AccessAuditLog
+	if err := s.db.Where("request_id = ?", requestID).Order("id").Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("获取审计日志失败: %w", err)
+	}
+	return logs, nil
+}
+
+// decide 审批或拒绝申请
+func (s *AccessRequestService) decide(requestID, approverID uint, comment string, approve bool) (*models.AccessRequest, error) {
+	var result *models.AccessRequest
+	err := withPolicyTransaction(s.db, s.authService.enforcer, func(tx *gorm.DB, changes *policyChanges) error {
+		request, err := s.lockRequest(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != models.AccessStatusPending {
+			return fmt.Errorf("申请状态为 %s，无法审批", request.Status)
+		}
+		if err := s.checkApprover(request, approverID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		request.ApproverID = &approverID
+		request.DecisionComment = comment
+		request.DecidedAt = &now
+
+		action := AuditActionReject
+		request.Status = models.AccessStatusRejected
+		if approve {
+			action = AuditActionApprove
+			request.Status = models.AccessStatusApproved
+			if request.DurationSeconds > 0 {
+				expiresAt := now.Add(time.Duration(request.DurationSeconds) * time.Second)
+				request.ExpiresAt = &expiresAt
+			}
+			if err := s.grant(tx, changes, request); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(request).Error; err != nil {
+			return fmt.Errorf("更新申请失败: %w", err)
+		}
+		if err := s.audit(tx, requestID, approverID, action, comment); err != nil {
+			return err
+		}
+		result = request
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkApprover 检查审批人是否有权审批该申请
+func (s *AccessRequestService) checkApprover(request *models.AccessRequest, approverID uint) error {
+	if approverID == request.RequesterID {
+		return fmt.Errorf("不能审批自己的申请")
+	}
+
+	obj := fmt.Sprintf("/access-requests/%s/%d", request.TargetType, request.TargetID)
+	allowed, err := s.authService.CheckPermission(approverID, request.Domain, obj, ApproveAction)
+	if err != nil {
+		return fmt.Errorf("检查审批权限失败: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("没有审批该申请的权限")
+	}
+	return nil
+}
+
+// grant 按申请类型授权，记录本次创建的关系以便回收时只删除这一条
+func (s *AccessRequestService) grant(tx *gorm.DB, changes *policyChanges, request *models.AccessRequest) error {
+	member := fmt.Sprintf("user:%d", request.RequesterID)
+	switch request.TargetType {
+	case models.AccessTargetRole:
+		var role models.Role
+		if err := tx.First(&role, request.TargetID).Error; err != nil {
+			return fmt.Errorf("角色不存在: %w", err)
+		}
+		if role.Domain != request.Domain {
+			return fmt.Errorf("角色已不属于域 %s", request.Domain)
+		}
+		userRole := models.UserRole{UserID: request.RequesterID, RoleID: role.ID}
+		if err := tx.Create(&userRole).Error; err != nil {
+			return fmt.Errorf("分配角色失败: %w", err)
+		}
+		request.GrantID = &userRole.ID
+		changes.AddGroupingPolicy(s.authService.groupingRule(member, role.Name, role.Domain))
+	case models.AccessTargetGroup:
+		if err := tx.First(&models.UserGroup{}, request.TargetID).Error; err != nil {
+			return fmt.Errorf("用户组不存在: %w", err)
+		}
+		groupMember := models.UserGroupMember{UserID: request.RequesterID, GroupID: request.TargetID}
+		if err := tx.Create(&groupMember).Error; err != nil {
+			return fmt.Errorf("添加用户到用户组失败: %w", err)
+		}
+		request.GrantID = &groupMember.ID
+		changes.AddGroupingPolicy([]string{member, fmt.Sprintf("group:%d", request.TargetID)})
+	default:
+		return fmt.Errorf("未知的申请类型: %s", request.TargetType)
+	}
+	return nil
+}
+
+// revoke 按申请类型回收授权
+// 只删除本次申请创建的关系，用户通过其他途径（直接分配、其他申请）仍持有该角色或用户组时保留 Casbin 中的关系
+func (s *AccessRequestService) revoke(tx *gorm.DB, changes *policyChanges, request *models.AccessRequest) error {
+	member := fmt.Sprintf("user:%d", request.RequesterID)
+	var remaining int64
+	switch request.TargetType {
+	case models.AccessTargetRole:
+		var role models.Role
+		if err := tx.First(&role, request.TargetID).Error; err != nil {
+			// 角色已被删除时，DeleteRole 已经清理了用户角色关系和 Casbin 规则
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("获取角色失败: %w", err)
+		}
+		query := tx.Where("user_id = ? AND role_id = ?", request.RequesterID, role.ID)
+		if request.GrantID != nil {
+			query = query.Where("id = ?", *request.GrantID)
+		}
+		if err := query.Delete(&models.UserRole{}).Error; err != nil {
+			return fmt.Errorf("移除用户角色失败: %w", err)
+		}
+		if err := tx.Model(&models.UserRole{}).
+			Where("user_id = ? AND role_id = ?", request.RequesterID, role.ID).
+			Count(&remaining).Error; err != nil {
+			return fmt.Errorf("检查用户角色失败: %w", err)
+		}
+		if remaining == 0 {
+			changes.RemoveGroupingPolicy(s.authService.groupingRule(member, role.Name, role.Domain))
+		}
+	case models.AccessTargetGroup:
+		query := tx.Where("user_id = ? AND group_id = ?", request.RequesterID, request.TargetID)
+		if request.GrantID != nil {
+			query = query.Where("id = ?", *request.GrantID)
+		}
+		if err := query.Delete(&models.UserGroupMember{}).Error; err != nil {
+			return fmt.Errorf("从用户组移除用户失败: %w", err)
+		}
+		if err := tx.Model(&models.UserGroupMember{}).
+			Where("user_id = ? AND group_id = ?", request.RequesterID, request.TargetID).
+			Count(&remaining).Error; err != nil {
+			return fmt.Errorf("检查用户组成员失败: %w", err)
+		}
+		if remaining == 0 {
+			changes.RemoveGroupingPolicy([]string{member, fmt.Sprintf("group:%d", request.TargetID)})
+		}
+	default:
+		return fmt.Errorf("未知的申请类型: %s", request.TargetType)
+	}
+	return nil
+}
+
+// targetDomain 检查申请目标是否存在，并返回其所在的域，用户组不区分域
+func (s *AccessRequestService) targetDomain(tx *gorm.DB, targetType string, targetID uint) (string, error) {
+	switch targetType {
+	case models.AccessTargetRole:
+		var role models.Role
+		if err := tx.First(&role, targetID).Error; err != nil {
+			return "", fmt.Errorf("角色不存在: %w", err)
+		}
+		return role.Domain, nil
+	case models.AccessTargetGroup:
+		if err := tx.First(&models.UserGroup{}, targetID).Error; err != nil {
+			return "", fmt.Errorf("用户组不存在: %w", err)
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("未知的申请类型: %s", targetType)
+	}
+}
+
+// hasGrant 检查用户是否已拥有该角色或用户组
+func (s *AccessRequestService) hasGrant(tx *gorm.DB, userID uint, targetType string, targetID uint) (bool, error) {
+	var count int64
+	switch targetType {
+	case models.AccessTargetRole:
+		if err := tx.Model(&models.UserRole{}).
+			Where("user_id = ? AND role_id = ?", userID, targetID).
+			Count(&count).Error; err != nil {
+			return false, fmt.Errorf("检查用户角色失败: %w", err)
+		}
+	case models.AccessTargetGroup:
+		if err := tx.Model(&models.UserGroupMember{}).
+			Where("user_id = ? AND group_id = ?", userID, targetID).
+			Count(&count).Error; err != nil {
+			return false, fmt.Errorf("检查用户组成员失败: %w", err)
+		}
+	default:
+		return false, fmt.Errorf("未知的申请类型: %s", targetType)
+	}
+	return count > 0, nil
+}
+
+// lockRequest 在事务中锁定申请记录，避免并发审批
+func (s *AccessRequestService) lockRequest(tx *gorm.DB, requestID uint) (*models.AccessRequest, error) {
+	var request models.AccessRequest
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, requestID).Error; err != nil {
+		return nil, fmt.Errorf("权限申请不存在: %w", err)
+	}
+	return &request, nil
+}
+
+// audit 写入审计日志
+func (s *AccessRequestService) audit(tx *gorm.DB, requestID, actorID uint, action, detail string) error {
+	entry := models.AccessAuditLog{
+		RequestID: requestID,
+		ActorID:   actorID,
+		Action:    action,
+		Detail:    detail,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+func targetName(targetType string) string {
+	if targetType == models.AccessTargetGroup {
+		return "用户组"
+	}
+	return "角色"
+}
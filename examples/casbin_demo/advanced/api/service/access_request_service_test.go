@@ -0,0 +1,240 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+	"gorm.io/gorm"
+)
+
+// accessGroupModel 用户组不区分域时使用的模型
+const accessGroupModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.dom == p.dom && keyMatch2(r.obj, p.obj) && r.act == p.act
+`
+
+type accessFixture struct {
+	s        *AccessRequestService
+	db       *gorm.DB
+	role     *models.Role
+	group    *models.UserGroup
+	approver uint
+}
+
+func newAccessFixture(t *testing.T, modelText string, policies, groupings [][]string) *accessFixture {
+	t.Helper()
+	db := newTestDB(t, &models.User{}, &models.Role{}, &models.UserRole{}, &models.UserGroup{},
+		&models.UserGroupMember{}, &models.AccessRequest{}, &models.AccessAuditLog{})
+	e := newTestEnforcer(t, modelText, policies, groupings)
+
+	for _, u := range []models.User{
+		{ID: 1, Username: "alice", Email: "alice@example.com", Password: "x"},
+		{ID: 2, Username: "bob", Email: "bob@example.com", Password: "x"},
+		{ID: 9, Username: "security", Email: "security@example.com", Password: "x"},
+	} {
+		if err := db.Create(&u).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	role := &models.Role{Name: "editor", Domain: "tenant1"}
+	group := &models.UserGroup{Name: "ops"}
+	if err := db.Create(role).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(group).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &accessFixture{
+		s:        NewAccessRequestService(db, NewAuthService(db, e)),
+		db:       db,
+		role:     role,
+		group:    group,
+		approver: 9,
+	}
+}
+
+func newRoleAccessFixture(t *testing.T) *accessFixture {
+	return newAccessFixture(t, analyzerDomainModel,
+		[][]string{{"security", "tenant1", "/access-requests/role/*", "approve", "allow"}},
+		[][]string{{"user:9", "security", "tenant1"}})
+}
+
+func TestRequestAccessDomainFromTarget(t *testing.T) {
+	f := newRoleAccessFixture(t)
+
+	req, err := f.s.RequestAccess(1, models.AccessTargetRole, f.role.ID, "", "需要编辑文档", 0)
+	if err != nil {
+		t.Fatalf("RequestAccess() error = %v", err)
+	}
+	if req.Domain != "tenant1" {
+		t.Errorf("Domain = %q, want tenant1", req.Domain)
+	}
+
+	// 申请人指定的域与角色所在域不一致时拒绝，避免选择审批人所在的域
+	if _, err := f.s.RequestAccess(2, models.AccessTargetRole, f.role.ID, "tenant2", "", 0); err == nil {
+		t.Fatal("域与角色不一致时应返回错误")
+	}
+	if _, err := f.s.RequestAccess(1, models.AccessTargetRole, f.role.ID, "tenant1", "", 0); err == nil {
+		t.Fatal("已有待审批的相同申请时应返回错误")
+	}
+	if _, err := f.s.RequestAccess(1, models.AccessTargetRole, 999, "", "", 0); err == nil {
+		t.Fatal("角色不存在时应返回错误")
+	}
+}
+
+func TestApproveAndExpire(t *testing.T) {
+	f := newRoleAccessFixture(t)
+	e := f.s.authService.enforcer
+
+	req, err := f.s.RequestAccess(1, models.AccessTargetRole, f.role.ID, "", "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.s.Approve(req.ID, 1, ""); err == nil {
+		t.Fatal("不能审批自己的申请")
+	}
+	if _, err := f.s.Approve(req.ID, 2, ""); err == nil {
+		t.Fatal("没有审批权限的用户不能审批")
+	}
+
+	approved, err := f.s.Approve(req.ID, f.approver, "同意")
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if approved.Status != models.AccessStatusApproved || approved.ExpiresAt == nil || approved.GrantID == nil {
+		t.Fatalf("approved = %+v", approved)
+	}
+	if !e.HasGroupingPolicy("user:1", "editor", "tenant1") {
+		t.Fatal("审批通过后应写入 g 规则")
+	}
+
+	if n, err := f.s.ExpireGrants(time.Now()); err != nil || n != 0 {
+		t.Fatalf("ExpireGrants(now) = %d, %v, want 0", n, err)
+	}
+	if n, err := f.s.ExpireGrants(time.Now().Add(2 * time.Hour)); err != nil || n != 1 {
+		t.Fatalf("ExpireGrants(+2h) = %d, %v, want 1", n, err)
+	}
+	if e.HasGroupingPolicy("user:1", "editor", "tenant1") {
+		t.Error("到期后应移除 g 规则")
+	}
+
+	logs, err := f.s.GetAuditLog(req.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := make([]string, 0, len(logs))
+	for _, l := range logs {
+		actions = append(actions, l.Action)
+	}
+	if got := strings.Join(actions, ","); got != "request,approve,expire" {
+		t.Errorf("audit = %s, want request,approve,expire", got)
+	}
+}
+
+func TestExpireGrantsKeepsOtherGrant(t *testing.T) {
+	f := newRoleAccessFixture(t)
+	e := f.s.authService.enforcer
+
+	req, err := f.s.RequestAccess(1, models.AccessTargetRole, f.role.ID, "", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.s.Approve(req.ID, f.approver, ""); err != nil {
+		t.Fatal(err)
+	}
+	// 授权期间管理员又直接分配了同一角色
+	if err := f.s.authService.AssignRoleToUser(1, f.role.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := f.s.ExpireGrants(time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Fatalf("ExpireGrants() = %d, %v, want 1", n, err)
+	}
+	if !e.HasGroupingPolicy("user:1", "editor", "tenant1") {
+		t.Error("用户通过其他途径持有的角色不应被回收")
+	}
+	var count int64
+	f.db.Model(&models.UserRole{}).Where("user_id = ? AND role_id = ?", 1, f.role.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("用户角色记录 = %d, want 1", count)
+	}
+}
+
+func TestApproveRollbackKeepsEnforcer(t *testing.T) {
+	f := newRoleAccessFixture(t)
+	e := f.s.authService.enforcer
+
+	req, err := f.s.RequestAccess(1, models.AccessTargetRole, f.role.ID, "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errAudit := errors.New("审计库不可用")
+	f.db.Callback().Create().Before("gorm:create").Register("test:fail_audit", func(tx *gorm.DB) {
+		if tx.Statement.Table == "access_audit_logs" {
+			tx.AddError(errAudit)
+		}
+	})
+
+	if _, err := f.s.Approve(req.ID, f.approver, ""); !errors.Is(err, errAudit) {
+		t.Fatalf("Approve() error = %v, want %v", err, errAudit)
+	}
+	if e.HasGroupingPolicy("user:1", "editor", "tenant1") {
+		t.Error("事务回滚后不应写入 g 规则")
+	}
+	var count int64
+	f.db.Model(&models.UserRole{}).Count(&count)
+	if count != 0 {
+		t.Errorf("用户角色记录 = %d, want 0", count)
+	}
+	pending, err := f.s.ListRequests(models.AccessStatusPending)
+	if err != nil || len(pending) != 1 {
+		t.Errorf("pending = %v, %v, want 1", pending, err)
+	}
+}
+
+func TestRevokeGroupGrant(t *testing.T) {
+	f := newAccessFixture(t, accessGroupModel,
+		[][]string{{"security", "", "/access-requests/group/*", "approve"}},
+		[][]string{{"user:9", "security"}})
+	e := f.s.authService.enforcer
+
+	if _, err := f.s.RequestAccess(1, models.AccessTargetGroup, f.group.ID, "tenant1", "", 0); err == nil {
+		t.Fatal("用户组不区分域，指定域时应返回错误")
+	}
+	req, err := f.s.RequestAccess(1, models.AccessTargetGroup, f.group.ID, "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.s.Approve(req.ID, f.approver, ""); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if !e.HasGroupingPolicy("user:1", "group:1") {
+		t.Fatal("审批通过后应加入用户组")
+	}
+
+	if err := f.s.Revoke(req.ID, f.approver, "离职"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if e.HasGroupingPolicy("user:1", "group:1") {
+		t.Error("回收后应移出用户组")
+	}
+	if err := f.s.Revoke(req.ID, f.approver, ""); err == nil {
+		t.Error("已回收的申请不能再次回收")
+	}
+}
@@ -2,13 +2,27 @@ package service
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
 	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
 	"gorm.io/gorm"
 )
 
+// 策略效果，需配合 deny-override 或 priority 模型使用
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// 优先级模型中的默认优先级，数字越小优先级越高
+const (
+	PriorityUser  = 1  // 用户级例外规则
+	PriorityGroup = 10 // 用户组/角色规则
+)
+
 // AuthService 认证服务
 type AuthService struct {
 	db       *gorm.DB
@@ -77,7 +91,7 @@ func (s *AuthService) AssignRoleToUser(userID uint, roleID uint) error {
 	}
 
 	// 同步到 Casbin 策略
-	_, err := s.enforcer.AddRoleForUser(fmt.Sprintf("user:%d", userID), role.Name)
+	_, err := s.enforcer.AddGroupingPolicy(s.groupingRule(fmt.Sprintf("user:%d", userID), role.Name, role.Domain))
 	if err != nil {
 		return fmt.Errorf("同步 Casbin 策略失败: %w", err)
 	}
@@ -94,14 +108,131 @@ func (s *AuthService) AddGroupPolicy(groupID uint, domain, obj, act string) erro
 	}
 
 	// 添加策略
-	_, err := s.enforcer.AddPolicy(fmt.Sprintf("group:%d", groupID), domain, obj, act, "allow")
+	return s.addPolicyWithEffect(fmt.Sprintf("group:%d", groupID), domain, obj, act, EffectAllow, PriorityGroup)
+}
+
+// AddGroupDenyPolicy 添加用户组拒绝策略
+func (s *AuthService) AddGroupDenyPolicy(groupID uint, domain, obj, act string) error {
+	var group models.UserGroup
+	if err := s.db.First(&group, groupID).Error; err != nil {
+		return fmt.Errorf("用户组不存在: %w", err)
+	}
+
+	return s.addPolicyWithEffect(fmt.Sprintf("group:%d", groupID), domain, obj, act, EffectDeny, PriorityGroup)
+}
+
+// DenyUserAccess 禁止用户访问指定资源，即使其所在用户组或角色拥有访问权限
+func (s *AuthService) DenyUserAccess(userID uint, domain, obj, act string) error {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return fmt.Errorf("用户不存在: %w", err)
+	}
+
+	return s.addPolicyWithEffect(fmt.Sprintf("user:%d", userID), domain, obj, act, EffectDeny, PriorityUser)
+}
+
+// RemoveUserDeny 解除对用户的访问禁止
+func (s *AuthService) RemoveUserDeny(userID uint, domain, obj, act string) error {
+	rule, err := s.buildPolicy(fmt.Sprintf("user:%d", userID), domain, obj, act, EffectDeny, PriorityUser)
 	if err != nil {
-		return fmt.Errorf("添加策略失败: %w", err)
+		return err
 	}
 
+	if _, err := s.enforcer.RemovePolicy(rule); err != nil {
+		return fmt.Errorf("删除策略失败: %w", err)
+	}
 	return nil
 }
 
+// GetUserDenyPolicies 获取直接作用于用户的拒绝策略
+func (s *AuthService) GetUserDenyPolicies(userID uint) [][]string {
+	subIndex, eftIndex := s.policyFieldIndex("sub"), s.policyFieldIndex("eft")
+	if subIndex < 0 || eftIndex < 0 {
+		return [][]string{}
+	}
+
+	policies := s.enforcer.GetFilteredPolicy(subIndex, fmt.Sprintf("user:%d", userID))
+	denies := make([][]string, 0)
+	for _, policy := range policies {
+		if eftIndex < len(policy) && policy[eftIndex] == EffectDeny {
+			denies = append(denies, policy)
+		}
+	}
+	return denies
+}
+
+// addPolicyWithEffect 按当前模型的字段布局添加带效果的策略
+func (s *AuthService) addPolicyWithEffect(sub, domain, obj, act, effect string, priority int) error {
+	rule, err := s.buildPolicy(sub, domain, obj, act, effect, priority)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.enforcer.AddPolicy(rule); err != nil {
+		return fmt.Errorf("添加策略失败: %w", err)
+	}
+	return nil
+}
+
+// buildPolicy 根据 policy_definition 组装策略，兼容 (sub, dom, obj, act, eft) 和带 priority 的优先级模型。
+// 其他字段（如 projectId）填 "*"，规则作用于该字段的所有取值；只作用于单个项目的规则用 AddProjectPolicy 添加
+func (s *AuthService) buildPolicy(sub, domain, obj, act, effect string, priority int) ([]string, error) {
+	ast, ok := s.enforcer.GetModel()["p"]["p"]
+	if !ok {
+		return nil, fmt.Errorf("模型未定义 policy_definition")
+	}
+
+	rule := make([]string, len(ast.Tokens))
+	hasEffect := false
+	for i, token := range ast.Tokens {
+		switch strings.TrimPrefix(token, "p_") {
+		case "sub":
+			rule[i] = sub
+		case "dom":
+			rule[i] = domain
+		case "obj":
+			rule[i] = obj
+		case "act":
+			rule[i] = act
+		case "eft":
+			rule[i] = effect
+			hasEffect = true
+		case "priority":
+			rule[i] = strconv.Itoa(priority)
+		default:
+			rule[i] = "*"
+		}
+	}
+
+	if !hasEffect && effect != EffectAllow {
+		return nil, fmt.Errorf("当前模型不支持拒绝策略")
+	}
+	return rule, nil
+}
+
+// policyFieldIndex 返回策略字段在 policy_definition 中的位置，不存在时返回 -1
+func (s *AuthService) policyFieldIndex(name string) int {
+	ast, ok := s.enforcer.GetModel()["p"]["p"]
+	if !ok {
+		return -1
+	}
+	for i, token := range ast.Tokens {
+		if token == "p_"+name {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupingRule 根据 role_definition 组装 g 规则，带域的模型（g = _, _, _）会追加域
+func (s *AuthService) groupingRule(member, role, domain string) []string {
+	rule := []string{member, role}
+	if ast, ok := s.enforcer.GetModel()["g"]["g"]; ok && len(ast.Tokens) > 2 {
+		rule = append(rule, domain)
+	}
+	return rule
+}
+
 // CheckPermission 检查权限
 func (s *AuthService) CheckPermission(userID uint, domain, obj, act string) (bool, error) {
 	return s.enforcer.Enforce(fmt.Sprintf("user:%d", userID), domain, obj, act)
@@ -176,7 +307,7 @@ func (s *AuthService) RemoveRoleFromUser(userID uint, roleID uint) error {
 	}
 
 	// 从 Casbin 策略中移除
-	_, err = s.enforcer.DeleteRoleForUser(fmt.Sprintf("user:%d", userID), role.Name)
+	_, err = s.enforcer.RemoveGroupingPolicy(s.groupingRule(fmt.Sprintf("user:%d", userID), role.Name, role.Domain))
 	if err != nil {
 		return fmt.Errorf("同步 Casbin 策略失败: %w", err)
 	}
@@ -230,7 +361,7 @@ func (s *AuthService) UpdateGroupPolicy(groupID uint, oldPolicy, newPolicy []str
 	return nil
 }
 
-// RemoveGroupPolicy 删除用户组权限策略
+// RemoveGroupPolicy 删除用户组在域内对资源和操作的权限策略，允许和拒绝规则都会删除
 func (s *AuthService) RemoveGroupPolicy(groupID uint, domain, obj, act string) error {
 	// 检查用户组是否存在
 	var group models.UserGroup
@@ -238,15 +369,51 @@ func (s *AuthService) RemoveGroupPolicy(groupID uint, domain, obj, act string) e
 		return fmt.Errorf("用户组不存在: %w", err)
 	}
 
-	// 删除策略
-	_, err := s.enforcer.RemovePolicy(fmt.Sprintf("group:%d", groupID), domain, obj, act)
+	// 按 sub/dom/obj/act 过滤删除，不限定效果、优先级等其他字段
+	fieldIndex, values, err := s.policyFilter(map[string]string{
+		"sub": fmt.Sprintf("group:%d", groupID),
+		"dom": domain,
+		"obj": obj,
+		"act": act,
+	})
 	if err != nil {
+		return err
+	}
+	if _, err := s.enforcer.RemoveFilteredPolicy(fieldIndex, values...); err != nil {
 		return fmt.Errorf("删除策略失败: %w", err)
 	}
 
 	return nil
 }
 
+// policyFilter 把字段名到取值的映射转换为 RemoveFilteredPolicy 所需的起始位置和取值，未指定的字段留空表示不限定
+func (s *AuthService) policyFilter(fields map[string]string) (int, []string, error) {
+	first, last := -1, -1
+	indexes := make(map[string]int, len(fields))
+	for name := range fields {
+		i := s.policyFieldIndex(name)
+		if i < 0 {
+			return 0, nil, fmt.Errorf("模型未定义策略字段 %s", name)
+		}
+		indexes[name] = i
+		if first < 0 || i < first {
+			first = i
+		}
+		if i > last {
+			last = i
+		}
+	}
+	if first < 0 {
+		return 0, nil, fmt.Errorf("过滤条件不能为空")
+	}
+
+	values := make([]string, last-first+1)
+	for name, i := range indexes {
+		values[i-first] = fields[name]
+	}
+	return first, values, nil
+}
+
 // GetAllPolicies 获取所有权限策略
 func (s *AuthService) GetAllPolicies() [][]string {
 	return s.enforcer.GetPolicy()
@@ -264,13 +431,12 @@ func (s *AuthService) GetImplicitPermissionsForUser(userID uint) ([][]string, er
 
 // CheckBasicPermission 基本权限检查（不包含继承权限）
 func (s *AuthService) CheckBasicPermission(userID uint, domain, obj, act string) (bool, error) {
-	// 直接检查用户是否有指定权限
-	return s.enforcer.HasPermissionForUser(
-		fmt.Sprintf("user:%d", userID),
-		domain,
-		obj,
-		act,
-	)
+	// 直接检查用户是否有指定的允许策略
+	rule, err := s.buildPolicy(fmt.Sprintf("user:%d", userID), domain, obj, act, EffectAllow, PriorityUser)
+	if err != nil {
+		return false, err
+	}
+	return s.enforcer.HasPolicy(rule), nil
 }
 
 // CheckInheritedPermission 继承权限检查（包含角色和用户组继承的权限）
@@ -315,13 +481,23 @@ func (s *AuthService) GetPermissionsByGroup(groupID uint) [][]string {
 
 // CheckUserInGroup 检查用户是否在指定用户组中（包括继承关系）
 func (s *AuthService) CheckUserInGroup(userID uint, groupID uint) (bool, error) {
-	return s.enforcer.HasGroupingPolicy(
-		fmt.Sprintf("user:%d", userID),
-		fmt.Sprintf("group:%d", groupID),
-	)
+	groups, err := s.enforcer.GetImplicitRolesForUser(fmt.Sprintf("user:%d", userID))
+	if err != nil {
+		return false, err
+	}
+	group := fmt.Sprintf("group:%d", groupID)
+	for _, g := range groups {
+		if g == group {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // GetAllUserPermissions 获取用户的所有权限（包括直接权限、角色权限和用户组权限）
+//
+// Deprecated: 直接权限只在用户拥有 (*, *, *) 策略时返回 ["*", "*", "*"]，角色和用户组的策略字段被拼接成一维数组，
+// 且不包含继承的角色和用户组，前端请使用 GetEffectivePermissions。
 func (s *AuthService) GetAllUserPermissions(userID uint) (map[string][]string, error) {
 	permissions := make(map[string][]string)
 
@@ -364,19 +540,35 @@ func (s *AuthService) GetAllUserPermissions(userID uint) (map[string][]string, e
 }
 
 // CheckPolicyConflicts 检查权限策略冲突
+// 返回主体（含继承的角色和用户组）在该请求上同时命中的 allow 和 deny 规则
 func (s *AuthService) CheckPolicyConflicts(sub, dom, obj, act string) (bool, [][]string, error) {
-	// 获取所有匹配的策略
-	policies := s.enforcer.GetFilteredPolicy(0, sub)
-	conflicts := make([][]string, 0)
-
-	for _, policy := range policies {
-		// 检查是否有冲突的策略（相同对象不同操作）
-		if len(policy) >= 4 && policy[1] == dom && policy[2] == obj && policy[3] != act {
-			conflicts = append(conflicts, policy)
+	analyzer := NewPolicyAnalyzer(s.enforcer, "user:")
+	parents := analyzer.loadInheritance()
+	subjects := collectAncestors(sub, dom, parents)
+	subjects[sub] = true
+
+	matched := make([][]string, 0)
+	effects := make(map[string]bool)
+	for _, rule := range analyzer.loadRules() {
+		if !subjects[rule.sub] || !analyzer.domainCovers(rule.dom, dom) {
+			continue
+		}
+		if !util.KeyMatch2(obj, rule.obj) || !actionsOverlap(rule.actions, map[string]bool{act: true}) {
+			continue
 		}
+		matched = append(matched, rule.raw)
+		effects[rule.eft] = true
+	}
+
+	if effects["allow"] && effects["deny"] {
+		return true, matched, nil
 	}
+	return false, [][]string{}, nil
+}
 
-	return len(conflicts) > 0, conflicts, nil
+// AnalyzePolicies 分析整个策略集中的覆盖、冗余、冲突、孤立主体和角色环
+func (s *AuthService) AnalyzePolicies() []PolicyFinding {
+	return NewPolicyAnalyzer(s.enforcer, "user:").Analyze()
 }
 
 // CheckDataPermission 检查数据权限
@@ -402,9 +594,22 @@ func (s *AuthService) AddProjectPolicy(sub string, domain string, obj string, ac
 	return err
 }
 
-// GetUserDepartmentProjects 获取用户部门可访问的所有项目
+// GetUserDepartmentProjects 获取用户部门可访问的所有项目，即用户及其角色的策略中的 projectId（AddProjectPolicy 的第 5 个字段）
 func (s *AuthService) GetUserDepartmentProjects(userID uint) ([]string, error) {
-	return s.enforcer.GetImplicitPermissionsForUser(fmt.Sprintf("user:%d", userID))
+	rules, err := s.enforcer.GetImplicitPermissionsForUser(fmt.Sprintf("user:%d", userID))
+	if err != nil {
+		return nil, err
+	}
+	var projects []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if len(rule) < 5 || seen[rule[4]] {
+			continue
+		}
+		seen[rule[4]] = true
+		projects = append(projects, rule[4])
+	}
+	return projects, nil
 }
 
 // ShareDocumentWithUser 分享文档给指定用户
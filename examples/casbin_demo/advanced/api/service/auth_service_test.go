@@ -0,0 +1,124 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+)
+
+const projectModel = `
+[request_definition]
+r = sub, dom, obj, act, projectId
+
+[policy_definition]
+p = sub, dom, obj, act, projectId, eft
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[matchers]
+m = g(r.sub, p.sub) && r.dom == p.dom && r.obj == p.obj && r.act == p.act && (r.projectId == p.projectId || p.projectId == "*")
+`
+
+const noEffectModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`
+
+func TestBuildPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		effect  string
+		want    string
+		wantErr string
+	}{
+		{name: "domain model", model: analyzerDomainModel, effect: EffectDeny, want: "user:1,d1,/docs/1,GET,deny"},
+		{name: "priority model", model: analyzerPriorityModel, effect: EffectDeny, want: "1,user:1,d1,/docs/1,GET,deny"},
+		{name: "extra field is filled with wildcard", model: projectModel, effect: EffectDeny, want: "user:1,d1,/docs/1,GET,*,deny"},
+		{name: "no effect field allows allow", model: noEffectModel, effect: EffectAllow, want: "user:1,/docs/1,GET"},
+		{name: "no effect field rejects deny", model: noEffectModel, effect: EffectDeny, wantErr: "不支持拒绝策略"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(nil, newTestEnforcer(t, tt.model, nil, nil))
+			rule, err := s.buildPolicy("user:1", "d1", "/docs/1", "GET", tt.effect, PriorityUser)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("rule = %v, err = %v, want error containing %q", rule, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(rule, ","); got != tt.want {
+				t.Fatalf("rule = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupPolicyOnProjectModel(t *testing.T) {
+	db := newTestDB(t, &models.UserGroup{})
+	group := models.UserGroup{Name: "editors"}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatal(err)
+	}
+	e := newTestEnforcer(t, projectModel, nil, [][]string{{"user:1", "group:1"}})
+	s := NewAuthService(db, e)
+
+	if err := s.AddGroupPolicy(group.ID, "d1", "/docs/1", "GET"); err != nil {
+		t.Fatalf("AddGroupPolicy() error = %v", err)
+	}
+	ok, err := s.CheckDataPermission(1, "d1", "/docs/1", "GET", "p1")
+	if err != nil || !ok {
+		t.Fatalf("CheckDataPermission() = %v, %v, want true", ok, err)
+	}
+}
+
+func TestRemoveGroupPolicyRemovesAllowAndDeny(t *testing.T) {
+	db := newTestDB(t, &models.UserGroup{})
+	group := models.UserGroup{Name: "editors"}
+	if err := db.Create(&group).Error; err != nil {
+		t.Fatal(err)
+	}
+	s := NewAuthService(db, newTestEnforcer(t, analyzerPriorityModel, nil, nil))
+
+	if err := s.AddGroupPolicy(group.ID, "d1", "/docs/1", "GET"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddGroupDenyPolicy(group.ID, "d1", "/docs/2", "GET"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddGroupPolicy(group.ID, "d1", "/docs/3", "GET"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range []string{"/docs/1", "/docs/2"} {
+		if err := s.RemoveGroupPolicy(group.ID, "d1", obj, "GET"); err != nil {
+			t.Fatalf("RemoveGroupPolicy(%s) error = %v", obj, err)
+		}
+	}
+	want := []string{"10,group:1,d1,/docs/3,GET,allow"}
+	if got := sortedRules(s.GetAllPolicies()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("policies = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EnforceRequest 一次权限检查，主体可以是用户（user:1）也可以是服务（svc:order）
+type EnforceRequest struct {
+	Subject string
+	Domain  string
+	Object  string
+	Action  string
+}
+
+// Enforce 按 request_definition 检查任意主体的权限，模型中没有的字段被忽略
+func (s *AuthService) Enforce(req EnforceRequest) (bool, error) {
+	allowed, err := s.enforcer.Enforce(s.requestValues(req.Subject, req.Domain, req.Object, req.Action)...)
+	if err != nil {
+		return false, fmt.Errorf("权限检查失败: %w", err)
+	}
+	return allowed, nil
+}
+
+// BatchEnforce 批量检查权限，结果顺序与请求一致
+func (s *AuthService) BatchEnforce(reqs []EnforceRequest) ([]bool, error) {
+	if len(reqs) == 0 {
+		return []bool{}, nil
+	}
+
+	requests := make([][]interface{}, len(reqs))
+	for i, req := range reqs {
+		requests[i] = s.requestValues(req.Subject, req.Domain, req.Object, req.Action)
+	}
+	allowed, err := s.enforcer.BatchEnforce(requests)
+	if err != nil {
+		return nil, fmt.Errorf("批量权限检查失败: %w", err)
+	}
+	return allowed, nil
+}
+
+// GetSubjectRoles 获取主体在域内直接和间接继承的角色、用户组，domain 为空时返回所有域
+func (s *AuthService) GetSubjectRoles(sub, domain string) []string {
+	roles := make([]string, 0)
+	for role, domains := range s.collectSubjects(sub) {
+		if role == sub {
+			continue
+		}
+		if domain == "" || domains[""] || domains[domain] {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// GetSubjectPolicies 获取直接作用于主体的策略，sub 为空时返回全部策略
+func (s *AuthService) GetSubjectPolicies(sub string) ([][]string, error) {
+	if sub == "" {
+		return s.GetAllPolicies(), nil
+	}
+	subIndex := s.policyFieldIndex("sub")
+	if subIndex < 0 {
+		return nil, fmt.Errorf("模型未定义 p.sub")
+	}
+	return s.enforcer.GetFilteredPolicy(subIndex, sub), nil
+}
+
+// requestValues 根据 request_definition 组装 Enforce 参数
+func (s *AuthService) requestValues(sub, dom, obj, act string) []interface{} {
+	var tokens []string
+	if ast, ok := s.enforcer.GetModel()["r"]["r"]; ok {
+		tokens = ast.Tokens
+	}
+
+	rvals := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		switch strings.TrimPrefix(token, "r_") {
+		case "sub":
+			rvals = append(rvals, sub)
+		case "dom":
+			rvals = append(rvals, dom)
+		case "obj":
+			rvals = append(rvals, obj)
+		case "act":
+			rvals = append(rvals, act)
+		default:
+			rvals = append(rvals, "")
+		}
+	}
+	return rvals
+}
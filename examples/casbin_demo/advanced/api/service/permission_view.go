@@ -0,0 +1,233 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PermissionEntry 用户的一条有效权限，供前端渲染菜单和按钮
+type PermissionEntry struct {
+	Domain  string   `json:"domain,omitempty"`
+	Object  string   `json:"object"`            // 对象模式，如 /api/v1/documents/:id
+	Actions []string `json:"actions"`           // ["*"] 表示所有操作
+	Effect  string   `json:"effect"`            // allow 或 deny
+	Sources []string `json:"sources,omitempty"` // 授予该权限的主体（用户本身、角色或用户组）
+}
+
+// Capability 前端的一个界面能力（菜单、按钮），映射到一次权限检查
+type Capability struct {
+	Key    string `json:"key"`
+	Domain string `json:"domain,omitempty"`
+	Object string `json:"object"`
+	Action string `json:"action"`
+}
+
+// GetEffectivePermissions 获取用户的有效权限，包括通过角色和用户组继承的权限
+// 同一 (域, 对象, 效果) 的规则合并为一条，操作取并集
+func (s *AuthService) GetEffectivePermissions(userID uint) ([]PermissionEntry, error) {
+	return s.GetSubjectPermissions(fmt.Sprintf("user:%d", userID))
+}
+
+// GetSubjectPermissions 获取任意主体（用户、服务）的有效权限，规则同 GetEffectivePermissions
+func (s *AuthService) GetSubjectPermissions(sub string) ([]PermissionEntry, error) {
+	index := map[string]int{}
+	ast, ok := s.enforcer.GetModel()["p"]["p"]
+	if !ok {
+		return nil, fmt.Errorf("模型未定义 policy_definition")
+	}
+	for i, token := range ast.Tokens {
+		index[strings.TrimPrefix(token, "p_")] = i
+	}
+	subIndex, ok := index["sub"]
+	if !ok {
+		return nil, fmt.Errorf("模型未定义 p.sub")
+	}
+	field := func(rule []string, name string) string {
+		if i, ok := index[name]; ok && i < len(rule) {
+			return rule[i]
+		}
+		return ""
+	}
+
+	// 主体 -> 可生效的域，"" 表示不限域
+	subjects := s.collectSubjects(sub)
+
+	type entryKey struct{ dom, obj, eft string }
+	merged := make(map[entryKey]*PermissionEntry)
+	actions := make(map[entryKey]map[string]bool)
+	sources := make(map[entryKey]map[string]bool)
+
+	add := func(rule []string, source string) {
+		key := entryKey{dom: field(rule, "dom"), obj: field(rule, "obj"), eft: field(rule, "eft")}
+		if key.eft == "" {
+			key.eft = EffectAllow
+		}
+		if _, ok := merged[key]; !ok {
+			merged[key] = &PermissionEntry{Domain: key.dom, Object: key.obj, Effect: key.eft}
+			actions[key] = make(map[string]bool)
+			sources[key] = make(map[string]bool)
+		}
+
+		parsed := parseActions(field(rule, "act"))
+		if parsed == nil {
+			actions[key]["*"] = true
+		}
+		for act := range parsed {
+			actions[key][act] = true
+		}
+		sources[key][source] = true
+	}
+
+	for sub, domains := range subjects {
+		for _, rule := range s.enforcer.GetFilteredPolicy(subIndex, sub) {
+			dom := field(rule, "dom")
+			if !domains[""] && !domains[dom] && dom != "*" {
+				continue
+			}
+			add(rule, sub)
+		}
+	}
+
+	// 匹配器中的 g(r.sub, "admin") 子句让持有该角色的主体匹配任意主体的规则
+	for role, allowOnly := range s.bypassRoles() {
+		if _, ok := subjects[role]; !ok || role == sub {
+			continue
+		}
+		for _, rule := range s.enforcer.GetPolicy() {
+			eft := field(rule, "eft")
+			if allowOnly && eft != "" && eft != EffectAllow {
+				continue
+			}
+			add(rule, role)
+		}
+	}
+
+	entries := make([]PermissionEntry, 0, len(merged))
+	for key, entry := range merged {
+		if actions[key]["*"] {
+			entry.Actions = []string{"*"}
+		} else {
+			entry.Actions = sortedKeys(actions[key])
+		}
+		entry.Sources = sortedKeys(sources[key])
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Domain != entries[j].Domain {
+			return entries[i].Domain < entries[j].Domain
+		}
+		if entries[i].Object != entries[j].Object {
+			return entries[i].Object < entries[j].Object
+		}
+		return entries[i].Effect < entries[j].Effect
+	})
+	return entries, nil
+}
+
+// CheckCapabilities 批量检查用户的界面能力，返回 Key -> 是否允许
+// 未指定域的能力使用 defaultDomain，所有检查通过一次 BatchEnforce 完成
+func (s *AuthService) CheckCapabilities(userID uint, defaultDomain string, caps []Capability) (map[string]bool, error) {
+	return s.CheckSubjectCapabilities(fmt.Sprintf("user:%d", userID), defaultDomain, caps)
+}
+
+// CheckSubjectCapabilities 批量检查任意主体的界面能力，规则同 CheckCapabilities
+func (s *AuthService) CheckSubjectCapabilities(sub, defaultDomain string, caps []Capability) (map[string]bool, error) {
+	result := make(map[string]bool, len(caps))
+	if len(caps) == 0 {
+		return result, nil
+	}
+
+	requests := make([][]interface{}, 0, len(caps))
+	for _, c := range caps {
+		if c.Key == "" {
+			return nil, fmt.Errorf("能力缺少 key: %s %s", c.Action, c.Object)
+		}
+		dom := c.Domain
+		if dom == "" {
+			dom = defaultDomain
+		}
+		requests = append(requests, s.requestValues(sub, dom, c.Object, c.Action))
+	}
+
+	allowed, err := s.enforcer.BatchEnforce(requests)
+	if err != nil {
+		return nil, fmt.Errorf("批量权限检查失败: %w", err)
+	}
+	for i, c := range caps {
+		result[c.Key] = allowed[i]
+	}
+	return result, nil
+}
+
+// bypassPattern 匹配形如 g(r.sub, "admin") 的角色子句，可选地紧跟 && p.eft == "allow"
+var bypassPattern = regexp.MustCompile(`\bg\(\s*r[._]sub\s*,\s*["']([^"']+)["']\s*\)(\s*&&\s*p[._]eft\s*==\s*["']allow["'])?`)
+
+// bypassRoles 解析匹配器中不看 p.sub 的角色子句，返回 角色 -> 是否只对 allow 规则生效
+func (s *AuthService) bypassRoles() map[string]bool {
+	roles := make(map[string]bool)
+	ast, ok := s.enforcer.GetModel()["m"]["m"]
+	if !ok {
+		return roles
+	}
+	for _, m := range bypassPattern.FindAllStringSubmatch(ast.Value, -1) {
+		roles[m[1]] = m[2] != ""
+	}
+	return roles
+}
+
+// collectSubjects 返回用户本身及其通过 g/g2... 继承的所有主体，以及各主体生效的域
+// 带域的继承关系（g = _, _, _）只在对应域内生效
+func (s *AuthService) collectSubjects(user string) map[string]map[string]bool {
+	edges := make(map[string][]inheritEdge)
+	for ptype := range s.enforcer.GetModel()["g"] {
+		for _, g := range s.enforcer.GetNamedGroupingPolicy(ptype) {
+			if len(g) < 2 {
+				continue
+			}
+			edge := inheritEdge{parent: g[1]}
+			if len(g) > 2 {
+				edge.dom = g[2]
+			}
+			edges[g[0]] = append(edges[g[0]], edge)
+		}
+	}
+
+	type node struct {
+		sub string
+		dom string
+	}
+	subjects := map[string]map[string]bool{user: {"": true}}
+	queue := []node{{sub: user}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges[n.sub] {
+			dom := n.dom
+			if dom == "" {
+				dom = edge.dom
+			} else if edge.dom != "" && edge.dom != dom {
+				continue
+			}
+			if subjects[edge.parent] == nil {
+				subjects[edge.parent] = make(map[string]bool)
+			}
+			if subjects[edge.parent][dom] {
+				continue
+			}
+			subjects[edge.parent][dom] = true
+			queue = append(queue, node{sub: edge.parent, dom: dom})
+		}
+	}
+	return subjects
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetSubjectPermissionsDomainInheritance(t *testing.T) {
+	e := newTestEnforcer(t, analyzerDomainModel,
+		[][]string{
+			{"editor", "d1", "/docs/*", "(GET)|(PUT)", "allow"},
+			{"editor", "d2", "/docs/*", "GET", "allow"},
+			{"viewer", "d1", "/docs/*", "GET", "allow"},
+			{"user:1", "d1", "/docs/secret", "GET", "deny"},
+		},
+		[][]string{{"user:1", "editor", "d1"}, {"user:1", "viewer", "d1"}},
+	)
+	entries, err := NewAuthService(nil, e).GetEffectivePermissions(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(entries))
+	for _, p := range entries {
+		got = append(got, p.Domain+" "+p.Object+" "+strings.Join(p.Actions, "|")+" "+p.Effect+" "+strings.Join(p.Sources, "+"))
+	}
+	// d2 的 editor 规则不生效；同一 (域, 对象, 效果) 的规则合并操作和来源
+	want := []string{
+		"d1 /docs/* GET|PUT allow editor+viewer",
+		"d1 /docs/secret GET deny user:1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBypassRoles(t *testing.T) {
+	tests := []struct {
+		matcher string
+		want    string
+	}{
+		{matcher: `g(r.sub, p.sub) && r.obj == p.obj`, want: ""},
+		{matcher: `(g(r.sub, "admin") && p.eft == "allow") || g(r.sub, p.sub)`, want: "admin=true"},
+		{matcher: `g(r.sub, 'root') || g(r.sub, p.sub)`, want: "root=false"},
+	}
+	for _, tt := range tests {
+		text := strings.Replace(analyzerDomainModel, "m = g(r.sub, p.sub, r.dom) && r.dom == p.dom && keyMatch2(r.obj, p.obj) && regexMatch(r.act, p.act)", "m = "+tt.matcher, 1)
+		roles := NewAuthService(nil, newTestEnforcer(t, text, nil, nil)).bypassRoles()
+		got := make([]string, 0)
+		for role, allowOnly := range roles {
+			if allowOnly {
+				got = append(got, role+"=true")
+			} else {
+				got = append(got, role+"=false")
+			}
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("%s: bypassRoles = %v, want %s", tt.matcher, got, tt.want)
+		}
+	}
+}
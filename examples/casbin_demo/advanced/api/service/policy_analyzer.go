@@ -0,0 +1,533 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
+)
+
+// FindingType 策略分析问题类型
+type FindingType string
+
+const (
+	// FindingShadowed 规则被同一主体或其继承角色的同等/更宽规则覆盖
+	FindingShadowed FindingType = "shadowed"
+	// FindingRedundant 规则被通配符（keyMatch2）规则覆盖，属于冗余规则
+	FindingRedundant FindingType = "redundant"
+	// FindingContradiction 同一请求同时命中 allow 和 deny 规则
+	FindingContradiction FindingType = "contradiction"
+	// FindingOrphanSubject 策略主体没有任何用户关联
+	FindingOrphanSubject FindingType = "orphan_subject"
+	// FindingRoleCycle 角色/用户组继承关系存在环
+	FindingRoleCycle FindingType = "role_cycle"
+)
+
+// PolicyFinding 策略分析结果
+type PolicyFinding struct {
+	Type    FindingType `json:"type"`
+	Policy  []string    `json:"policy,omitempty"`  // 有问题的规则
+	Related [][]string  `json:"related,omitempty"` // 导致问题的相关规则
+	Path    []string    `json:"path,omitempty"`    // 角色环路径
+	Message string      `json:"message"`
+}
+
+// policyRule 按模型字段解析后的策略规则
+type policyRule struct {
+	raw     []string
+	sub     string
+	dom     string
+	obj     string
+	act     string
+	eft     string
+	extra   string          // 其余字段（如 projectId、owner），必须相同才可比较
+	actions map[string]bool // nil 表示匹配所有操作
+
+	// priority 模型中的优先级，数字越小越先匹配；它决定规则的先后而不是规则本身，不参与 extra 比较
+	priority    int
+	hasPriority bool
+}
+
+// precedes 判断 r 是否先于 other 参与匹配；没有优先级字段时规则不分先后
+func (r policyRule) precedes(other policyRule) bool {
+	if !r.hasPriority || !other.hasPriority {
+		return true
+	}
+	return r.priority <= other.priority
+}
+
+// domainMode 匹配器比较请求域和规则域的方式
+type domainMode int
+
+const (
+	domainEqual   domainMode = iota // r.dom == p.dom，只有同一个域的规则会相互影响
+	domainPattern                   // 规则域按 keyMatch 匹配（如 "*"、"tenant_*"），可以覆盖多个域
+	domainIgnored                   // 匹配器不检查域，所有域的规则都会相互影响
+)
+
+// PolicyAnalyzer 策略冲突与冗余分析器
+type PolicyAnalyzer struct {
+	enforcer   *casbin.Enforcer
+	userPrefix string
+	domains    domainMode
+}
+
+// NewPolicyAnalyzer 创建策略分析器，主体以 userPrefix 开头的视为用户
+func NewPolicyAnalyzer(enforcer *casbin.Enforcer, userPrefix string) *PolicyAnalyzer {
+	a := &PolicyAnalyzer{
+		enforcer:   enforcer,
+		userPrefix: userPrefix,
+	}
+	a.domains = a.loadDomainMode()
+	return a
+}
+
+// Analyze 分析整个策略集，返回所有发现的问题
+func (a *PolicyAnalyzer) Analyze() []PolicyFinding {
+	rules := a.loadRules()
+	parents := a.loadInheritance()
+
+	findings := make([]PolicyFinding, 0)
+	findings = append(findings, a.findShadowed(rules, parents)...)
+	findings = append(findings, a.findContradictions(rules, parents)...)
+	findings = append(findings, a.findOrphanSubjects(rules)...)
+	findings = append(findings, a.findRoleCycles()...)
+	return findings
+}
+
+// loadRules 根据 policy_definition 的字段名解析所有 p 规则
+func (a *PolicyAnalyzer) loadRules() []policyRule {
+	index := map[string]int{}
+	if ast, ok := a.enforcer.GetModel()["p"]["p"]; ok {
+		for i, token := range ast.Tokens {
+			index[strings.TrimPrefix(token, "p_")] = i
+		}
+	}
+	field := func(rule []string, name string) string {
+		if i, ok := index[name]; ok && i < len(rule) {
+			return rule[i]
+		}
+		return ""
+	}
+
+	known := map[string]bool{"sub": true, "dom": true, "obj": true, "act": true, "eft": true, "priority": true}
+	extraFields := func(rule []string) string {
+		parts := make([]string, 0)
+		for name, i := range index {
+			if !known[name] && i < len(rule) {
+				parts = append(parts, name+"="+rule[i])
+			}
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	}
+
+	policies := a.enforcer.GetPolicy()
+	rules := make([]policyRule, 0, len(policies))
+	for _, p := range policies {
+		r := policyRule{
+			raw:   p,
+			sub:   field(p, "sub"),
+			dom:   field(p, "dom"),
+			obj:   field(p, "obj"),
+			act:   field(p, "act"),
+			eft:   field(p, "eft"),
+			extra: extraFields(p),
+		}
+		if r.eft == "" {
+			r.eft = "allow"
+		}
+		if v := field(p, "priority"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				r.priority, r.hasPriority = n, true
+			}
+		}
+		r.actions = parseActions(r.act)
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// loadDomainMode 根据匹配器判断规则域的匹配方式
+func (a *PolicyAnalyzer) loadDomainMode() domainMode {
+	ast, ok := a.enforcer.GetModel()["m"]["m"]
+	if !ok {
+		return domainEqual
+	}
+	// 模型加载后匹配器中的 r.dom 写作 r_dom
+	m := strings.NewReplacer(" ", "", "\t", "", ".", "_").Replace(ast.Value)
+	switch {
+	case strings.Contains(m, "r_dom==p_dom") || strings.Contains(m, "p_dom==r_dom"):
+		return domainEqual
+	case strings.Contains(m, "p_dom"):
+		return domainPattern
+	default:
+		return domainIgnored
+	}
+}
+
+// domainCovers 判断规则域 policyDom 是否包含请求域 dom
+func (a *PolicyAnalyzer) domainCovers(policyDom, dom string) bool {
+	switch a.domains {
+	case domainIgnored:
+		return true
+	case domainPattern:
+		return policyDom == dom || util.KeyMatch(dom, policyDom)
+	default:
+		return policyDom == dom
+	}
+}
+
+// domainsOverlap 判断两条规则的域是否可能命中同一请求
+func (a *PolicyAnalyzer) domainsOverlap(x, y string) bool {
+	return a.domainCovers(x, y) || a.domainCovers(y, x)
+}
+
+// inheritEdge 继承关系，dom 为空表示不限域
+type inheritEdge struct {
+	parent string
+	dom    string
+}
+
+// loadInheritance 汇总所有 g/g2/g3... 关系，返回 成员 -> 直接上级 的映射，带域的关系记录所属域
+func (a *PolicyAnalyzer) loadInheritance() map[string][]inheritEdge {
+	parents := make(map[string][]inheritEdge)
+	for ptype := range a.enforcer.GetModel()["g"] {
+		for _, g := range a.enforcer.GetNamedGroupingPolicy(ptype) {
+			if len(g) < 2 {
+				continue
+			}
+			edge := inheritEdge{parent: g[1]}
+			if len(g) > 2 {
+				edge.dom = g[2]
+			}
+			parents[g[0]] = append(parents[g[0]], edge)
+		}
+	}
+	return parents
+}
+
+// findShadowed 查找被覆盖的规则（重复规则、继承角色已授予的规则、通配符冗余规则）
+func (a *PolicyAnalyzer) findShadowed(rules []policyRule, parents map[string][]inheritEdge) []PolicyFinding {
+	findings := make([]PolicyFinding, 0)
+	for i, r := range rules {
+		ancestors := collectAncestors(r.sub, r.dom, parents)
+		for j, other := range rules {
+			if i == j || r.eft != other.eft || !a.domainCovers(other.dom, r.dom) || r.extra != other.extra {
+				continue
+			}
+			// 优先级模型中，后匹配的规则不会覆盖先匹配的规则
+			if !other.precedes(r) {
+				continue
+			}
+			sameSub := other.sub == r.sub
+			if !sameSub && !ancestors[other.sub] {
+				continue
+			}
+			if !actionsCover(other.actions, r.actions) || !util.KeyMatch2(r.obj, other.obj) {
+				continue
+			}
+			// 完全相同的规则只报告后出现（或优先级更低）的那一条
+			if sameSub && r.obj == other.obj && equalActions(r.actions, other.actions) && r.priority == other.priority && j > i {
+				continue
+			}
+
+			finding := PolicyFinding{
+				Policy:  r.raw,
+				Related: [][]string{other.raw},
+			}
+			switch {
+			case r.obj != other.obj:
+				finding.Type = FindingRedundant
+				finding.Message = fmt.Sprintf("规则 %s 已被通配规则 %s 覆盖", strings.Join(r.raw, ", "), strings.Join(other.raw, ", "))
+			case sameSub:
+				finding.Type = FindingShadowed
+				finding.Message = fmt.Sprintf("规则 %s 与同主体规则重复或被其包含", strings.Join(r.raw, ", "))
+			default:
+				finding.Type = FindingShadowed
+				finding.Message = fmt.Sprintf("规则 %s 已通过继承的 %s 获得", strings.Join(r.raw, ", "), other.sub)
+			}
+			findings = append(findings, finding)
+			break
+		}
+	}
+	return findings
+}
+
+// findContradictions 查找同一主体（含继承）在重叠对象和操作上同时存在 allow 和 deny 的规则
+func (a *PolicyAnalyzer) findContradictions(rules []policyRule, parents map[string][]inheritEdge) []PolicyFinding {
+	findings := make([]PolicyFinding, 0)
+	for i, r := range rules {
+		if r.eft != "deny" {
+			continue
+		}
+		ancestors := collectAncestors(r.sub, r.dom, parents)
+		related := make([][]string, 0)
+		winners := 0
+		for j, other := range rules {
+			if i == j || other.eft == "deny" || !a.domainsOverlap(r.dom, other.dom) || other.extra != r.extra {
+				continue
+			}
+			if other.sub != r.sub && !ancestors[other.sub] && !collectAncestors(other.sub, r.dom, parents)[r.sub] {
+				continue
+			}
+			if !actionsOverlap(r.actions, other.actions) || !objectsOverlap(r.obj, other.obj) {
+				continue
+			}
+			related = append(related, other.raw)
+			if r.hasPriority && other.hasPriority && other.priority < r.priority {
+				winners++
+			}
+		}
+		if len(related) > 0 {
+			msg := fmt.Sprintf("拒绝规则 %s 与 %d 条允许规则冲突", strings.Join(r.raw, ", "), len(related))
+			if r.hasPriority {
+				msg += fmt.Sprintf("，按优先级其中 %d 条允许规则先于该拒绝规则生效", winners)
+			}
+			findings = append(findings, PolicyFinding{
+				Type:    FindingContradiction,
+				Policy:  r.raw,
+				Related: related,
+				Message: msg,
+			})
+		}
+	}
+	return findings
+}
+
+// findOrphanSubjects 查找不是用户、直接和间接成员中也没有任何用户的策略主体
+// 例如角色 a 的唯一成员是没有成员的角色 b 时，a 和 b 都是孤立的
+func (a *PolicyAnalyzer) findOrphanSubjects(rules []policyRule) []PolicyFinding {
+	members := make(map[string][]string)
+	isMember := make(map[string]bool)
+	for ptype := range a.enforcer.GetModel()["g"] {
+		for _, g := range a.enforcer.GetNamedGroupingPolicy(ptype) {
+			if len(g) >= 2 {
+				isMember[g[0]] = true
+				members[g[1]] = append(members[g[1]], g[0])
+			}
+		}
+	}
+	isUser := func(sub string) bool {
+		return a.isUser(sub, isMember, len(members[sub]) > 0)
+	}
+
+	seen := make(map[string]bool)
+	findings := make([]PolicyFinding, 0)
+	for _, r := range rules {
+		if seen[r.sub] || reachesUser(r.sub, members, isUser) {
+			continue
+		}
+		seen[r.sub] = true
+		related := make([][]string, 0)
+		for _, other := range rules {
+			if other.sub == r.sub {
+				related = append(related, other.raw)
+			}
+		}
+		findings = append(findings, PolicyFinding{
+			Type:    FindingOrphanSubject,
+			Related: related,
+			Message: fmt.Sprintf("主体 %s 拥有 %d 条规则但没有关联任何用户", r.sub, len(related)),
+		})
+	}
+	return findings
+}
+
+// findRoleCycles 在每种分组关系中查找继承环
+func (a *PolicyAnalyzer) findRoleCycles() []PolicyFinding {
+	ptypes := make([]string, 0)
+	for ptype := range a.enforcer.GetModel()["g"] {
+		ptypes = append(ptypes, ptype)
+	}
+	sort.Strings(ptypes)
+
+	findings := make([]PolicyFinding, 0)
+	for _, ptype := range ptypes {
+		// 带域的关系按域分别建图
+		graphs := make(map[string]map[string][]string)
+		for _, g := range a.enforcer.GetNamedGroupingPolicy(ptype) {
+			if len(g) < 2 {
+				continue
+			}
+			dom := ""
+			if len(g) > 2 {
+				dom = g[2]
+			}
+			if graphs[dom] == nil {
+				graphs[dom] = make(map[string][]string)
+			}
+			graphs[dom][g[0]] = append(graphs[dom][g[0]], g[1])
+		}
+
+		for dom, graph := range graphs {
+			for _, cycle := range detectCycles(graph) {
+				msg := fmt.Sprintf("%s 存在继承环: %s", ptype, strings.Join(cycle, " -> "))
+				if dom != "" {
+					msg = fmt.Sprintf("%s 在域 %s 中存在继承环: %s", ptype, dom, strings.Join(cycle, " -> "))
+				}
+				findings = append(findings, PolicyFinding{
+					Type:    FindingRoleCycle,
+					Path:    cycle,
+					Message: msg,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// isUser 判断主体是否为用户；未配置前缀时，只作为成员出现、自身没有成员的主体视为用户
+func (a *PolicyAnalyzer) isUser(sub string, isMember map[string]bool, hasMembers bool) bool {
+	if a.userPrefix == "" {
+		return isMember[sub] && !hasMembers
+	}
+	return strings.HasPrefix(sub, a.userPrefix)
+}
+
+// reachesUser 判断主体本身或其直接、间接成员中是否有用户
+func reachesUser(sub string, members map[string][]string, isUser func(string) bool) bool {
+	visited := map[string]bool{sub: true}
+	stack := []string{sub}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if isUser(n) {
+			return true
+		}
+		for _, m := range members[n] {
+			if !visited[m] {
+				visited[m] = true
+				stack = append(stack, m)
+			}
+		}
+	}
+	return false
+}
+
+// collectAncestors 返回主体在域 dom 中通过继承关系可达的所有上级；
+// 不带域的关系在所有域生效，dom 为空（模型没有域）时沿所有关系查找
+func collectAncestors(sub, dom string, parents map[string][]inheritEdge) map[string]bool {
+	visited := make(map[string]bool)
+	stack := []string{sub}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range parents[n] {
+			if dom != "" && e.dom != "" && e.dom != dom {
+				continue
+			}
+			if !visited[e.parent] {
+				visited[e.parent] = true
+				stack = append(stack, e.parent)
+			}
+		}
+	}
+	delete(visited, sub)
+	return visited
+}
+
+// detectCycles 使用 DFS 查找图中的环，每个环只报告一次
+func detectCycles(graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	nodes := make([]string, 0, len(graph))
+	for n := range graph {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	state := make(map[string]int)
+	cycles := make([][]string, 0)
+	path := make([]string, 0)
+
+	var visit func(n string)
+	visit = func(n string) {
+		state[n] = visiting
+		path = append(path, n)
+		for _, next := range graph[n] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == next {
+						cycle := append(append([]string{}, path[i:]...), next)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = done
+	}
+
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+	return cycles
+}
+
+// parseActions 解析 "(GET)|(POST)" 形式的操作，返回 nil 表示匹配所有操作
+func parseActions(act string) map[string]bool {
+	if act == "" || act == "*" || act == ".*" {
+		return nil
+	}
+	actions := make(map[string]bool)
+	for _, part := range strings.Split(act, "|") {
+		part = strings.TrimSpace(strings.Trim(strings.TrimSpace(part), "()"))
+		if part == "*" || part == ".*" {
+			return nil
+		}
+		if part != "" {
+			actions[part] = true
+		}
+	}
+	return actions
+}
+
+// actionsCover 判断 outer 的操作集合是否包含 inner
+func actionsCover(outer, inner map[string]bool) bool {
+	if outer == nil {
+		return true
+	}
+	if inner == nil {
+		return false
+	}
+	for act := range inner {
+		if !outer[act] {
+			return false
+		}
+	}
+	return true
+}
+
+func actionsOverlap(a, b map[string]bool) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for act := range a {
+		if b[act] {
+			return true
+		}
+	}
+	return false
+}
+
+func equalActions(a, b map[string]bool) bool {
+	return actionsCover(a, b) && actionsCover(b, a)
+}
+
+// objectsOverlap 判断两个 keyMatch2 模式是否可能匹配同一路径
+func objectsOverlap(a, b string) bool {
+	return a == b || util.KeyMatch2(a, b) || util.KeyMatch2(b, a)
+}
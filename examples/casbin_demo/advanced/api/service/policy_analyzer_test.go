@@ -0,0 +1,351 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+const analyzerDomainModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act, eft
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && r.dom == p.dom && keyMatch2(r.obj, p.obj) && regexMatch(r.act, p.act)
+`
+
+const analyzerPriorityModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = priority, sub, dom, obj, act, eft
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = priority(p.eft) || deny
+
+[matchers]
+m = g(r.sub, p.sub) && r.dom == p.dom && keyMatch2(r.obj, p.obj) && regexMatch(r.act, p.act)
+`
+
+const analyzerPatternDomainModel = `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act, eft
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[matchers]
+m = g(r.sub, p.sub) && keyMatch(r.dom, p.dom) && keyMatch2(r.obj, p.obj) && regexMatch(r.act, p.act)
+`
+
+// newTestEnforcer 用模型文本和策略创建内存中的 enforcer
+func newTestEnforcer(t *testing.T, text string, policies, groupings [][]string) *casbin.Enforcer {
+	t.Helper()
+	m, err := model.NewModelFromString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) > 0 {
+		if _, err := e.AddPolicies(policies); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(groupings) > 0 {
+		if _, err := e.AddGroupingPolicies(groupings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return e
+}
+
+func findingsOfType(findings []PolicyFinding, typ FindingType) []PolicyFinding {
+	out := make([]PolicyFinding, 0)
+	for _, f := range findings {
+		if f.Type == typ {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestPolicyAnalyzerFindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		policies  [][]string
+		groupings [][]string
+		typ       FindingType
+		want      []string // 每条期望结果的 Policy（或 Path）拼接
+	}{
+		{
+			name:  "rule granted through inherited role",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/:id", "GET", "allow"},
+				{"user:1", "d1", "/docs/:id", "GET", "allow"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingShadowed,
+			want:      []string{"user:1,d1,/docs/:id,GET,allow"},
+		},
+		{
+			name:  "inheritance in another domain does not shadow",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/:id", "GET", "allow"},
+				{"user:1", "d1", "/docs/:id", "GET", "allow"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d2"}},
+			typ:       FindingShadowed,
+			want:      nil,
+		},
+		{
+			name:  "rule covered by wildcard is redundant",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/*", "(GET)|(PUT)", "allow"},
+				{"editor", "d1", "/docs/1", "GET", "allow"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingRedundant,
+			want:      []string{"editor,d1,/docs/1,GET,allow"},
+		},
+		{
+			name:  "narrower actions do not cover",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/*", "GET", "allow"},
+				{"editor", "d1", "/docs/1", "(GET)|(PUT)", "allow"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingRedundant,
+			want:      nil,
+		},
+		{
+			name:  "deny against inherited allow",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/*", "GET", "allow"},
+				{"user:1", "d1", "/docs/secret", "GET", "deny"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingContradiction,
+			want:      []string{"user:1,d1,/docs/secret,GET,deny"},
+		},
+		{
+			name:  "deny and allow in different domains",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d1", "/docs/*", "GET", "allow"},
+				{"user:1", "d2", "/docs/secret", "GET", "deny"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingContradiction,
+			want:      nil,
+		},
+		{
+			name:  "deny against allow of a role held only in another domain",
+			model: analyzerDomainModel,
+			policies: [][]string{
+				{"editor", "d2", "/docs/*", "GET", "allow"},
+				{"user:1", "d2", "/docs/secret", "GET", "deny"},
+			},
+			groupings: [][]string{{"user:1", "editor", "d1"}},
+			typ:       FindingContradiction,
+			want:      nil,
+		},
+		{
+			name:  "priority does not separate conflicting rules",
+			model: analyzerPriorityModel,
+			policies: [][]string{
+				{"1", "charlie", "platform", "/private/2", "(GET)|(PUT)", "deny"},
+				{"10", "vip_group", "platform", "/private/*", "(GET)|(POST)", "allow"},
+			},
+			groupings: [][]string{{"charlie", "vip_group"}},
+			typ:       FindingContradiction,
+			want:      []string{"1,charlie,platform,/private/2,(GET)|(PUT),deny"},
+		},
+		{
+			name:  "lower priority rule cannot shadow a higher priority one",
+			model: analyzerPriorityModel,
+			policies: [][]string{
+				{"1", "david", "platform", "/private/3", "GET", "allow"},
+				{"10", "vip_group", "platform", "/private/*", "GET", "allow"},
+			},
+			groupings: [][]string{{"david", "vip_group"}},
+			typ:       FindingRedundant,
+			want:      nil,
+		},
+		{
+			name:  "higher priority wildcard makes rule redundant",
+			model: analyzerPriorityModel,
+			policies: [][]string{
+				{"1", "vip_group", "platform", "/private/*", "GET", "allow"},
+				{"10", "david", "platform", "/private/3", "GET", "allow"},
+			},
+			groupings: [][]string{{"david", "vip_group"}},
+			typ:       FindingRedundant,
+			want:      []string{"10,david,platform,/private/3,GET,allow"},
+		},
+		{
+			name:     "role without members is orphan",
+			model:    analyzerDomainModel,
+			policies: [][]string{{"auditor", "d1", "/logs", "GET", "allow"}, {"user:1", "d1", "/me", "GET", "allow"}},
+			typ:      FindingOrphanSubject,
+			want:     []string{"auditor"},
+		},
+		{
+			name:      "role whose only member has no users is orphan",
+			model:     analyzerDomainModel,
+			policies:  [][]string{{"auditor", "d1", "/logs", "GET", "allow"}},
+			groupings: [][]string{{"auditor_lead", "auditor", "d1"}},
+			typ:       FindingOrphanSubject,
+			want:      []string{"auditor"},
+		},
+		{
+			name:      "role reached by a user through another role is not orphan",
+			model:     analyzerDomainModel,
+			policies:  [][]string{{"auditor", "d1", "/logs", "GET", "allow"}},
+			groupings: [][]string{{"user:1", "auditor_lead", "d1"}, {"auditor_lead", "auditor", "d1"}},
+			typ:       FindingOrphanSubject,
+			want:      nil,
+		},
+		{
+			name:  "wildcard domain rule shadows a rule in one domain",
+			model: analyzerPatternDomainModel,
+			policies: [][]string{
+				{"editor", "*", "/docs/1", "GET", "allow"},
+				{"editor", "d1", "/docs/1", "GET", "allow"},
+			},
+			groupings: [][]string{{"user:1", "editor"}},
+			typ:       FindingShadowed,
+			want:      []string{"editor,d1,/docs/1,GET,allow"},
+		},
+		{
+			name:  "deny against allow in a wildcard domain",
+			model: analyzerPatternDomainModel,
+			policies: [][]string{
+				{"editor", "tenant_*", "/docs/*", "GET", "allow"},
+				{"user:1", "tenant_a", "/docs/secret", "GET", "deny"},
+				{"user:1", "other", "/docs/secret", "GET", "deny"},
+			},
+			groupings: [][]string{{"user:1", "editor"}},
+			typ:       FindingContradiction,
+			want:      []string{"user:1,tenant_a,/docs/secret,GET,deny"},
+		},
+		{
+			name:      "role cycle in one domain",
+			model:     analyzerDomainModel,
+			policies:  [][]string{{"a", "d1", "/x", "GET", "allow"}},
+			groupings: [][]string{{"a", "b", "d1"}, {"b", "a", "d1"}, {"user:1", "a", "d1"}},
+			typ:       FindingRoleCycle,
+			want:      []string{"a,b,a"},
+		},
+		{
+			name:      "edges in different domains do not form a cycle",
+			model:     analyzerDomainModel,
+			policies:  [][]string{{"a", "d1", "/x", "GET", "allow"}},
+			groupings: [][]string{{"a", "b", "d1"}, {"b", "a", "d2"}, {"user:1", "a", "d1"}},
+			typ:       FindingRoleCycle,
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnforcer(t, tt.model, tt.policies, tt.groupings)
+			got := make([]string, 0)
+			for _, f := range findingsOfType(NewPolicyAnalyzer(e, "user:").Analyze(), tt.typ) {
+				switch {
+				case f.Policy != nil:
+					got = append(got, strings.Join(f.Policy, ","))
+				case f.Path != nil:
+					got = append(got, strings.Join(f.Path, ","))
+				default:
+					got = append(got, strings.SplitN(strings.TrimPrefix(f.Message, "主体 "), " ", 2)[0])
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("%s findings = %q, want %q", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyAnalyzerDuplicateRule(t *testing.T) {
+	rule := []string{"editor", "d1", "/docs/1", "GET", "allow"}
+	e := newTestEnforcer(t, analyzerDomainModel, [][]string{rule}, [][]string{{"user:1", "editor", "d1"}})
+	// AddPolicy 会拒绝重复规则，但从文件或数据库加载时可能出现，这里直接写入模型
+	ast := e.GetModel()["p"]["p"]
+	ast.Policy = append(ast.Policy, rule)
+
+	shadowed := findingsOfType(NewPolicyAnalyzer(e, "user:").Analyze(), FindingShadowed)
+	if len(shadowed) != 1 {
+		t.Fatalf("shadowed findings = %+v, want exactly one for the duplicate", shadowed)
+	}
+}
+
+func TestPolicyAnalyzerPriorityModelFile(t *testing.T) {
+	e, err := casbin.NewEnforcer("../../doc_priority_model.conf", "../../doc_priority_policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contradictions := findingsOfType(NewPolicyAnalyzer(e, "").Analyze(), FindingContradiction)
+	for _, f := range contradictions {
+		if f.Policy[1] == "charlie" {
+			if len(f.Related) != 1 || f.Related[0][1] != "vip_group" {
+				t.Fatalf("charlie deny related = %v", f.Related)
+			}
+			return
+		}
+	}
+	t.Fatalf("charlie deny vs vip_group allow not reported: %+v", contradictions)
+}
+
+func TestCheckPolicyConflictsDomain(t *testing.T) {
+	e := newTestEnforcer(t, analyzerDomainModel,
+		[][]string{
+			{"editor", "d1", "/docs/*", "GET", "allow"},
+			{"editor", "d2", "/docs/*", "GET", "allow"},
+			{"user:1", "d1", "/docs/secret", "GET", "deny"},
+			{"user:1", "d2", "/docs/secret", "GET", "deny"},
+		},
+		[][]string{{"user:1", "editor", "d1"}},
+	)
+	s := NewAuthService(nil, e)
+
+	conflict, matched, err := s.CheckPolicyConflicts("user:1", "d1", "/docs/secret", "GET")
+	if err != nil || !conflict || len(matched) != 2 {
+		t.Fatalf("d1: conflict = %v, matched = %v, err = %v", conflict, matched, err)
+	}
+	// user:1 只在 d1 拥有 editor，d2 的 allow 不会生效
+	conflict, _, err = s.CheckPolicyConflicts("user:1", "d2", "/docs/secret", "GET")
+	if err != nil || conflict {
+		t.Fatalf("d2: conflict = %v, err = %v", conflict, err)
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/casbin/casbin/v2"
+	"gorm.io/gorm"
+)
+
+// policyStep 一次 Casbin 变更及其撤销操作
+type policyStep struct {
+	apply func() (bool, error) // 返回 false 表示规则已存在或不存在，没有实际修改
+	undo  func() error
+	done  bool
+}
+
+// policyChanges 在数据库事务中记录的 Casbin 变更
+// Casbin 的内存策略（以及它自己的 adapter）不受 gorm 事务控制，
+// 因此变更先记录下来，数据库操作全部成功后再执行，事务回滚时按相反顺序撤销
+type policyChanges struct {
+	enforcer *casbin.Enforcer
+	steps    []*policyStep
+}
+
+func (c *policyChanges) add(apply func() (bool, error), undo func() error) {
+	c.steps = append(c.steps, &policyStep{apply: apply, undo: undo})
+}
+
+// AddGroupingPolicy 记录添加一条 g 规则
+func (c *policyChanges) AddGroupingPolicy(rule []string) {
+	c.add(func() (bool, error) { return c.enforcer.AddGroupingPolicy(rule) },
+		func() error { _, err := c.enforcer.RemoveGroupingPolicy(rule); return err })
+}
+
+// RemoveGroupingPolicy 记录删除一条 g 规则
+func (c *policyChanges) RemoveGroupingPolicy(rule []string) {
+	c.add(func() (bool, error) { return c.enforcer.RemoveGroupingPolicy(rule) },
+		func() error { _, err := c.enforcer.AddGroupingPolicy(rule); return err })
+}
+
+// AddPolicies 记录批量添加 p 规则，已存在的规则会导致整批不写入
+func (c *policyChanges) AddPolicies(rules [][]string) {
+	if len(rules) == 0 {
+		return
+	}
+	c.add(func() (bool, error) { return c.enforcer.AddPolicies(rules) },
+		func() error { _, err := c.enforcer.RemovePolicies(rules); return err })
+}
+
+// RemovePolicies 记录批量删除 p 规则
+func (c *policyChanges) RemovePolicies(rules [][]string) {
+	if len(rules) == 0 {
+		return
+	}
+	c.add(func() (bool, error) { return c.enforcer.RemovePolicies(rules) },
+		func() error { _, err := c.enforcer.AddPolicies(rules); return err })
+}
+
+// AddGroupingPolicies 记录批量添加 g 规则
+func (c *policyChanges) AddGroupingPolicies(rules [][]string) {
+	if len(rules) == 0 {
+		return
+	}
+	c.add(func() (bool, error) { return c.enforcer.AddGroupingPolicies(rules) },
+		func() error { _, err := c.enforcer.RemoveGroupingPolicies(rules); return err })
+}
+
+// RemoveGroupingPolicies 记录批量删除 g 规则
+func (c *policyChanges) RemoveGroupingPolicies(rules [][]string) {
+	if len(rules) == 0 {
+		return
+	}
+	c.add(func() (bool, error) { return c.enforcer.RemoveGroupingPolicies(rules) },
+		func() error { _, err := c.enforcer.AddGroupingPolicies(rules); return err })
+}
+
+// apply 依次执行所有变更，某一步失败时撤销已执行的变更
+func (c *policyChanges) apply() error {
+	for _, step := range c.steps {
+		changed, err := step.apply()
+		if err != nil {
+			return errors.Join(fmt.Errorf("同步 Casbin 策略失败: %w", err), c.rollback())
+		}
+		step.done = changed
+	}
+	return nil
+}
+
+// rollback 按相反顺序撤销已执行的变更
+func (c *policyChanges) rollback() error {
+	var errs []error
+	for i := len(c.steps) - 1; i >= 0; i-- {
+		step := c.steps[i]
+		if !step.done {
+			continue
+		}
+		if err := step.undo(); err != nil {
+			errs = append(errs, err)
+		}
+		step.done = false
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("撤销 Casbin 变更失败: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// withPolicyTransaction 在数据库事务中执行 fn，fn 中记录的 Casbin 变更在数据库操作成功后执行；
+// 变更失败时事务回滚，事务提交失败时撤销已执行的变更，保证两边一致
+func withPolicyTransaction(db *gorm.DB, enforcer *casbin.Enforcer, fn func(tx *gorm.DB, changes *policyChanges) error) error {
+	changes := &policyChanges{enforcer: enforcer}
+	applied := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx, changes); err != nil {
+			return err
+		}
+		if err := changes.apply(); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil && applied {
+		return errors.Join(err, changes.rollback())
+	}
+	return err
+}
@@ -0,0 +1,490 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+	"gorm.io/gorm"
+)
+
+// RoleService 角色服务：角色增删改查、角色继承和角色模板
+// 角色在 Casbin 中以角色名作为主体，父角色关系同步为 g(子角色, 父角色[, 域])
+type RoleService struct {
+	db          *gorm.DB
+	authService *AuthService
+}
+
+// NewRoleService 创建角色服务
+func NewRoleService(db *gorm.DB, authService *AuthService) *RoleService {
+	return &RoleService{
+		db:          db,
+		authService: authService,
+	}
+}
+
+// TemplatePermission 创建角色模板时的权限定义
+type TemplatePermission struct {
+	Object string `json:"object"`
+	Action string `json:"action"`
+	Effect string `json:"effect"` // 为空表示 allow
+}
+
+// CreateRole 创建角色，如果指定了父角色则同步继承关系
+func (s *RoleService) CreateRole(role *models.Role) error {
+	if role.Name == "" {
+		return fmt.Errorf("角色名不能为空")
+	}
+
+	return s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		return s.createRole(tx, changes, role)
+	})
+}
+
+// createRole 在事务中创建角色，继承关系记录到 changes
+func (s *RoleService) createRole(tx *gorm.DB, changes *policyChanges, role *models.Role) error {
+	var parent *models.Role
+	if role.ParentID != nil {
+		parent = &models.Role{}
+		if err := tx.First(parent, *role.ParentID).Error; err != nil {
+			return fmt.Errorf("父角色不存在: %w", err)
+		}
+	}
+
+	if err := s.checkName(tx, role.Name, role.Domain, 0); err != nil {
+		return err
+	}
+	if err := tx.Create(role).Error; err != nil {
+		return fmt.Errorf("创建角色失败: %w", err)
+	}
+
+	if parent != nil {
+		changes.AddGroupingPolicy(s.inheritanceRule(role, parent))
+	}
+	return nil
+}
+
+// GetRole 获取角色
+func (s *RoleService) GetRole(roleID uint) (*models.Role, error) {
+	var role models.Role
+	if err := s.db.First(&role, roleID).Error; err != nil {
+		return nil, fmt.Errorf("角色不存在: %w", err)
+	}
+	return &role, nil
+}
+
+// ListRoles 获取角色列表，domain 为空时返回所有角色
+func (s *RoleService) ListRoles(domain string) ([]models.Role, error) {
+	var roles []models.Role
+	query := s.db.Model(&models.Role{})
+	if domain != "" {
+		query = query.Where("domain = ? OR domain = ''", domain)
+	}
+	if err := query.Order("id").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("获取角色列表失败: %w", err)
+	}
+	return roles, nil
+}
+
+// UpdateRole 更新角色名称和描述，参数为空时保留原值；改名时同步重命名 Casbin 中该角色所在域的策略和继承关系
+func (s *RoleService) UpdateRole(roleID uint, name, description string) error {
+	return s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		var role models.Role
+		if err := tx.First(&role, roleID).Error; err != nil {
+			return fmt.Errorf("角色不存在: %w", err)
+		}
+
+		oldName := role.Name
+		if name != "" && name != oldName {
+			if err := s.checkName(tx, name, role.Domain, role.ID); err != nil {
+				return err
+			}
+			role.Name = name
+		}
+		if description != "" {
+			role.Description = description
+		}
+		if err := tx.Save(&role).Error; err != nil {
+			return fmt.Errorf("更新角色失败: %w", err)
+		}
+
+		if role.Name != oldName {
+			s.renameSubject(changes, oldName, role.Name, role.Domain)
+		}
+		return nil
+	})
+}
+
+// SetRoleParent 设置或清除父角色（parentID 为 nil），禁止形成继承环
+func (s *RoleService) SetRoleParent(roleID uint, parentID *uint) error {
+	return s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		var role models.Role
+		if err := tx.First(&role, roleID).Error; err != nil {
+			return fmt.Errorf("角色不存在: %w", err)
+		}
+
+		var parent *models.Role
+		if parentID != nil {
+			parent = &models.Role{}
+			if err := tx.First(parent, *parentID).Error; err != nil {
+				return fmt.Errorf("父角色不存在: %w", err)
+			}
+			if err := s.checkCycle(tx, roleID, parent); err != nil {
+				return err
+			}
+		}
+
+		// 移除旧的继承关系
+		if role.ParentID != nil {
+			var oldParent models.Role
+			err := tx.First(&oldParent, *role.ParentID).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("获取父角色失败: %w", err)
+			}
+			if err == nil {
+				changes.RemoveGroupingPolicy(s.inheritanceRule(&role, &oldParent))
+			}
+		}
+
+		role.ParentID = parentID
+		if err := tx.Model(&role).Update("parent_id", parentID).Error; err != nil {
+			return fmt.Errorf("更新父角色失败: %w", err)
+		}
+
+		if parent != nil {
+			changes.AddGroupingPolicy(s.inheritanceRule(&role, parent))
+		}
+		return nil
+	})
+}
+
+// CloneRole 复制角色的权限和父角色，不复制用户关系
+func (s *RoleService) CloneRole(srcRoleID uint, newName string) (*models.Role, error) {
+	if newName == "" {
+		return nil, fmt.Errorf("角色名不能为空")
+	}
+	subIndex := s.authService.policyFieldIndex("sub")
+	if subIndex < 0 {
+		return nil, fmt.Errorf("模型未定义 p.sub")
+	}
+
+	var clone *models.Role
+	err := s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		var src models.Role
+		if err := tx.First(&src, srcRoleID).Error; err != nil {
+			return fmt.Errorf("角色不存在: %w", err)
+		}
+
+		clone = &models.Role{
+			Name:        newName,
+			Description: src.Description,
+			Domain:      src.Domain,
+			ParentID:    src.ParentID,
+		}
+		if err := s.createRole(tx, changes, clone); err != nil {
+			return err
+		}
+
+		policies, _ := s.roleRules(&src)
+		copied := make([][]string, 0, len(policies))
+		for _, p := range policies {
+			rule := append([]string{}, p...)
+			rule[subIndex] = newName
+			copied = append(copied, rule)
+		}
+		changes.AddPolicies(copied)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// DeleteRole 删除角色
+// reassignTo 不为空时，将该角色的用户转移到指定角色；否则删除用户角色关系。
+// 子角色改为继承被删除角色的父角色，角色在所属域内的策略和继承关系一并清理
+func (s *RoleService) DeleteRole(roleID uint, reassignTo *uint) error {
+	return s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		var role models.Role
+		if err := tx.First(&role, roleID).Error; err != nil {
+			return fmt.Errorf("角色不存在: %w", err)
+		}
+
+		var target *models.Role
+		if reassignTo != nil {
+			if *reassignTo == roleID {
+				return fmt.Errorf("不能将用户转移到被删除的角色")
+			}
+			target = &models.Role{}
+			if err := tx.First(target, *reassignTo).Error; err != nil {
+				return fmt.Errorf("目标角色不存在: %w", err)
+			}
+		}
+
+		var parent *models.Role
+		if role.ParentID != nil {
+			parent = &models.Role{}
+			if err := tx.First(parent, *role.ParentID).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("获取父角色失败: %w", err)
+				}
+				parent = nil
+			}
+		}
+
+		// 先清理角色自身的策略和继承关系，后续新增的关系不会被误删
+		s.removeSubject(changes, &role)
+
+		// 1. 处理用户角色关系
+		var userRoles []models.UserRole
+		if err := tx.Where("role_id = ?", roleID).Find(&userRoles).Error; err != nil {
+			return fmt.Errorf("获取角色用户失败: %w", err)
+		}
+		for _, ur := range userRoles {
+			if target == nil {
+				continue
+			}
+			var count int64
+			if err := tx.Model(&models.UserRole{}).
+				Where("user_id = ? AND role_id = ?", ur.UserID, target.ID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("检查用户角色失败: %w", err)
+			}
+			if count == 0 {
+				if err := tx.Create(&models.UserRole{UserID: ur.UserID, RoleID: target.ID}).Error; err != nil {
+					return fmt.Errorf("转移用户角色失败: %w", err)
+				}
+			}
+			changes.AddGroupingPolicy(s.authService.groupingRule(fmt.Sprintf("user:%d", ur.UserID), target.Name, target.Domain))
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&models.UserRole{}).Error; err != nil {
+			return fmt.Errorf("删除用户角色关系失败: %w", err)
+		}
+
+		// 2. 子角色改为继承被删除角色的父角色
+		var children []models.Role
+		if err := tx.Where("parent_id = ?", roleID).Find(&children).Error; err != nil {
+			return fmt.Errorf("获取子角色失败: %w", err)
+		}
+		for i := range children {
+			child := &children[i]
+			var newParentID *uint
+			if parent != nil {
+				newParentID = &parent.ID
+			}
+			if err := tx.Model(child).Update("parent_id", newParentID).Error; err != nil {
+				return fmt.Errorf("更新子角色失败: %w", err)
+			}
+			if parent != nil {
+				changes.AddGroupingPolicy(s.inheritanceRule(child, parent))
+			}
+		}
+
+		// 3. 删除角色
+		if err := tx.Delete(&role).Error; err != nil {
+			return fmt.Errorf("删除角色失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateRoleTemplate 创建角色模板
+func (s *RoleService) CreateRoleTemplate(tpl *models.RoleTemplate, perms []TemplatePermission) error {
+	if tpl.Name == "" {
+		return fmt.Errorf("模板名不能为空")
+	}
+	if len(perms) == 0 {
+		return fmt.Errorf("模板至少需要一条权限")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tpl).Error; err != nil {
+			return fmt.Errorf("创建角色模板失败: %w", err)
+		}
+		for _, p := range perms {
+			effect := p.Effect
+			if effect == "" {
+				effect = EffectAllow
+			}
+			if effect != EffectAllow && effect != EffectDeny {
+				return fmt.Errorf("未知的策略效果: %s", effect)
+			}
+			perm := models.RoleTemplatePermission{
+				TemplateID: tpl.ID,
+				Object:     p.Object,
+				Action:     p.Action,
+				Effect:     effect,
+			}
+			if err := tx.Create(&perm).Error; err != nil {
+				return fmt.Errorf("创建模板权限失败: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListRoleTemplates 获取指定域可用的角色模板（包括全局模板）
+func (s *RoleService) ListRoleTemplates(domain string) ([]models.RoleTemplate, error) {
+	var templates []models.RoleTemplate
+	err := s.db.Where("domain = ? OR domain = ''", domain).Order("id").Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("获取角色模板失败: %w", err)
+	}
+	return templates, nil
+}
+
+// CreateRoleFromTemplate 根据模板在指定域创建角色，并写入模板中的权限
+func (s *RoleService) CreateRoleFromTemplate(templateID uint, roleName, domain string) (*models.Role, error) {
+	var tpl models.RoleTemplate
+	if err := s.db.First(&tpl, templateID).Error; err != nil {
+		return nil, fmt.Errorf("角色模板不存在: %w", err)
+	}
+	if tpl.Domain != "" && tpl.Domain != domain {
+		return nil, fmt.Errorf("角色模板 %s 不能用于域 %s", tpl.Name, domain)
+	}
+
+	var perms []models.RoleTemplatePermission
+	if err := s.db.Where("template_id = ?", templateID).Find(&perms).Error; err != nil {
+		return nil, fmt.Errorf("获取模板权限失败: %w", err)
+	}
+
+	// 先组装策略，模型不支持时不创建角色
+	rules := make([][]string, 0, len(perms))
+	for _, p := range perms {
+		rule, err := s.authService.buildPolicy(roleName, domain, p.Object, p.Action, p.Effect, PriorityGroup)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	role := &models.Role{
+		Name:        roleName,
+		Description: tpl.Description,
+		Domain:      domain,
+	}
+	err := s.transaction(func(tx *gorm.DB, changes *policyChanges) error {
+		if err := s.createRole(tx, changes, role); err != nil {
+			return err
+		}
+		changes.AddPolicies(rules)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// checkCycle 沿 parent 链向上查找，若回到 roleID 则说明会形成继承环
+func (s *RoleService) checkCycle(tx *gorm.DB, roleID uint, parent *models.Role) error {
+	visited := map[uint]bool{}
+	current := parent
+	for current != nil {
+		if current.ID == roleID {
+			return fmt.Errorf("角色继承关系存在环: %s", parent.Name)
+		}
+		if visited[current.ID] || current.ParentID == nil {
+			return nil
+		}
+		visited[current.ID] = true
+
+		next := &models.Role{}
+		if err := tx.First(next, *current.ParentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("检查角色继承关系失败: %w", err)
+		}
+		current = next
+	}
+	return nil
+}
+
+// checkName 检查角色名在域内是否可用
+// 全局角色在 Casbin 中对所有域生效，因此不能与任何域内角色同名
+func (s *RoleService) checkName(tx *gorm.DB, name, domain string, excludeID uint) error {
+	query := tx.Model(&models.Role{}).Where("name = ? AND id <> ?", name, excludeID)
+	if domain != "" {
+		query = query.Where("domain = ? OR domain = ''", domain)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("检查角色名失败: %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("角色 %s 已存在", name)
+	}
+	return nil
+}
+
+// transaction 在数据库事务中执行 fn，Casbin 变更在数据库操作成功后执行，失败时回滚或撤销
+func (s *RoleService) transaction(fn func(tx *gorm.DB, changes *policyChanges) error) error {
+	return withPolicyTransaction(s.db, s.authService.enforcer, fn)
+}
+
+// inheritanceRule 子角色继承父角色的 g 规则
+func (s *RoleService) inheritanceRule(child, parent *models.Role) []string {
+	return s.authService.groupingRule(child.Name, parent.Name, child.Domain)
+}
+
+// roleRules 返回角色在所属域内的 p 规则和 g 规则（角色作为成员或被继承方）
+// 全局角色（Domain 为空）包含所有域的规则
+func (s *RoleService) roleRules(role *models.Role) (policies, groupings [][]string) {
+	e := s.authService.enforcer
+
+	if subIndex := s.authService.policyFieldIndex("sub"); subIndex >= 0 {
+		domIndex := s.authService.policyFieldIndex("dom")
+		for _, p := range e.GetFilteredPolicy(subIndex, role.Name) {
+			if role.Domain == "" || domIndex < 0 || domIndex >= len(p) || p[domIndex] == role.Domain {
+				policies = append(policies, p)
+			}
+		}
+	}
+
+	for _, g := range e.GetGroupingPolicy() {
+		if len(g) < 2 || (g[0] != role.Name && g[1] != role.Name) {
+			continue
+		}
+		if role.Domain == "" || len(g) < 3 || g[2] == role.Domain {
+			groupings = append(groupings, g)
+		}
+	}
+	return policies, groupings
+}
+
+// renameSubject 将角色在所属域内以 oldName 为主体或角色的策略和继承关系改为 newName
+func (s *RoleService) renameSubject(changes *policyChanges, oldName, newName, domain string) {
+	policies, groupings := s.roleRules(&models.Role{Name: oldName, Domain: domain})
+
+	subIndex := s.authService.policyFieldIndex("sub")
+	renamed := make([][]string, 0, len(policies))
+	for _, p := range policies {
+		rule := append([]string{}, p...)
+		rule[subIndex] = newName
+		renamed = append(renamed, rule)
+	}
+	changes.RemovePolicies(policies)
+	changes.AddPolicies(renamed)
+
+	renamedGroupings := make([][]string, 0, len(groupings))
+	for _, g := range groupings {
+		rule := append([]string{}, g...)
+		for i := 0; i < 2; i++ {
+			if rule[i] == oldName {
+				rule[i] = newName
+			}
+		}
+		renamedGroupings = append(renamedGroupings, rule)
+	}
+	changes.RemoveGroupingPolicies(groupings)
+	changes.AddGroupingPolicies(renamedGroupings)
+}
+
+// removeSubject 删除角色在所属域内的所有策略和继承关系
+func (s *RoleService) removeSubject(changes *policyChanges, role *models.Role) {
+	policies, groupings := s.roleRules(role)
+	changes.RemovePolicies(policies)
+	changes.RemoveGroupingPolicies(groupings)
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接独立，限制为一个连接保证事务内外看到同一份数据
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newTestRoleService(t *testing.T) (*RoleService, *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t, &models.Role{}, &models.UserRole{}, &models.RoleTemplate{}, &models.RoleTemplatePermission{})
+	e := newTestEnforcer(t, analyzerDomainModel, nil, nil)
+	return NewRoleService(db, NewAuthService(db, e)), db
+}
+
+func mustCreateRole(t *testing.T, s *RoleService, role *models.Role) *models.Role {
+	t.Helper()
+	if err := s.CreateRole(role); err != nil {
+		t.Fatalf("CreateRole(%s) error = %v", role.Name, err)
+	}
+	return role
+}
+
+func sortedRules(rules [][]string) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, strings.Join(r, ","))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestCreateRoleNamePerDomain(t *testing.T) {
+	s, _ := newTestRoleService(t)
+
+	mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant1"})
+	mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant2"})
+
+	if err := s.CreateRole(&models.Role{Name: "editor", Domain: "tenant1"}); err == nil {
+		t.Fatal("同一域内重复的角色名应返回错误")
+	}
+	// 全局角色对所有域生效，不能与域内角色同名
+	if err := s.CreateRole(&models.Role{Name: "editor"}); err == nil {
+		t.Fatal("与域内角色同名的全局角色应返回错误")
+	}
+	mustCreateRole(t, s, &models.Role{Name: "auditor"})
+	if err := s.CreateRole(&models.Role{Name: "auditor", Domain: "tenant1"}); err == nil {
+		t.Fatal("与全局角色同名的域内角色应返回错误")
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	s, _ := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	parent := mustCreateRole(t, s, &models.Role{Name: "viewer", Domain: "tenant1"})
+	role := mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant1", Description: "编辑", ParentID: &parent.ID})
+	mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant2"})
+	e.AddPolicies([][]string{
+		{"editor", "tenant1", "/docs/*", "write", "allow"},
+		{"editor", "tenant2", "/docs/*", "read", "allow"},
+	})
+	e.AddGroupingPolicies([][]string{
+		{"user:1", "editor", "tenant1"},
+		{"user:2", "editor", "tenant2"},
+	})
+
+	if err := s.UpdateRole(role.ID, "writer", ""); err != nil {
+		t.Fatalf("UpdateRole() error = %v", err)
+	}
+
+	got, err := s.GetRole(role.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "writer" || got.Description != "编辑" {
+		t.Fatalf("role = %s/%q, want writer/编辑", got.Name, got.Description)
+	}
+
+	wantPolicies := []string{
+		"editor,tenant2,/docs/*,read,allow",
+		"writer,tenant1,/docs/*,write,allow",
+	}
+	if p := sortedRules(e.GetPolicy()); !reflect.DeepEqual(p, wantPolicies) {
+		t.Errorf("policies = %v, want %v", p, wantPolicies)
+	}
+	wantGroupings := []string{
+		"user:1,writer,tenant1",
+		"user:2,editor,tenant2",
+		"writer,viewer,tenant1",
+	}
+	if g := sortedRules(e.GetGroupingPolicy()); !reflect.DeepEqual(g, wantGroupings) {
+		t.Errorf("groupings = %v, want %v", g, wantGroupings)
+	}
+
+	if err := s.UpdateRole(role.ID, "", "新描述"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetRole(role.ID); got.Name != "writer" || got.Description != "新描述" {
+		t.Errorf("role = %s/%q, want writer/新描述", got.Name, got.Description)
+	}
+}
+
+func TestCloneRole(t *testing.T) {
+	s, _ := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	parent := mustCreateRole(t, s, &models.Role{Name: "viewer", Domain: "tenant1"})
+	src := mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant1", Description: "编辑", ParentID: &parent.ID})
+	e.AddPolicies([][]string{
+		{"editor", "tenant1", "/docs/*", "write", "allow"},
+		{"editor", "tenant1", "/admin/*", "*", "deny"},
+	})
+	e.AddGroupingPolicy("user:1", "editor", "tenant1")
+
+	clone, err := s.CloneRole(src.ID, "editor2")
+	if err != nil {
+		t.Fatalf("CloneRole() error = %v", err)
+	}
+	if clone.Domain != "tenant1" || clone.Description != "编辑" || clone.ParentID == nil || *clone.ParentID != parent.ID {
+		t.Errorf("clone = %+v", clone)
+	}
+	if ok, _ := e.Enforce("editor2", "tenant1", "/docs/1", "write"); !ok {
+		t.Error("克隆的角色应拥有源角色的权限")
+	}
+	if !e.HasGroupingPolicy("editor2", "viewer", "tenant1") {
+		t.Error("克隆的角色应继承源角色的父角色")
+	}
+	if e.HasGroupingPolicy("user:1", "editor2", "tenant1") {
+		t.Error("克隆不应复制用户关系")
+	}
+
+	// 名称冲突时既不创建角色也不写入策略
+	before := len(e.GetPolicy())
+	if _, err := s.CloneRole(src.ID, "viewer"); err == nil {
+		t.Fatal("克隆为已存在的角色名应返回错误")
+	}
+	if after := len(e.GetPolicy()); after != before {
+		t.Errorf("policies = %d, want %d", after, before)
+	}
+}
+
+func TestDeleteRole(t *testing.T) {
+	s, db := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	parent := mustCreateRole(t, s, &models.Role{Name: "viewer", Domain: "tenant1"})
+	role := mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant1", ParentID: &parent.ID})
+	child := mustCreateRole(t, s, &models.Role{Name: "intern", Domain: "tenant1", ParentID: &role.ID})
+	target := mustCreateRole(t, s, &models.Role{Name: "writer", Domain: "tenant1"})
+	mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant2"})
+
+	db.Create(&models.UserRole{UserID: 1, RoleID: role.ID})
+	e.AddPolicies([][]string{
+		{"editor", "tenant1", "/docs/*", "write", "allow"},
+		{"editor", "tenant2", "/docs/*", "read", "allow"},
+	})
+	e.AddGroupingPolicies([][]string{
+		{"user:1", "editor", "tenant1"},
+		{"user:2", "editor", "tenant2"},
+	})
+
+	if err := s.DeleteRole(role.ID, &target.ID); err != nil {
+		t.Fatalf("DeleteRole() error = %v", err)
+	}
+
+	wantPolicies := []string{"editor,tenant2,/docs/*,read,allow"}
+	if p := sortedRules(e.GetPolicy()); !reflect.DeepEqual(p, wantPolicies) {
+		t.Errorf("policies = %v, want %v", p, wantPolicies)
+	}
+	wantGroupings := []string{
+		"intern,viewer,tenant1",
+		"user:1,writer,tenant1",
+		"user:2,editor,tenant2",
+	}
+	if g := sortedRules(e.GetGroupingPolicy()); !reflect.DeepEqual(g, wantGroupings) {
+		t.Errorf("groupings = %v, want %v", g, wantGroupings)
+	}
+
+	var count int64
+	db.Model(&models.UserRole{}).Where("user_id = ? AND role_id = ?", 1, target.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("转移后的用户角色数 = %d, want 1", count)
+	}
+	if got, _ := s.GetRole(child.ID); got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Errorf("child.ParentID = %v, want %d", got.ParentID, parent.ID)
+	}
+}
+
+func TestDeleteRoleRollbackKeepsEnforcer(t *testing.T) {
+	s, db := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	parent := mustCreateRole(t, s, &models.Role{Name: "viewer", Domain: "tenant1"})
+	role := mustCreateRole(t, s, &models.Role{Name: "editor", Domain: "tenant1", ParentID: &parent.ID})
+	mustCreateRole(t, s, &models.Role{Name: "intern", Domain: "tenant1", ParentID: &role.ID})
+	e.AddPolicy("editor", "tenant1", "/docs/*", "write", "allow")
+
+	policies, groupings := sortedRules(e.GetPolicy()), sortedRules(e.GetGroupingPolicy())
+
+	errDelete := errors.New("磁盘已满")
+	db.Callback().Delete().Before("gorm:delete").Register("test:fail_role_delete", func(tx *gorm.DB) {
+		if tx.Statement.Table == "roles" {
+			tx.AddError(errDelete)
+		}
+	})
+
+	if err := s.DeleteRole(role.ID, nil); !errors.Is(err, errDelete) {
+		t.Fatalf("DeleteRole() error = %v, want %v", err, errDelete)
+	}
+	if p := sortedRules(e.GetPolicy()); !reflect.DeepEqual(p, policies) {
+		t.Errorf("policies = %v, want %v", p, policies)
+	}
+	if g := sortedRules(e.GetGroupingPolicy()); !reflect.DeepEqual(g, groupings) {
+		t.Errorf("groupings = %v, want %v", g, groupings)
+	}
+	if _, err := s.GetRole(role.ID); err != nil {
+		t.Errorf("事务回滚后角色应仍然存在: %v", err)
+	}
+}
+
+func TestSetRoleParentCycle(t *testing.T) {
+	s, db := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	a := mustCreateRole(t, s, &models.Role{Name: "a", Domain: "tenant1"})
+	b := mustCreateRole(t, s, &models.Role{Name: "b", Domain: "tenant1", ParentID: &a.ID})
+	c := mustCreateRole(t, s, &models.Role{Name: "c", Domain: "tenant1", ParentID: &b.ID})
+
+	if err := s.SetRoleParent(a.ID, &c.ID); err == nil || !strings.Contains(err.Error(), "环") {
+		t.Fatalf("SetRoleParent() error = %v, want cycle error", err)
+	}
+	if e.HasGroupingPolicy("a", "c", "tenant1") {
+		t.Error("形成环时不应写入继承关系")
+	}
+
+	if err := s.SetRoleParent(c.ID, &a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if e.HasGroupingPolicy("c", "b", "tenant1") {
+		t.Error("旧的继承关系应被移除")
+	}
+	if !e.HasGroupingPolicy("c", "a", "tenant1") {
+		t.Error("新的继承关系应被写入")
+	}
+
+	// 查询父角色出错时返回错误，而不是当作没有环
+	errQuery := errors.New("连接已断开")
+	db.Callback().Query().Before("gorm:query").Register("test:fail_query", func(tx *gorm.DB) {
+		tx.AddError(errQuery)
+	})
+	if err := s.checkCycle(db, a.ID, c); !errors.Is(err, errQuery) {
+		t.Errorf("checkCycle() error = %v, want %v", err, errQuery)
+	}
+}
+
+func TestCreateRoleFromTemplate(t *testing.T) {
+	s, _ := newTestRoleService(t)
+	e := s.authService.enforcer
+
+	tpl := &models.RoleTemplate{Name: "reader"}
+	if err := s.CreateRoleTemplate(tpl, []TemplatePermission{{Object: "/docs/*", Action: "read"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateRoleFromTemplate(tpl.ID, "reader", "tenant1"); err != nil {
+		t.Fatalf("CreateRoleFromTemplate() error = %v", err)
+	}
+	if ok, _ := e.Enforce("reader", "tenant1", "/docs/1", "read"); !ok {
+		t.Error("模板权限应写入角色所在域")
+	}
+
+	before := len(e.GetPolicy())
+	if _, err := s.CreateRoleFromTemplate(tpl.ID, "reader", "tenant1"); err == nil {
+		t.Fatal("重复的角色名应返回错误")
+	}
+	if after := len(e.GetPolicy()); after != before {
+		t.Errorf("policies = %d, want %d", after, before)
+	}
+}
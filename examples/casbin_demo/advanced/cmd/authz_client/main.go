@@ -0,0 +1,126 @@
+// authz_client 演示调用权限服务，以及用客户端授权中间件检查服务间调用
+//
+//	go run ./cmd/authz_client -addr 127.0.0.1:8890
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/transmeta"
+	"github.com/cloudwego/kitex/transport"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/rpc"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz/permissionservice"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8890", "权限服务地址")
+	flag.Parse()
+
+	opts := []client.Option{
+		client.WithHostPorts(*addr),
+		client.WithTransportProtocol(transport.TTHeader),
+		// 从传输层元信息中解析服务端返回的业务错误
+		client.WithMetaHandler(transmeta.ClientTTHeaderHandler),
+	}
+	cli, err := permissionservice.NewClient("authz-service", opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// 1. 单次和批量检查，HTTP 接口和服务间调用使用同一套策略
+	resp, err := cli.Enforce(ctx, &authz.EnforceRequest{Sub: "user:2", Dom: "platform", Obj: "/api/v1/documents/1", Act: "GET"})
+	if err != nil {
+		logError("Enforce", err)
+	} else {
+		log.Printf("user:2 GET /api/v1/documents/1: %v\n", resp.Allowed)
+	}
+
+	batch := []*authz.EnforceRequest{
+		{Sub: "svc:order", Dom: "platform", Obj: rpc.Object("calculator-service", "Add"), Act: rpc.ActionCall},
+		{Sub: "svc:order", Dom: "platform", Obj: rpc.Object("calculator-service", "Power"), Act: rpc.ActionCall},
+		{Sub: "user:2", Dom: "platform", Obj: "/api/v1/documents/1", Act: "PUT"},
+	}
+	batchResp, err := cli.BatchEnforce(ctx, &authz.BatchEnforceRequest{Requests: batch})
+	if err != nil {
+		logError("BatchEnforce", err)
+	} else {
+		for i, r := range batch {
+			log.Printf("%s %s %s: %v\n", r.Sub, r.Act, r.Obj, batchResp.Allowed[i])
+		}
+	}
+
+	// 缺少必填字段时返回业务错误
+	_, err = cli.Enforce(ctx, &authz.EnforceRequest{Sub: "user:1"})
+	logError("Enforce without obj", err)
+
+	// 2. 角色和权限查询，服务端要求调用方有查询权限，这里代表管理员 user:1 调用
+	adminCtx := rpc.WithSubject(ctx, "user:1")
+	roles, err := cli.GetRoles(adminCtx, &authz.GetRolesRequest{Sub: "svc:report", Dom: "platform"})
+	if err != nil {
+		logError("GetRoles", err)
+	} else {
+		log.Printf("svc:report roles: %v\n", roles.Roles)
+	}
+	perms, err := cli.GetPermissions(adminCtx, &authz.GetPermissionsRequest{Sub: "svc:order"})
+	if err != nil {
+		logError("GetPermissions", err)
+	} else {
+		for _, p := range perms.Permissions {
+			log.Printf("svc:order %s %s %v (from %v)\n", p.Effect, p.Obj, p.Actions, p.Sources)
+		}
+	}
+
+	// 没有声明主体或没有权限时，服务端授权中间件返回业务错误
+	_, err = cli.GetRoles(ctx, &authz.GetRolesRequest{Sub: "svc:report", Dom: "platform"})
+	logError("GetRoles without subject", err)
+	_, err = cli.GetPolicies(rpc.WithSubject(ctx, "svc:order"), &authz.GetPoliciesRequest{Sub: "role:admin"})
+	logError("GetPolicies as svc:order", err)
+
+	// 3. 客户端授权中间件：以 svc:order 的身份调用权限服务，调用前先检查权限
+	guarded, err := permissionservice.NewClient("authz-service", append(opts,
+		client.WithMiddleware(rpc.ClientAuth(rpc.ClientAuthConfig{
+			Authorizer: rpc.NewRemoteAuthorizer(cli, 5*time.Second),
+			Subject:    "svc:order",
+			Domain:     "platform",
+		})),
+	)...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// svc:order 没有调用权限服务的权限，请求不会发出
+	_, err = guarded.GetPolicies(ctx, &authz.GetPoliciesRequest{Sub: "role:admin"})
+	logError("GetPolicies as svc:order (client)", err)
+
+	// 代表管理员 user:1 调用，使用 user:1 的权限
+	policies, err := guarded.GetPolicies(rpc.WithSubject(ctx, "user:1"), &authz.GetPoliciesRequest{Sub: "role:calc_caller"})
+	if err != nil {
+		logError("GetPolicies as user:1", err)
+	} else {
+		for _, p := range policies.Policies {
+			log.Printf("policy: %v\n", p.Fields)
+		}
+	}
+}
+
+// logError 区分权限拒绝、业务错误和框架错误
+func logError(name string, err error) {
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		log.Printf("%s failed: %s (code %d: %s)\n",
+			name, authz.ErrorCode(bizErr.BizStatusCode()), bizErr.BizStatusCode(), bizErr.BizMessage())
+		return
+	}
+	if errors.Is(err, rpc.ErrForbidden) {
+		log.Printf("%s denied: %v\n", name, err)
+		return
+	}
+	log.Printf("%s failed: %v\n", name, err)
+}
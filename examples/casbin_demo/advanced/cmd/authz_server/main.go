@@ -0,0 +1,59 @@
+// authz_server 以 Kitex 服务的形式提供权限检查
+//
+//	go run ./cmd/authz_server -model rpc_model.conf -policy rpc_policy.csv
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/transmeta"
+	"github.com/cloudwego/kitex/server"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/rpc"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz/permissionservice"
+)
+
+// serviceName 权限服务的服务名
+const serviceName = "authz-service"
+
+func main() {
+	addr := flag.String("addr", ":8890", "监听地址")
+	modelPath := flag.String("model", "rpc_model.conf", "模型文件")
+	policyPath := flag.String("policy", "rpc_policy.csv", "策略文件")
+	domain := flag.String("domain", "platform", "检查调用方权限使用的域")
+	flag.Parse()
+
+	e, err := casbin.NewEnforcer(*modelPath, *policyPath)
+	if err != nil {
+		log.Fatalf("NewEnforcer failed: %v", err)
+	}
+	listen, err := net.ResolveTCPAddr("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// RPC 接口只用到 enforcer，不访问数据库
+	authService := service.NewAuthService(nil, e)
+
+	svr := permissionservice.NewServer(rpc.NewPermissionService(authService),
+		server.WithServiceAddr(listen),
+		server.WithServerBasicInfo(&rpcinfo.EndpointBasicInfo{ServiceName: serviceName}),
+		// 查询角色、权限和策略需要调用方有 /rpc/authz-service/<方法名> 的 CALL 权限；
+		// 权限检查只返回是否允许，各服务的 RemoteAuthorizer 在确认身份前就要调用，不要求主体
+		server.WithMiddleware(rpc.ServerAuth(rpc.ServerAuthConfig{
+			Authorizer: rpc.NewLocalAuthorizer(authService),
+			Domain:     *domain,
+			Skip:       []string{"Enforce", "BatchEnforce"},
+		})),
+		// 业务错误通过传输层元信息返回给客户端
+		server.WithMetaHandler(transmeta.ServerTTHeaderHandler),
+		server.WithMetaHandler(transmeta.ServerHTTP2Handler),
+	)
+	if err := svr.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
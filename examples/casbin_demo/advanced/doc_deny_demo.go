@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/casbin/casbin/v2"
+)
+
+/*
+  拒绝策略的两种语义:
+	deny-override（doc_restful_model.conf 等）:
+		e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+		只要命中任意一条 deny 规则即拒绝，适合"封禁"类需求
+	priority（doc_priority_model.conf）:
+		e = priority(p.eft) || deny
+		按 priority 从小到大取第一条命中的规则，适合"例外"类需求（既能例外拒绝，也能例外允许）
+*/
+
+// ExampleDenyOverride 用户组有权限，但单独禁止某个用户访问某个文档
+func ExampleDenyOverride() {
+	e, err := casbin.NewEnforcer("doc_restful_model.conf", "doc_restful_policy.csv")
+	if err != nil {
+		log.Fatalf("NewEnforcer failed: %v", err)
+	}
+
+	// user 组可以查看所有公共文档，但 charles 被禁止查看 doc1
+	if _, err := e.AddPolicy("charles", "/api/v1/documents/public/doc1", "GET", "deny"); err != nil {
+		log.Printf("AddPolicy failed: %v", err)
+	}
+	// 管理员的全局权限不会被其他主体的拒绝规则影响，但可以单独拒绝
+	if _, err := e.AddPolicy("alice", "/api/v1/documents/private/audit", "(PUT)|(DELETE)", "deny"); err != nil {
+		log.Printf("AddPolicy failed: %v", err)
+	}
+
+	testCases := []struct {
+		user   string
+		path   string
+		method string
+		desc   string
+	}{
+		{"charles", "/api/v1/documents/public/doc1", "GET", "被单独禁止的用户查看文档"},
+		{"charles", "/api/v1/documents/public/doc2", "GET", "被禁止用户查看其他文档"},
+		{"david", "/api/v1/documents/public/doc1", "GET", "同组其他用户查看文档"},
+		{"alice", "/api/v1/documents/public/doc1", "GET", "管理员查看文档"},
+		{"alice", "/api/v1/documents/private/audit", "DELETE", "管理员删除被锁定的审计文档"},
+	}
+
+	fmt.Println("\n=== deny-override 拒绝策略测试 ===")
+	for _, tc := range testCases {
+		ok, err := e.Enforce(tc.user, tc.path, tc.method)
+		if err != nil {
+			log.Printf("Enforce failed: %v", err)
+			continue
+		}
+		fmt.Printf("用户: %-8s | 操作: %-6s | 路径: %-35s | %-24s | 允许访问: %v\n",
+			tc.user, tc.method, tc.path, tc.desc, ok)
+	}
+}
+
+// ExamplePriorityPolicy 基于优先级的例外规则
+func ExamplePriorityPolicy() {
+	e, err := casbin.NewEnforcer("doc_priority_model.conf", "doc_priority_policy.csv")
+	if err != nil {
+		log.Fatalf("NewEnforcer failed: %v", err)
+	}
+
+	testCases := []struct {
+		user   string
+		path   string
+		method string
+		desc   string
+	}{
+		{"charlie", "/api/documents/private/1", "GET", "VIP用户查看私有文档"},
+		{"charlie", "/api/documents/private/2", "GET", "VIP用户查看被例外拒绝的文档"},
+		{"frank", "/api/documents/private/2", "GET", "其他VIP用户查看该文档"},
+		{"david", "/api/documents/private/3", "GET", "普通用户查看被例外允许的文档"},
+		{"eve", "/api/documents/private/3", "GET", "其他普通用户查看该文档"},
+		{"eve", "/api/documents/public/1", "GET", "普通用户查看公共文档"},
+	}
+
+	fmt.Println("\n=== priority 优先级策略测试 ===")
+	for _, tc := range testCases {
+		ok, err := e.Enforce(tc.user, "platform", tc.path, tc.method)
+		if err != nil {
+			log.Printf("Enforce failed: %v", err)
+			continue
+		}
+		fmt.Printf("用户: %-8s | 操作: %-6s | 路径: %-30s | %-24s | 允许访问: %v\n",
+			tc.user, tc.method, tc.path, tc.desc, ok)
+	}
+}
@@ -1,3 +1,9 @@
+//go:build ignore
+
+// 多域文档权限的中间件片段，演示按 X-Domain 头选择域，依赖 doc_restful_demo.go 中的 DocumentAPI，不参与构建
+
+package main
+
 // AuthMiddleware 权限检查中间件
 func (api *DocumentAPI) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -138,4 +144,4 @@ func (api *DocumentAPI) DeleteDocument(c *gin.Context) {
 
 	delete(api.docs, id)
 	c.Status(http.StatusNoContent)
-} 
\ No newline at end of file
+}
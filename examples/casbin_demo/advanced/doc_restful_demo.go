@@ -8,6 +8,9 @@ import (
 
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/handler"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/middleware"
+	"github.com/go-language-learning/examples/casbin_demo/advanced/api/service"
 )
 
 // Document 表示一个文档
@@ -50,35 +53,14 @@ func NewDocumentAPI() (*DocumentAPI, error) {
 }
 
 // AuthMiddleware 权限检查中间件
+// 按路由模板（如 /api/v1/documents/public/:id）检查权限，当前用户的权限查询只要求登录
 func (api *DocumentAPI) AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		user := c.GetHeader("X-User-ID") // 从请求头获取用户ID
-		if user == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "未认证"})
-			c.Abort()
-			return
-		}
-
-		// 构建权限检查请求
-		path := c.Request.URL.Path
-		method := c.Request.Method
-
-		// 检查权限
-		ok, err := api.enforcer.Enforce(user, path, method)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "权限检查失败"})
-			c.Abort()
-			return
-		}
-
-		if !ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": "没有权限"})
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
+	return middleware.RouteAuth(middleware.RouteAuthConfig{
+		Enforcer:           api.enforcer,
+		SubjectFunc:        middleware.HeaderSubject("X-User-ID"),
+		MatchRouteTemplate: true,
+		AuthOnly:           []string{"GET /api/v1/me/permissions", "POST /api/v1/me/permissions/check"},
+	})
 }
 
 // setupRouter 设置路由
@@ -117,6 +99,9 @@ func (api *DocumentAPI) setupRouter() *gin.Engine {
 				private.DELETE("/:id", api.DeleteDocument)
 			}
 		}
+
+		// 当前用户的有效权限和界面能力，供前端渲染菜单和按钮
+		handler.NewPermissionHandler(service.NewAuthService(nil, api.enforcer), "").RegisterRoutes(v1)
 	}
 
 	return r
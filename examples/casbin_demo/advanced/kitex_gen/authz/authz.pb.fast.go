@@ -0,0 +1,1059 @@
+// Code generated by Fastpb v0.0.2. DO NOT EDIT.
+
+package authz
+
+import (
+	fmt "fmt"
+	fastpb "github.com/cloudwego/fastpb"
+)
+
+var (
+	_ = fmt.Errorf
+	_ = fastpb.Skip
+)
+
+func (x *EnforceRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 2:
+		offset, err = x.fastReadField2(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 3:
+		offset, err = x.fastReadField3(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 4:
+		offset, err = x.fastReadField4(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_EnforceRequest[number], err)
+}
+
+func (x *EnforceRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Sub, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EnforceRequest) fastReadField2(buf []byte, _type int8) (offset int, err error) {
+	x.Dom, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EnforceRequest) fastReadField3(buf []byte, _type int8) (offset int, err error) {
+	x.Obj, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EnforceRequest) fastReadField4(buf []byte, _type int8) (offset int, err error) {
+	x.Act, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EnforceResponse) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_EnforceResponse[number], err)
+}
+
+func (x *EnforceResponse) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Allowed, offset, err = fastpb.ReadBool(buf, _type)
+	return offset, err
+}
+
+func (x *BatchEnforceRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_BatchEnforceRequest[number], err)
+}
+
+func (x *BatchEnforceRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	var v EnforceRequest
+	offset, err = fastpb.ReadMessage(buf, _type, &v)
+	if err != nil {
+		return offset, err
+	}
+	x.Requests = append(x.Requests, &v)
+	return offset, nil
+}
+
+func (x *BatchEnforceResponse) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_BatchEnforceResponse[number], err)
+}
+
+func (x *BatchEnforceResponse) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	offset, err = fastpb.ReadList(buf, _type,
+		func(buf []byte, _type int8) (n int, err error) {
+			var v bool
+			v, offset, err = fastpb.ReadBool(buf, _type)
+			if err != nil {
+				return offset, err
+			}
+			x.Allowed = append(x.Allowed, v)
+			return offset, err
+		})
+	return offset, err
+}
+
+func (x *GetRolesRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 2:
+		offset, err = x.fastReadField2(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetRolesRequest[number], err)
+}
+
+func (x *GetRolesRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Sub, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *GetRolesRequest) fastReadField2(buf []byte, _type int8) (offset int, err error) {
+	x.Dom, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *GetRolesResponse) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetRolesResponse[number], err)
+}
+
+func (x *GetRolesResponse) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	var v string
+	v, offset, err = fastpb.ReadString(buf, _type)
+	if err != nil {
+		return offset, err
+	}
+	x.Roles = append(x.Roles, v)
+	return offset, err
+}
+
+func (x *GetPermissionsRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetPermissionsRequest[number], err)
+}
+
+func (x *GetPermissionsRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Sub, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *Permission) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 2:
+		offset, err = x.fastReadField2(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 3:
+		offset, err = x.fastReadField3(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 4:
+		offset, err = x.fastReadField4(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 5:
+		offset, err = x.fastReadField5(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_Permission[number], err)
+}
+
+func (x *Permission) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Dom, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *Permission) fastReadField2(buf []byte, _type int8) (offset int, err error) {
+	x.Obj, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *Permission) fastReadField3(buf []byte, _type int8) (offset int, err error) {
+	var v string
+	v, offset, err = fastpb.ReadString(buf, _type)
+	if err != nil {
+		return offset, err
+	}
+	x.Actions = append(x.Actions, v)
+	return offset, err
+}
+
+func (x *Permission) fastReadField4(buf []byte, _type int8) (offset int, err error) {
+	x.Effect, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *Permission) fastReadField5(buf []byte, _type int8) (offset int, err error) {
+	var v string
+	v, offset, err = fastpb.ReadString(buf, _type)
+	if err != nil {
+		return offset, err
+	}
+	x.Sources = append(x.Sources, v)
+	return offset, err
+}
+
+func (x *GetPermissionsResponse) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetPermissionsResponse[number], err)
+}
+
+func (x *GetPermissionsResponse) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	var v Permission
+	offset, err = fastpb.ReadMessage(buf, _type, &v)
+	if err != nil {
+		return offset, err
+	}
+	x.Permissions = append(x.Permissions, &v)
+	return offset, nil
+}
+
+func (x *GetPoliciesRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetPoliciesRequest[number], err)
+}
+
+func (x *GetPoliciesRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Sub, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *Policy) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_Policy[number], err)
+}
+
+func (x *Policy) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	var v string
+	v, offset, err = fastpb.ReadString(buf, _type)
+	if err != nil {
+		return offset, err
+	}
+	x.Fields = append(x.Fields, v)
+	return offset, err
+}
+
+func (x *GetPoliciesResponse) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_GetPoliciesResponse[number], err)
+}
+
+func (x *GetPoliciesResponse) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	var v Policy
+	offset, err = fastpb.ReadMessage(buf, _type, &v)
+	if err != nil {
+		return offset, err
+	}
+	x.Policies = append(x.Policies, &v)
+	return offset, nil
+}
+
+func (x *EnforceRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	offset += x.fastWriteField2(buf[offset:])
+	offset += x.fastWriteField3(buf[offset:])
+	offset += x.fastWriteField4(buf[offset:])
+	return offset
+}
+
+func (x *EnforceRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Sub == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetSub())
+	return offset
+}
+
+func (x *EnforceRequest) fastWriteField2(buf []byte) (offset int) {
+	if x.Dom == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 2, x.GetDom())
+	return offset
+}
+
+func (x *EnforceRequest) fastWriteField3(buf []byte) (offset int) {
+	if x.Obj == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 3, x.GetObj())
+	return offset
+}
+
+func (x *EnforceRequest) fastWriteField4(buf []byte) (offset int) {
+	if x.Act == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 4, x.GetAct())
+	return offset
+}
+
+func (x *EnforceResponse) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *EnforceResponse) fastWriteField1(buf []byte) (offset int) {
+	if !x.Allowed {
+		return offset
+	}
+	offset += fastpb.WriteBool(buf[offset:], 1, x.GetAllowed())
+	return offset
+}
+
+func (x *BatchEnforceRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *BatchEnforceRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Requests == nil {
+		return offset
+	}
+	for i := range x.GetRequests() {
+		offset += fastpb.WriteMessage(buf[offset:], 1, x.GetRequests()[i])
+	}
+	return offset
+}
+
+func (x *BatchEnforceResponse) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *BatchEnforceResponse) fastWriteField1(buf []byte) (offset int) {
+	if len(x.Allowed) == 0 {
+		return offset
+	}
+	offset += fastpb.WriteListPacked(buf[offset:], 1, len(x.GetAllowed()),
+		func(buf []byte, numTagOrKey, numIdxOrVal int32) int {
+			offset := 0
+			offset += fastpb.WriteBool(buf[offset:], numTagOrKey, x.GetAllowed()[numIdxOrVal])
+			return offset
+		})
+	return offset
+}
+
+func (x *GetRolesRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	offset += x.fastWriteField2(buf[offset:])
+	return offset
+}
+
+func (x *GetRolesRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Sub == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetSub())
+	return offset
+}
+
+func (x *GetRolesRequest) fastWriteField2(buf []byte) (offset int) {
+	if x.Dom == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 2, x.GetDom())
+	return offset
+}
+
+func (x *GetRolesResponse) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *GetRolesResponse) fastWriteField1(buf []byte) (offset int) {
+	if len(x.Roles) == 0 {
+		return offset
+	}
+	for i := range x.GetRoles() {
+		offset += fastpb.WriteString(buf[offset:], 1, x.GetRoles()[i])
+	}
+	return offset
+}
+
+func (x *GetPermissionsRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *GetPermissionsRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Sub == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetSub())
+	return offset
+}
+
+func (x *Permission) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	offset += x.fastWriteField2(buf[offset:])
+	offset += x.fastWriteField3(buf[offset:])
+	offset += x.fastWriteField4(buf[offset:])
+	offset += x.fastWriteField5(buf[offset:])
+	return offset
+}
+
+func (x *Permission) fastWriteField1(buf []byte) (offset int) {
+	if x.Dom == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetDom())
+	return offset
+}
+
+func (x *Permission) fastWriteField2(buf []byte) (offset int) {
+	if x.Obj == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 2, x.GetObj())
+	return offset
+}
+
+func (x *Permission) fastWriteField3(buf []byte) (offset int) {
+	if len(x.Actions) == 0 {
+		return offset
+	}
+	for i := range x.GetActions() {
+		offset += fastpb.WriteString(buf[offset:], 3, x.GetActions()[i])
+	}
+	return offset
+}
+
+func (x *Permission) fastWriteField4(buf []byte) (offset int) {
+	if x.Effect == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 4, x.GetEffect())
+	return offset
+}
+
+func (x *Permission) fastWriteField5(buf []byte) (offset int) {
+	if len(x.Sources) == 0 {
+		return offset
+	}
+	for i := range x.GetSources() {
+		offset += fastpb.WriteString(buf[offset:], 5, x.GetSources()[i])
+	}
+	return offset
+}
+
+func (x *GetPermissionsResponse) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *GetPermissionsResponse) fastWriteField1(buf []byte) (offset int) {
+	if x.Permissions == nil {
+		return offset
+	}
+	for i := range x.GetPermissions() {
+		offset += fastpb.WriteMessage(buf[offset:], 1, x.GetPermissions()[i])
+	}
+	return offset
+}
+
+func (x *GetPoliciesRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *GetPoliciesRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Sub == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetSub())
+	return offset
+}
+
+func (x *Policy) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *Policy) fastWriteField1(buf []byte) (offset int) {
+	if len(x.Fields) == 0 {
+		return offset
+	}
+	for i := range x.GetFields() {
+		offset += fastpb.WriteString(buf[offset:], 1, x.GetFields()[i])
+	}
+	return offset
+}
+
+func (x *GetPoliciesResponse) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *GetPoliciesResponse) fastWriteField1(buf []byte) (offset int) {
+	if x.Policies == nil {
+		return offset
+	}
+	for i := range x.GetPolicies() {
+		offset += fastpb.WriteMessage(buf[offset:], 1, x.GetPolicies()[i])
+	}
+	return offset
+}
+
+func (x *EnforceRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	n += x.sizeField2()
+	n += x.sizeField3()
+	n += x.sizeField4()
+	return n
+}
+
+func (x *EnforceRequest) sizeField1() (n int) {
+	if x.Sub == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetSub())
+	return n
+}
+
+func (x *EnforceRequest) sizeField2() (n int) {
+	if x.Dom == "" {
+		return n
+	}
+	n += fastpb.SizeString(2, x.GetDom())
+	return n
+}
+
+func (x *EnforceRequest) sizeField3() (n int) {
+	if x.Obj == "" {
+		return n
+	}
+	n += fastpb.SizeString(3, x.GetObj())
+	return n
+}
+
+func (x *EnforceRequest) sizeField4() (n int) {
+	if x.Act == "" {
+		return n
+	}
+	n += fastpb.SizeString(4, x.GetAct())
+	return n
+}
+
+func (x *EnforceResponse) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *EnforceResponse) sizeField1() (n int) {
+	if !x.Allowed {
+		return n
+	}
+	n += fastpb.SizeBool(1, x.GetAllowed())
+	return n
+}
+
+func (x *BatchEnforceRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *BatchEnforceRequest) sizeField1() (n int) {
+	if x.Requests == nil {
+		return n
+	}
+	for i := range x.GetRequests() {
+		n += fastpb.SizeMessage(1, x.GetRequests()[i])
+	}
+	return n
+}
+
+func (x *BatchEnforceResponse) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *BatchEnforceResponse) sizeField1() (n int) {
+	if len(x.Allowed) == 0 {
+		return n
+	}
+	n += fastpb.SizeListPacked(1, len(x.GetAllowed()),
+		func(numTagOrKey, numIdxOrVal int32) int {
+			n := 0
+			n += fastpb.SizeBool(numTagOrKey, x.GetAllowed()[numIdxOrVal])
+			return n
+		})
+	return n
+}
+
+func (x *GetRolesRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	n += x.sizeField2()
+	return n
+}
+
+func (x *GetRolesRequest) sizeField1() (n int) {
+	if x.Sub == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetSub())
+	return n
+}
+
+func (x *GetRolesRequest) sizeField2() (n int) {
+	if x.Dom == "" {
+		return n
+	}
+	n += fastpb.SizeString(2, x.GetDom())
+	return n
+}
+
+func (x *GetRolesResponse) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *GetRolesResponse) sizeField1() (n int) {
+	if len(x.Roles) == 0 {
+		return n
+	}
+	for i := range x.GetRoles() {
+		n += fastpb.SizeString(1, x.GetRoles()[i])
+	}
+	return n
+}
+
+func (x *GetPermissionsRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *GetPermissionsRequest) sizeField1() (n int) {
+	if x.Sub == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetSub())
+	return n
+}
+
+func (x *Permission) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	n += x.sizeField2()
+	n += x.sizeField3()
+	n += x.sizeField4()
+	n += x.sizeField5()
+	return n
+}
+
+func (x *Permission) sizeField1() (n int) {
+	if x.Dom == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetDom())
+	return n
+}
+
+func (x *Permission) sizeField2() (n int) {
+	if x.Obj == "" {
+		return n
+	}
+	n += fastpb.SizeString(2, x.GetObj())
+	return n
+}
+
+func (x *Permission) sizeField3() (n int) {
+	if len(x.Actions) == 0 {
+		return n
+	}
+	for i := range x.GetActions() {
+		n += fastpb.SizeString(3, x.GetActions()[i])
+	}
+	return n
+}
+
+func (x *Permission) sizeField4() (n int) {
+	if x.Effect == "" {
+		return n
+	}
+	n += fastpb.SizeString(4, x.GetEffect())
+	return n
+}
+
+func (x *Permission) sizeField5() (n int) {
+	if len(x.Sources) == 0 {
+		return n
+	}
+	for i := range x.GetSources() {
+		n += fastpb.SizeString(5, x.GetSources()[i])
+	}
+	return n
+}
+
+func (x *GetPermissionsResponse) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *GetPermissionsResponse) sizeField1() (n int) {
+	if x.Permissions == nil {
+		return n
+	}
+	for i := range x.GetPermissions() {
+		n += fastpb.SizeMessage(1, x.GetPermissions()[i])
+	}
+	return n
+}
+
+func (x *GetPoliciesRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *GetPoliciesRequest) sizeField1() (n int) {
+	if x.Sub == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetSub())
+	return n
+}
+
+func (x *Policy) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *Policy) sizeField1() (n int) {
+	if len(x.Fields) == 0 {
+		return n
+	}
+	for i := range x.GetFields() {
+		n += fastpb.SizeString(1, x.GetFields()[i])
+	}
+	return n
+}
+
+func (x *GetPoliciesResponse) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *GetPoliciesResponse) sizeField1() (n int) {
+	if x.Policies == nil {
+		return n
+	}
+	for i := range x.GetPolicies() {
+		n += fastpb.SizeMessage(1, x.GetPolicies()[i])
+	}
+	return n
+}
+
+var fieldIDToName_EnforceRequest = map[int32]string{
+	1: "Sub",
+	2: "Dom",
+	3: "Obj",
+	4: "Act",
+}
+
+var fieldIDToName_EnforceResponse = map[int32]string{
+	1: "Allowed",
+}
+
+var fieldIDToName_BatchEnforceRequest = map[int32]string{
+	1: "Requests",
+}
+
+var fieldIDToName_BatchEnforceResponse = map[int32]string{
+	1: "Allowed",
+}
+
+var fieldIDToName_GetRolesRequest = map[int32]string{
+	1: "Sub",
+	2: "Dom",
+}
+
+var fieldIDToName_GetRolesResponse = map[int32]string{
+	1: "Roles",
+}
+
+var fieldIDToName_GetPermissionsRequest = map[int32]string{
+	1: "Sub",
+}
+
+var fieldIDToName_Permission = map[int32]string{
+	1: "Dom",
+	2: "Obj",
+	3: "Actions",
+	4: "Effect",
+	5: "Sources",
+}
+
+var fieldIDToName_GetPermissionsResponse = map[int32]string{
+	1: "Permissions",
+}
+
+var fieldIDToName_GetPoliciesRequest = map[int32]string{
+	1: "Sub",
+}
+
+var fieldIDToName_Policy = map[int32]string{
+	1: "Fields",
+}
+
+var fieldIDToName_GetPoliciesResponse = map[int32]string{
+	1: "Policies",
+}
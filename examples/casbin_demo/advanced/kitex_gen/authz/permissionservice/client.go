@@ -0,0 +1,73 @@
+// Code generated by Kitex v0.12.3. DO NOT EDIT.
+
+package permissionservice
+
+import (
+	"context"
+	client "github.com/cloudwego/kitex/client"
+	callopt "github.com/cloudwego/kitex/client/callopt"
+	authz "github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+// Client is designed to provide IDL-compatible methods with call-option parameter for kitex framework.
+type Client interface {
+	Enforce(ctx context.Context, Req *authz.EnforceRequest, callOptions ...callopt.Option) (r *authz.EnforceResponse, err error)
+	BatchEnforce(ctx context.Context, Req *authz.BatchEnforceRequest, callOptions ...callopt.Option) (r *authz.BatchEnforceResponse, err error)
+	GetRoles(ctx context.Context, Req *authz.GetRolesRequest, callOptions ...callopt.Option) (r *authz.GetRolesResponse, err error)
+	GetPermissions(ctx context.Context, Req *authz.GetPermissionsRequest, callOptions ...callopt.Option) (r *authz.GetPermissionsResponse, err error)
+	GetPolicies(ctx context.Context, Req *authz.GetPoliciesRequest, callOptions ...callopt.Option) (r *authz.GetPoliciesResponse, err error)
+}
+
+// NewClient creates a client for the service defined in IDL.
+func NewClient(destService string, opts ...client.Option) (Client, error) {
+	var options []client.Option
+	options = append(options, client.WithDestService(destService))
+
+	options = append(options, opts...)
+
+	kc, err := client.NewClient(serviceInfo(), options...)
+	if err != nil {
+		return nil, err
+	}
+	return &kPermissionServiceClient{
+		kClient: newServiceClient(kc),
+	}, nil
+}
+
+// MustNewClient creates a client for the service defined in IDL. It panics if any error occurs.
+func MustNewClient(destService string, opts ...client.Option) Client {
+	kc, err := NewClient(destService, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return kc
+}
+
+type kPermissionServiceClient struct {
+	*kClient
+}
+
+func (p *kPermissionServiceClient) Enforce(ctx context.Context, Req *authz.EnforceRequest, callOptions ...callopt.Option) (r *authz.EnforceResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Enforce(ctx, Req)
+}
+
+func (p *kPermissionServiceClient) BatchEnforce(ctx context.Context, Req *authz.BatchEnforceRequest, callOptions ...callopt.Option) (r *authz.BatchEnforceResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.BatchEnforce(ctx, Req)
+}
+
+func (p *kPermissionServiceClient) GetRoles(ctx context.Context, Req *authz.GetRolesRequest, callOptions ...callopt.Option) (r *authz.GetRolesResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetRoles(ctx, Req)
+}
+
+func (p *kPermissionServiceClient) GetPermissions(ctx context.Context, Req *authz.GetPermissionsRequest, callOptions ...callopt.Option) (r *authz.GetPermissionsResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetPermissions(ctx, Req)
+}
+
+func (p *kPermissionServiceClient) GetPolicies(ctx context.Context, Req *authz.GetPoliciesRequest, callOptions ...callopt.Option) (r *authz.GetPoliciesResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.GetPolicies(ctx, Req)
+}
@@ -0,0 +1,942 @@
+// Code generated by Kitex v0.12.3. DO NOT EDIT.
+
+package permissionservice
+
+import (
+	"context"
+	"errors"
+	client "github.com/cloudwego/kitex/client"
+	kitex "github.com/cloudwego/kitex/pkg/serviceinfo"
+	streaming "github.com/cloudwego/kitex/pkg/streaming"
+	authz "github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+	proto "google.golang.org/protobuf/proto"
+)
+
+var errInvalidMessageType = errors.New("invalid message type for service method handler")
+
+var serviceMethods = map[string]kitex.MethodInfo{
+	"Enforce": kitex.NewMethodInfo(
+		enforceHandler,
+		newEnforceArgs,
+		newEnforceResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"BatchEnforce": kitex.NewMethodInfo(
+		batchEnforceHandler,
+		newBatchEnforceArgs,
+		newBatchEnforceResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GetRoles": kitex.NewMethodInfo(
+		getRolesHandler,
+		newGetRolesArgs,
+		newGetRolesResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GetPermissions": kitex.NewMethodInfo(
+		getPermissionsHandler,
+		newGetPermissionsArgs,
+		newGetPermissionsResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"GetPolicies": kitex.NewMethodInfo(
+		getPoliciesHandler,
+		newGetPoliciesArgs,
+		newGetPoliciesResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+}
+
+var (
+	permissionServiceServiceInfo                = NewServiceInfo()
+	permissionServiceServiceInfoForClient       = NewServiceInfoForClient()
+	permissionServiceServiceInfoForStreamClient = NewServiceInfoForStreamClient()
+)
+
+// for server
+func serviceInfo() *kitex.ServiceInfo {
+	return permissionServiceServiceInfo
+}
+
+// for stream client
+func serviceInfoForStreamClient() *kitex.ServiceInfo {
+	return permissionServiceServiceInfoForStreamClient
+}
+
+// for client
+func serviceInfoForClient() *kitex.ServiceInfo {
+	return permissionServiceServiceInfoForClient
+}
+
+// NewServiceInfo creates a new ServiceInfo containing all methods
+func NewServiceInfo() *kitex.ServiceInfo {
+	return newServiceInfo(false, true, true)
+}
+
+// NewServiceInfo creates a new ServiceInfo containing non-streaming methods
+func NewServiceInfoForClient() *kitex.ServiceInfo {
+	return newServiceInfo(false, false, true)
+}
+func NewServiceInfoForStreamClient() *kitex.ServiceInfo {
+	return newServiceInfo(true, true, false)
+}
+
+func newServiceInfo(hasStreaming bool, keepStreamingMethods bool, keepNonStreamingMethods bool) *kitex.ServiceInfo {
+	serviceName := "PermissionService"
+	handlerType := (*authz.PermissionService)(nil)
+	methods := map[string]kitex.MethodInfo{}
+	for name, m := range serviceMethods {
+		if m.IsStreaming() && !keepStreamingMethods {
+			continue
+		}
+		if !m.IsStreaming() && !keepNonStreamingMethods {
+			continue
+		}
+		methods[name] = m
+	}
+	extra := map[string]interface{}{
+		"PackageName": "authz",
+	}
+	if hasStreaming {
+		extra["streaming"] = hasStreaming
+	}
+	svcInfo := &kitex.ServiceInfo{
+		ServiceName:     serviceName,
+		HandlerType:     handlerType,
+		Methods:         methods,
+		PayloadCodec:    kitex.Protobuf,
+		KiteXGenVersion: "v0.12.3",
+		Extra:           extra,
+	}
+	return svcInfo
+}
+
+func enforceHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(authz.EnforceRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(authz.PermissionService).Enforce(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *EnforceArgs:
+		success, err := handler.(authz.PermissionService).Enforce(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*EnforceResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newEnforceArgs() interface{} {
+	return &EnforceArgs{}
+}
+
+func newEnforceResult() interface{} {
+	return &EnforceResult{}
+}
+
+type EnforceArgs struct {
+	Req *authz.EnforceRequest
+}
+
+func (p *EnforceArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(authz.EnforceRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *EnforceArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *EnforceArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *EnforceArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *EnforceArgs) Unmarshal(in []byte) error {
+	msg := new(authz.EnforceRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var EnforceArgs_Req_DEFAULT *authz.EnforceRequest
+
+func (p *EnforceArgs) GetReq() *authz.EnforceRequest {
+	if !p.IsSetReq() {
+		return EnforceArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *EnforceArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *EnforceArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type EnforceResult struct {
+	Success *authz.EnforceResponse
+}
+
+var EnforceResult_Success_DEFAULT *authz.EnforceResponse
+
+func (p *EnforceResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(authz.EnforceResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *EnforceResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *EnforceResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *EnforceResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *EnforceResult) Unmarshal(in []byte) error {
+	msg := new(authz.EnforceResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *EnforceResult) GetSuccess() *authz.EnforceResponse {
+	if !p.IsSetSuccess() {
+		return EnforceResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *EnforceResult) SetSuccess(x interface{}) {
+	p.Success = x.(*authz.EnforceResponse)
+}
+
+func (p *EnforceResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *EnforceResult) GetResult() interface{} {
+	return p.Success
+}
+
+func batchEnforceHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(authz.BatchEnforceRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(authz.PermissionService).BatchEnforce(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *BatchEnforceArgs:
+		success, err := handler.(authz.PermissionService).BatchEnforce(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*BatchEnforceResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newBatchEnforceArgs() interface{} {
+	return &BatchEnforceArgs{}
+}
+
+func newBatchEnforceResult() interface{} {
+	return &BatchEnforceResult{}
+}
+
+type BatchEnforceArgs struct {
+	Req *authz.BatchEnforceRequest
+}
+
+func (p *BatchEnforceArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(authz.BatchEnforceRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *BatchEnforceArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *BatchEnforceArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *BatchEnforceArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *BatchEnforceArgs) Unmarshal(in []byte) error {
+	msg := new(authz.BatchEnforceRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var BatchEnforceArgs_Req_DEFAULT *authz.BatchEnforceRequest
+
+func (p *BatchEnforceArgs) GetReq() *authz.BatchEnforceRequest {
+	if !p.IsSetReq() {
+		return BatchEnforceArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *BatchEnforceArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *BatchEnforceArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type BatchEnforceResult struct {
+	Success *authz.BatchEnforceResponse
+}
+
+var BatchEnforceResult_Success_DEFAULT *authz.BatchEnforceResponse
+
+func (p *BatchEnforceResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(authz.BatchEnforceResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *BatchEnforceResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *BatchEnforceResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *BatchEnforceResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *BatchEnforceResult) Unmarshal(in []byte) error {
+	msg := new(authz.BatchEnforceResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *BatchEnforceResult) GetSuccess() *authz.BatchEnforceResponse {
+	if !p.IsSetSuccess() {
+		return BatchEnforceResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *BatchEnforceResult) SetSuccess(x interface{}) {
+	p.Success = x.(*authz.BatchEnforceResponse)
+}
+
+func (p *BatchEnforceResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *BatchEnforceResult) GetResult() interface{} {
+	return p.Success
+}
+
+func getRolesHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(authz.GetRolesRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(authz.PermissionService).GetRoles(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GetRolesArgs:
+		success, err := handler.(authz.PermissionService).GetRoles(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GetRolesResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGetRolesArgs() interface{} {
+	return &GetRolesArgs{}
+}
+
+func newGetRolesResult() interface{} {
+	return &GetRolesResult{}
+}
+
+type GetRolesArgs struct {
+	Req *authz.GetRolesRequest
+}
+
+func (p *GetRolesArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(authz.GetRolesRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *GetRolesArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *GetRolesArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *GetRolesArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GetRolesArgs) Unmarshal(in []byte) error {
+	msg := new(authz.GetRolesRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GetRolesArgs_Req_DEFAULT *authz.GetRolesRequest
+
+func (p *GetRolesArgs) GetReq() *authz.GetRolesRequest {
+	if !p.IsSetReq() {
+		return GetRolesArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GetRolesArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GetRolesArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GetRolesResult struct {
+	Success *authz.GetRolesResponse
+}
+
+var GetRolesResult_Success_DEFAULT *authz.GetRolesResponse
+
+func (p *GetRolesResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(authz.GetRolesResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *GetRolesResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *GetRolesResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *GetRolesResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GetRolesResult) Unmarshal(in []byte) error {
+	msg := new(authz.GetRolesResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GetRolesResult) GetSuccess() *authz.GetRolesResponse {
+	if !p.IsSetSuccess() {
+		return GetRolesResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GetRolesResult) SetSuccess(x interface{}) {
+	p.Success = x.(*authz.GetRolesResponse)
+}
+
+func (p *GetRolesResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetRolesResult) GetResult() interface{} {
+	return p.Success
+}
+
+func getPermissionsHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(authz.GetPermissionsRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(authz.PermissionService).GetPermissions(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GetPermissionsArgs:
+		success, err := handler.(authz.PermissionService).GetPermissions(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GetPermissionsResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGetPermissionsArgs() interface{} {
+	return &GetPermissionsArgs{}
+}
+
+func newGetPermissionsResult() interface{} {
+	return &GetPermissionsResult{}
+}
+
+type GetPermissionsArgs struct {
+	Req *authz.GetPermissionsRequest
+}
+
+func (p *GetPermissionsArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(authz.GetPermissionsRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *GetPermissionsArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *GetPermissionsArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *GetPermissionsArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GetPermissionsArgs) Unmarshal(in []byte) error {
+	msg := new(authz.GetPermissionsRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GetPermissionsArgs_Req_DEFAULT *authz.GetPermissionsRequest
+
+func (p *GetPermissionsArgs) GetReq() *authz.GetPermissionsRequest {
+	if !p.IsSetReq() {
+		return GetPermissionsArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GetPermissionsArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GetPermissionsArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GetPermissionsResult struct {
+	Success *authz.GetPermissionsResponse
+}
+
+var GetPermissionsResult_Success_DEFAULT *authz.GetPermissionsResponse
+
+func (p *GetPermissionsResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(authz.GetPermissionsResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *GetPermissionsResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *GetPermissionsResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *GetPermissionsResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GetPermissionsResult) Unmarshal(in []byte) error {
+	msg := new(authz.GetPermissionsResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GetPermissionsResult) GetSuccess() *authz.GetPermissionsResponse {
+	if !p.IsSetSuccess() {
+		return GetPermissionsResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GetPermissionsResult) SetSuccess(x interface{}) {
+	p.Success = x.(*authz.GetPermissionsResponse)
+}
+
+func (p *GetPermissionsResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetPermissionsResult) GetResult() interface{} {
+	return p.Success
+}
+
+func getPoliciesHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(authz.GetPoliciesRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(authz.PermissionService).GetPolicies(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *GetPoliciesArgs:
+		success, err := handler.(authz.PermissionService).GetPolicies(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*GetPoliciesResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newGetPoliciesArgs() interface{} {
+	return &GetPoliciesArgs{}
+}
+
+func newGetPoliciesResult() interface{} {
+	return &GetPoliciesResult{}
+}
+
+type GetPoliciesArgs struct {
+	Req *authz.GetPoliciesRequest
+}
+
+func (p *GetPoliciesArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(authz.GetPoliciesRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *GetPoliciesArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *GetPoliciesArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *GetPoliciesArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *GetPoliciesArgs) Unmarshal(in []byte) error {
+	msg := new(authz.GetPoliciesRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var GetPoliciesArgs_Req_DEFAULT *authz.GetPoliciesRequest
+
+func (p *GetPoliciesArgs) GetReq() *authz.GetPoliciesRequest {
+	if !p.IsSetReq() {
+		return GetPoliciesArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *GetPoliciesArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *GetPoliciesArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type GetPoliciesResult struct {
+	Success *authz.GetPoliciesResponse
+}
+
+var GetPoliciesResult_Success_DEFAULT *authz.GetPoliciesResponse
+
+func (p *GetPoliciesResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(authz.GetPoliciesResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *GetPoliciesResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *GetPoliciesResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *GetPoliciesResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *GetPoliciesResult) Unmarshal(in []byte) error {
+	msg := new(authz.GetPoliciesResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *GetPoliciesResult) GetSuccess() *authz.GetPoliciesResponse {
+	if !p.IsSetSuccess() {
+		return GetPoliciesResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *GetPoliciesResult) SetSuccess(x interface{}) {
+	p.Success = x.(*authz.GetPoliciesResponse)
+}
+
+func (p *GetPoliciesResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *GetPoliciesResult) GetResult() interface{} {
+	return p.Success
+}
+
+type kClient struct {
+	c client.Client
+}
+
+func newServiceClient(c client.Client) *kClient {
+	return &kClient{
+		c: c,
+	}
+}
+
+func (p *kClient) Enforce(ctx context.Context, Req *authz.EnforceRequest) (r *authz.EnforceResponse, err error) {
+	var _args EnforceArgs
+	_args.Req = Req
+	var _result EnforceResult
+	if err = p.c.Call(ctx, "Enforce", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) BatchEnforce(ctx context.Context, Req *authz.BatchEnforceRequest) (r *authz.BatchEnforceResponse, err error) {
+	var _args BatchEnforceArgs
+	_args.Req = Req
+	var _result BatchEnforceResult
+	if err = p.c.Call(ctx, "BatchEnforce", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GetRoles(ctx context.Context, Req *authz.GetRolesRequest) (r *authz.GetRolesResponse, err error) {
+	var _args GetRolesArgs
+	_args.Req = Req
+	var _result GetRolesResult
+	if err = p.c.Call(ctx, "GetRoles", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GetPermissions(ctx context.Context, Req *authz.GetPermissionsRequest) (r *authz.GetPermissionsResponse, err error) {
+	var _args GetPermissionsArgs
+	_args.Req = Req
+	var _result GetPermissionsResult
+	if err = p.c.Call(ctx, "GetPermissions", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) GetPolicies(ctx context.Context, Req *authz.GetPoliciesRequest) (r *authz.GetPoliciesResponse, err error) {
+	var _args GetPoliciesArgs
+	_args.Req = Req
+	var _result GetPoliciesResult
+	if err = p.c.Call(ctx, "GetPolicies", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
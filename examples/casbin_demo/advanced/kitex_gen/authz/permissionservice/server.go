@@ -0,0 +1,24 @@
+// Code generated by Kitex v0.12.3. DO NOT EDIT.
+package permissionservice
+
+import (
+	server "github.com/cloudwego/kitex/server"
+	authz "github.com/go-language-learning/examples/casbin_demo/advanced/kitex_gen/authz"
+)
+
+// NewServer creates a server.Server with the given handler and options.
+func NewServer(handler authz.PermissionService, opts ...server.Option) server.Server {
+	var options []server.Option
+
+	options = append(options, opts...)
+
+	svr := server.NewServer(options...)
+	if err := svr.RegisterService(serviceInfo(), handler); err != nil {
+		panic(err)
+	}
+	return svr
+}
+
+func RegisterService(svr server.Server, handler authz.PermissionService, opts ...server.RegisterOption) error {
+	return svr.RegisterService(serviceInfo(), handler, opts...)
+}
@@ -359,4 +359,6 @@ func main() {
 	ExampleDomainIsolation()
 	ExampleABAC()
 	ExampleAPIAccess()
+	ExampleDenyOverride()
+	ExamplePriorityPolicy()
 }
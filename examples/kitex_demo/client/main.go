@@ -2,45 +2,135 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"io"
 	"log"
 	"time"
 
+	"example/examples/kitex_demo/discovery"
 	"example/examples/kitex_demo/kitex_gen/api"
 	"example/examples/kitex_demo/kitex_gen/api/calculator"
+	"example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+	healthsvc "example/examples/kitex_demo/kitex_gen/grpc_health_v1/health"
+	"example/examples/kitex_demo/middleware"
 
 	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/client/callopt"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/transmeta"
 )
 
 func main() {
-	client, err := calculator.NewClient(
-		"calculator-service",
-		client.WithHostPorts("127.0.0.1:8888"),
-	)
+	configPath := flag.String("config", "conf/middleware.yaml", "中间件配置文件")
+	discoveryPath := flag.String("discovery", "conf/discovery.yaml", "服务发现配置文件")
+	flag.Parse()
+
+	cfg, err := middleware.Load(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	// 测试加法
-	addReq := &api.CalcRequest{
-		A: 10,
-		B: 20,
+	dcfg, err := discovery.Load(*discoveryPath)
+	if err != nil {
+		log.Fatal(err)
 	}
-	addResp, err := client.Add(context.Background(), addReq)
+	discoveryOpts, err := discovery.ClientOptions(dcfg)
 	if err != nil {
 		log.Fatal(err)
 	}
-	log.Printf("Add result: %d + %d = %d\n", addReq.A, addReq.B, addResp.Result)
 
-	// 测试减法
-	subReq := &api.CalcRequest{
-		A: 30,
-		B: 15,
+	opts := []client.Option{
+		// 从传输层元信息中解析服务端返回的业务错误
+		client.WithMetaHandler(transmeta.ClientHTTP2Handler),
 	}
-	subResp, err := client.Subtract(context.Background(), subReq)
+	opts = append(opts, discoveryOpts...)
+	ctx := context.Background()
+
+	// 调用前先通过标准健康检查协议确认服务可用，停机中的实例返回 NOT_SERVING
+	checkHealth(ctx, opts)
+
+	client, err := calculator.NewClient("calculator-service", append(opts, middleware.ClientOptions(cfg.Client)...)...)
 	if err != nil {
 		log.Fatal(err)
 	}
-	log.Printf("Subtract result: %d - %d = %d\n", subReq.A, subReq.B, subResp.Result)
+
+	// 测试四则运算
+	ops := []struct {
+		name string
+		sym  string
+		call func(context.Context, *api.CalcRequest, ...callopt.Option) (*api.CalcResponse, error)
+		req  *api.CalcRequest
+	}{
+		{"Add", "+", client.Add, &api.CalcRequest{A: 10, B: 20}},
+		{"Subtract", "-", client.Subtract, &api.CalcRequest{A: 30, B: 15}},
+		{"Multiply", "*", client.Multiply, &api.CalcRequest{A: 6, B: 7}},
+		{"Divide", "/", client.Divide, &api.CalcRequest{A: 100, B: 7}},
+		{"Modulo", "%", client.Modulo, &api.CalcRequest{A: 100, B: 7}},
+		{"Power", "^", client.Power, &api.CalcRequest{A: 2, B: 10}},
+		{"Divide", "/", client.Divide, &api.CalcRequest{A: 1, B: 0}},
+		{"Power", "^", client.Power, &api.CalcRequest{A: 10, B: 19}},
+	}
+	for _, op := range ops {
+		resp, err := op.call(ctx, op.req)
+		if err != nil {
+			logError(op.name, err)
+			continue
+		}
+		log.Printf("%s result: %d %s %d = %d\n", op.name, op.req.A, op.sym, op.req.B, resp.Result)
+	}
+
+	// 测试表达式求值，服务端逐步返回计算过程
+	for _, expr := range []string{"(1 + 2) * 3 - 4 / 2", "2 ^ 3 ^ 2 % 7", "10 / (5 - 5)", "1 +"} {
+		evaluate(ctx, client, expr)
+	}
 
 	time.Sleep(time.Second) // 等待日志输出
 }
+
+// checkHealth 查询计算服务的健康状态
+func checkHealth(ctx context.Context, opts []client.Option) {
+	cli, err := healthsvc.NewClient("calculator-service", opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err := cli.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "api.Calculator"})
+	if err != nil {
+		log.Printf("Health check failed: %v\n", err)
+		return
+	}
+	log.Printf("Health of api.Calculator: %s\n", resp.Status)
+}
+
+// evaluate 调用流式接口并打印每一步
+func evaluate(ctx context.Context, client calculator.Client, expr string) {
+	stream, err := client.Evaluate(ctx, &api.EvalRequest{Expression: expr})
+	if err != nil {
+		logError("Evaluate", err)
+		return
+	}
+	for {
+		step, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			logError("Evaluate "+expr, err)
+			return
+		}
+		if step.Done {
+			log.Printf("Evaluate result: %s = %d\n", step.Operation, step.Result)
+		} else {
+			log.Printf("  step: %s = %d\n", step.Operation, step.Result)
+		}
+	}
+}
+
+// logError 区分业务错误和框架错误
+func logError(name string, err error) {
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		log.Printf("%s failed: %s (code %d: %s)\n",
+			name, api.ErrorCode(bizErr.BizStatusCode()), bizErr.BizStatusCode(), bizErr.BizMessage())
+		return
+	}
+	log.Printf("%s failed: %v\n", name, err)
+}
@@ -0,0 +1,135 @@
+// Package discovery Kitex 服务注册与发现：服务端把自己注册到 etcd（带租约，进程退出或失联后自动下线），
+// 客户端从 etcd 或静态文件解析实例列表，并按权重负载均衡，通过 YAML 配置
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 注册中心类型
+const (
+	TypeEtcd   = "etcd"   // etcd 注册中心
+	TypeFile   = "file"   // 静态文件，用于离线测试
+	TypeDirect = "direct" // 直连固定地址，不做服务发现
+)
+
+// 负载均衡算法
+const (
+	WeightedRoundRobin = "weighted_round_robin"
+	WeightedRandom     = "weighted_random"
+)
+
+// Config 服务发现配置文件
+type Config struct {
+	Type   string       `yaml:"type"` // etcd / file / direct，默认 direct
+	Etcd   EtcdConfig   `yaml:"etcd"`
+	File   FileConfig   `yaml:"file"`
+	Direct DirectConfig `yaml:"direct"`
+	Server ServerConfig `yaml:"server"`
+	Client ClientConfig `yaml:"client"`
+}
+
+// EtcdConfig etcd 连接和租约配置
+type EtcdConfig struct {
+	Endpoints   []string      `yaml:"endpoints"`
+	DialTimeout time.Duration `yaml:"dial_timeout"` // 默认 5 秒
+	Username    string        `yaml:"username"`
+	Password    string        `yaml:"password"`
+	Prefix      string        `yaml:"prefix"`    // 注册的 key 前缀，默认 /kitex/services
+	LeaseTTL    time.Duration `yaml:"lease_ttl"` // 实例失联多久后被删除，默认 10 秒
+}
+
+// FileConfig 静态文件配置
+type FileConfig struct {
+	Path string `yaml:"path"` // 实例列表文件，修改后自动重新读取
+}
+
+// DirectConfig 直连配置
+type DirectConfig struct {
+	Addresses []string `yaml:"addresses"`
+}
+
+// ServerConfig 服务端注册配置
+type ServerConfig struct {
+	// 注册的地址，为空时使用监听地址；监听 0.0.0.0 时用本机第一个非回环 IP 替换
+	Advertise string            `yaml:"advertise"`
+	Weight    int               `yaml:"weight"` // 默认 10
+	Tags      map[string]string `yaml:"tags"`
+}
+
+// ClientConfig 客户端发现配置
+type ClientConfig struct {
+	LoadBalancer string `yaml:"load_balancer"` // weighted_round_robin / weighted_random，默认轮询
+	// 重新解析实例列表的间隔，默认 5 秒；实例下线后最多经过这么久才会从列表中移除
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// Load 读取配置文件
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read discovery config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse discovery config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid discovery config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.Type == "" {
+		c.Type = TypeDirect
+	}
+	switch c.Type {
+	case TypeEtcd:
+		if len(c.Etcd.Endpoints) == 0 {
+			return fmt.Errorf("etcd.endpoints is required")
+		}
+		if c.Etcd.DialTimeout <= 0 {
+			c.Etcd.DialTimeout = 5 * time.Second
+		}
+		if c.Etcd.Prefix == "" {
+			c.Etcd.Prefix = DefaultPrefix
+		}
+		if c.Etcd.LeaseTTL <= 0 {
+			c.Etcd.LeaseTTL = 10 * time.Second
+		}
+		if c.Etcd.LeaseTTL < time.Second {
+			return fmt.Errorf("etcd.lease_ttl must be at least 1s")
+		}
+	case TypeFile:
+		if c.File.Path == "" {
+			return fmt.Errorf("file.path is required")
+		}
+	case TypeDirect:
+		if len(c.Direct.Addresses) == 0 {
+			return fmt.Errorf("direct.addresses is required")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", c.Type)
+	}
+
+	if c.Server.Weight < 0 {
+		return fmt.Errorf("server.weight must not be negative")
+	}
+	switch c.Client.LoadBalancer {
+	case "":
+		c.Client.LoadBalancer = WeightedRoundRobin
+	case WeightedRoundRobin, WeightedRandom:
+	default:
+		return fmt.Errorf("unknown client.load_balancer %q", c.Client.LoadBalancer)
+	}
+	if c.Client.RefreshInterval <= 0 {
+		c.Client.RefreshInterval = 5 * time.Second
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{Type: TypeEtcd, Etcd: EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}}}
+	if err := cfg.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Etcd.DialTimeout != 5*time.Second || cfg.Etcd.Prefix != DefaultPrefix || cfg.Etcd.LeaseTTL != 10*time.Second {
+		t.Errorf("etcd defaults = %+v", cfg.Etcd)
+	}
+	if cfg.Client.LoadBalancer != WeightedRoundRobin || cfg.Client.RefreshInterval != 5*time.Second {
+		t.Errorf("client defaults = %+v", cfg.Client)
+	}
+
+	cfg = &Config{Direct: DirectConfig{Addresses: []string{"127.0.0.1:8888"}}}
+	if err := cfg.validate(); err != nil || cfg.Type != TypeDirect {
+		t.Errorf("empty type = %q, %v, want direct", cfg.Type, err)
+	}
+}
+
+func TestConfigValidateErrors(t *testing.T) {
+	etcd := EtcdConfig{Endpoints: []string{"127.0.0.1:2379"}}
+	direct := DirectConfig{Addresses: []string{"127.0.0.1:8888"}}
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"etcd without endpoints", Config{Type: TypeEtcd}, "etcd.endpoints"},
+		{"lease shorter than 1s", Config{Type: TypeEtcd, Etcd: EtcdConfig{Endpoints: etcd.Endpoints, LeaseTTL: 500 * time.Millisecond}}, "lease_ttl"},
+		{"file without path", Config{Type: TypeFile}, "file.path"},
+		{"direct without addresses", Config{Type: TypeDirect}, "direct.addresses"},
+		{"unknown type", Config{Type: "consul"}, "unknown type"},
+		{"negative weight", Config{Direct: direct, Server: ServerConfig{Weight: -1}}, "server.weight"},
+		{"unknown load balancer", Config{Direct: direct, Client: ClientConfig{LoadBalancer: "p2c"}}, "load_balancer"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load("../conf/discovery.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Type != TypeDirect || cfg.Client.RefreshInterval != 2*time.Second {
+		t.Errorf("conf/discovery.yaml = %+v", cfg)
+	}
+
+	if _, err := Load(writeFile(t, "discovery.yaml", "type: file\n")); err == nil {
+		t.Error("file type without path should fail")
+	}
+}
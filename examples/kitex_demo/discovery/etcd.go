@@ -0,0 +1,286 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/discovery"
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// DefaultPrefix 默认的注册 key 前缀，实例的 key 为 <prefix>/<服务名>/<地址>
+const DefaultPrefix = "/kitex/services"
+
+// instanceInfo etcd 中保存的实例信息
+type instanceInfo struct {
+	Network string            `json:"network"`
+	Address string            `json:"address"`
+	Weight  int               `json:"weight"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// serviceKey 返回服务的 key 前缀，以 / 结尾，避免 calc 匹配到 calculator
+func serviceKey(prefix, service string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + service + "/"
+}
+
+// EtcdRegistry 基于 etcd 的注册中心
+// 每个实例写入一个绑定租约的 key 并持续续约，进程崩溃或与 etcd 失联超过 TTL 后 key 被自动删除；
+// 续约中断（如 etcd 重启导致租约丢失）时重新申请租约并注册
+type EtcdRegistry struct {
+	cli    *clientv3.Client
+	prefix string
+	ttl    int64
+
+	mu        sync.Mutex
+	instances map[string]*registration // key -> 注册信息
+}
+
+// registration 一个已注册实例的租约和续约协程
+type registration struct {
+	leaseID clientv3.LeaseID
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewEtcdRegistry 创建 etcd 注册中心，prefix 为空时使用 DefaultPrefix，ttl 不足 1 秒按 1 秒计算
+func NewEtcdRegistry(cli *clientv3.Client, prefix string, ttl time.Duration) *EtcdRegistry {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &EtcdRegistry{
+		cli:       cli,
+		prefix:    prefix,
+		ttl:       seconds,
+		instances: make(map[string]*registration),
+	}
+}
+
+// Register 注册实例，实现 registry.Registry
+func (r *EtcdRegistry) Register(info *registry.Info) error {
+	key, value, err := r.entry(info)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.instances[key]; ok {
+		return fmt.Errorf("instance %s already registered", key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout())
+	leaseID, err := r.put(ctx, key, value)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("register %s: %w", key, err)
+	}
+
+	keepCtx, keepCancel := context.WithCancel(context.Background())
+	reg := &registration{leaseID: leaseID, cancel: keepCancel, done: make(chan struct{})}
+	r.instances[key] = reg
+	go r.keepAlive(keepCtx, reg, key, value)
+	klog.Infof("registered %s (lease %x, ttl %ds)", key, leaseID, r.ttl)
+	return nil
+}
+
+// Deregister 注销实例，撤销租约后 key 立即被删除，实现 registry.Registry
+func (r *EtcdRegistry) Deregister(info *registry.Info) error {
+	key, _, err := r.entry(info)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	reg, ok := r.instances[key]
+	delete(r.instances, key)
+	r.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	reg.cancel()
+	<-reg.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout())
+	defer cancel()
+	// 续约协程可能已经换了租约，以协程结束时的租约为准
+	if _, err := r.cli.Revoke(ctx, reg.leaseID); err != nil {
+		return fmt.Errorf("deregister %s: %w", key, err)
+	}
+	klog.Infof("deregistered %s", key)
+	return nil
+}
+
+// put 申请租约并写入实例信息
+func (r *EtcdRegistry) put(ctx context.Context, key, value string) (clientv3.LeaseID, error) {
+	lease, err := r.cli.Grant(ctx, r.ttl)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.cli.Put(ctx, key, value, clientv3.WithLease(lease.ID)); err != nil {
+		return 0, err
+	}
+	return lease.ID, nil
+}
+
+// keepAlive 持续续约，续约通道关闭说明租约已失效，重新注册直到成功或被注销
+func (r *EtcdRegistry) keepAlive(ctx context.Context, reg *registration, key, value string) {
+	defer close(reg.done)
+
+	for {
+		ch, err := r.cli.KeepAlive(ctx, reg.leaseID)
+		if err == nil {
+			for range ch {
+				// 消费续约响应，否则 etcd 客户端会打印通道已满的警告
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		klog.Warnf("lease of %s lost, registering again", key)
+
+		backoff := 500 * time.Millisecond
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			putCtx, cancel := context.WithTimeout(ctx, r.requestTimeout())
+			leaseID, err := r.put(putCtx, key, value)
+			cancel()
+			if err == nil {
+				reg.leaseID = leaseID
+				klog.Infof("registered %s again (lease %x)", key, leaseID)
+				break
+			}
+			klog.Warnf("register %s again failed: %v", key, err)
+			if backoff < time.Duration(r.ttl)*time.Second {
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// entry 返回实例的 key 和 value
+func (r *EtcdRegistry) entry(info *registry.Info) (string, string, error) {
+	if info == nil || info.ServiceName == "" {
+		return "", "", fmt.Errorf("registry info without service name")
+	}
+	if info.Addr == nil {
+		return "", "", fmt.Errorf("registry info of %s without address", info.ServiceName)
+	}
+
+	advertise := advertiseAddr(info.Addr)
+	addr := advertise.String()
+	value, err := json.Marshal(instanceInfo{
+		Network: advertise.Network(),
+		Address: addr,
+		Weight:  info.Weight,
+		Tags:    info.Tags,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return serviceKey(r.prefix, info.ServiceName) + addr, string(value), nil
+}
+
+// requestTimeout 单次请求 etcd 的超时时间，不超过租约 TTL
+func (r *EtcdRegistry) requestTimeout() time.Duration {
+	return time.Duration(r.ttl) * time.Second
+}
+
+// EtcdResolver 从 etcd 解析服务实例
+type EtcdResolver struct {
+	cli    *clientv3.Client
+	prefix string
+}
+
+// NewEtcdResolver 创建 etcd 解析器，prefix 需要和服务端注册时一致，为空时使用 DefaultPrefix
+func NewEtcdResolver(cli *clientv3.Client, prefix string) *EtcdResolver {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &EtcdResolver{cli: cli, prefix: prefix}
+}
+
+// Target 以服务名作为缓存 key，实现 discovery.Resolver
+func (r *EtcdResolver) Target(_ context.Context, target rpcinfo.EndpointInfo) string {
+	return target.ServiceName()
+}
+
+// Resolve 读取服务下的全部实例，实现 discovery.Resolver
+func (r *EtcdResolver) Resolve(ctx context.Context, desc string) (discovery.Result, error) {
+	resp, err := r.cli.Get(ctx, serviceKey(r.prefix, desc), clientv3.WithPrefix())
+	if err != nil {
+		return discovery.Result{}, fmt.Errorf("resolve %s: %w", desc, err)
+	}
+
+	instances := make([]discovery.Instance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var info instanceInfo
+		if err := json.Unmarshal(kv.Value, &info); err != nil {
+			klog.Warnf("skip invalid instance %s: %v", kv.Key, err)
+			continue
+		}
+		instances = append(instances, newInstance(info.Network, info.Address, info.Weight, info.Tags))
+	}
+	if len(instances) == 0 {
+		return discovery.Result{}, fmt.Errorf("no instance of %s in etcd", desc)
+	}
+	return discovery.Result{Cacheable: true, CacheKey: desc, Instances: instances}, nil
+}
+
+// Diff 实现 discovery.Resolver
+func (r *EtcdResolver) Diff(cacheKey string, prev, next discovery.Result) (discovery.Change, bool) {
+	return discovery.DefaultDiff(cacheKey, prev, next)
+}
+
+// Name 实现 discovery.Resolver
+func (r *EtcdResolver) Name() string {
+	return "etcd"
+}
+
+// newInstance 创建实例，权重为 0 时使用默认权重
+func newInstance(network, address string, weight int, tags map[string]string) discovery.Instance {
+	if network == "" {
+		network = "tcp"
+	}
+	if weight <= 0 {
+		weight = discovery.DefaultWeight
+	}
+	return discovery.NewInstance(network, address, weight, tags)
+}
+
+// advertiseAddr 把未指定 IP 的监听地址（如 :8888、0.0.0.0:8888）替换为本机第一个非回环 IPv4 地址
+func advertiseAddr(addr net.Addr) net.Addr {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok || (tcp.IP != nil && !tcp.IP.IsUnspecified()) {
+		return addr
+	}
+
+	ip := net.IPv4(127, 0, 0, 1)
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, a := range addrs {
+			if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				ip = ipNet.IP
+				break
+			}
+		}
+	}
+	return &net.TCPAddr{IP: ip, Port: tcp.Port}
+}
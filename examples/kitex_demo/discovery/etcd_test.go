@@ -0,0 +1,161 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"example.com/etcd-use/etcdtest"
+	"github.com/cloudwego/kitex/pkg/registry"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const testPrefix = "/test/services"
+
+func testInfo(service string, port int) *registry.Info {
+	return &registry.Info{
+		ServiceName: service,
+		Addr:        &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+		Weight:      5,
+		Tags:        map[string]string{"zone": "a"},
+	}
+}
+
+// waitFor 轮询直到 cond 返回 true，超时后失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// leaseOf 返回 key 绑定的租约，key 不存在时返回 0
+func leaseOf(t *testing.T, cli *clientv3.Client, key string) clientv3.LeaseID {
+	t.Helper()
+	resp, err := cli.Get(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return 0
+	}
+	return clientv3.LeaseID(resp.Kvs[0].Lease)
+}
+
+func TestEtcdRegistry(t *testing.T) {
+	cli := etcdtest.Start(t)
+	reg := NewEtcdRegistry(cli, testPrefix, time.Second)
+	resolver := NewEtcdResolver(cli, testPrefix)
+
+	if _, err := resolver.Resolve(context.Background(), "calc"); err == nil {
+		t.Error("resolve before register should fail")
+	}
+
+	info := testInfo("calc", 8888)
+	if err := reg.Register(info); err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Deregister(info)
+	if err := reg.Register(info); err == nil {
+		t.Error("registering the same instance twice should fail")
+	}
+	// calculator 与 calc 前缀相同，不能被解析到 calc 下
+	other := testInfo("calculator", 9999)
+	if err := reg.Register(other); err != nil {
+		t.Fatal(err)
+	}
+	defer reg.Deregister(other)
+
+	res, err := resolver.Resolve(context.Background(), "calc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addresses(res); !reflect.DeepEqual(got, []string{"127.0.0.1:8888"}) {
+		t.Fatalf("addresses = %v", got)
+	}
+	ins := res.Instances[0]
+	if zone, _ := ins.Tag("zone"); ins.Weight() != 5 || zone != "a" {
+		t.Errorf("instance weight = %d zone = %q, want 5 a", ins.Weight(), zone)
+	}
+
+	// 租约续约后实例不会过期
+	time.Sleep(2500 * time.Millisecond)
+	if res, err := resolver.Resolve(context.Background(), "calc"); err != nil || len(res.Instances) != 1 {
+		t.Fatalf("after ttl = %v, %v, want the instance kept alive", addresses(res), err)
+	}
+}
+
+func TestEtcdRegistryReregister(t *testing.T) {
+	cli := etcdtest.Start(t)
+	reg := NewEtcdRegistry(cli, testPrefix, time.Second)
+	info := testInfo("calc", 8888)
+	key := serviceKey(testPrefix, "calc") + "127.0.0.1:8888"
+
+	if err := reg.Register(info); err != nil {
+		t.Fatal(err)
+	}
+	lease := leaseOf(t, cli, key)
+	if lease == 0 {
+		t.Fatal("instance key not written")
+	}
+
+	// 模拟租约丢失（如 etcd 重启），续约协程重新申请租约并注册
+	if _, err := cli.Revoke(context.Background(), lease); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "re-registration", func() bool {
+		next := leaseOf(t, cli, key)
+		return next != 0 && next != lease
+	})
+
+	// 注销撤销的是新租约，key 立即被删除
+	if err := reg.Deregister(info); err != nil {
+		t.Fatal(err)
+	}
+	if leaseOf(t, cli, key) != 0 {
+		t.Error("key still exists after Deregister")
+	}
+	if err := reg.Deregister(info); err != nil {
+		t.Errorf("second Deregister = %v, want nil", err)
+	}
+
+	// 注销后可以再次注册
+	if err := reg.Register(info); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Deregister(info); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEtcdRegistryInvalidInfo(t *testing.T) {
+	reg := NewEtcdRegistry(nil, "", 0)
+	if reg.prefix != DefaultPrefix || reg.ttl != 1 {
+		t.Errorf("defaults = %q %d, want %q 1", reg.prefix, reg.ttl, DefaultPrefix)
+	}
+	for _, info := range []*registry.Info{nil, {}, {ServiceName: "calc"}} {
+		if err := reg.Register(info); err == nil {
+			t.Errorf("Register(%+v) should fail", info)
+		}
+	}
+}
+
+func TestAdvertiseAddr(t *testing.T) {
+	fixed := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 80}
+	if got := advertiseAddr(fixed); got != fixed {
+		t.Errorf("advertiseAddr(%s) = %s, want unchanged", fixed, got)
+	}
+
+	for _, addr := range []*net.TCPAddr{{Port: 8888}, {IP: net.IPv4zero, Port: 8888}} {
+		got := advertiseAddr(addr).(*net.TCPAddr)
+		if got.Port != 8888 || got.IP.IsUnspecified() || got.IP.To4() == nil {
+			t.Errorf("advertiseAddr(%s) = %s, want a concrete IPv4 address", addr, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/discovery"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"gopkg.in/yaml.v3"
+)
+
+// FileResolver 从静态 YAML 文件解析服务实例，不依赖注册中心，用于离线测试
+// 文件修改后下一次解析时重新读取，可以手动增删实例模拟上下线：
+//
+//	services:
+//	  calculator-service:
+//	    - address: 127.0.0.1:8888
+//	      weight: 10
+//	    - address: 127.0.0.1:8889
+//	      weight: 5
+//	      tags: {zone: b}
+type FileResolver struct {
+	path string
+
+	mu       sync.Mutex
+	modTime  time.Time
+	services map[string][]fileInstance
+}
+
+// servicesFile 实例列表文件的结构
+type servicesFile struct {
+	Services map[string][]fileInstance `yaml:"services"`
+}
+
+// fileInstance 文件中的一个实例
+type fileInstance struct {
+	Network string            `yaml:"network"` // 默认 tcp
+	Address string            `yaml:"address"`
+	Weight  int               `yaml:"weight"` // 默认 10
+	Tags    map[string]string `yaml:"tags"`
+}
+
+// NewFileResolver 创建文件解析器，并检查文件能否正常读取
+func NewFileResolver(path string) (*FileResolver, error) {
+	r := &FileResolver{path: path}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Target 以服务名作为缓存 key，实现 discovery.Resolver
+func (r *FileResolver) Target(_ context.Context, target rpcinfo.EndpointInfo) string {
+	return target.ServiceName()
+}
+
+// Resolve 返回文件中服务的实例，实现 discovery.Resolver
+func (r *FileResolver) Resolve(_ context.Context, desc string) (discovery.Result, error) {
+	services, err := r.load()
+	if err != nil {
+		return discovery.Result{}, err
+	}
+
+	list := services[desc]
+	if len(list) == 0 {
+		return discovery.Result{}, fmt.Errorf("no instance of %s in %s", desc, r.path)
+	}
+	instances := make([]discovery.Instance, 0, len(list))
+	for _, ins := range list {
+		instances = append(instances, newInstance(ins.Network, ins.Address, ins.Weight, ins.Tags))
+	}
+	return discovery.Result{Cacheable: true, CacheKey: desc, Instances: instances}, nil
+}
+
+// Diff 实现 discovery.Resolver
+func (r *FileResolver) Diff(cacheKey string, prev, next discovery.Result) (discovery.Change, bool) {
+	return discovery.DefaultDiff(cacheKey, prev, next)
+}
+
+// Name 实现 discovery.Resolver
+func (r *FileResolver) Name() string {
+	return "file"
+}
+
+// load 文件修改时间变化时重新读取，读取失败时返回错误，保留上一次的结果不变
+func (r *FileResolver) load() (map[string][]fileInstance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stat, err := os.Stat(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("read services file: %w", err)
+	}
+	if r.services != nil && stat.ModTime().Equal(r.modTime) {
+		return r.services, nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("read services file: %w", err)
+	}
+	var file servicesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse services file %s: %w", r.path, err)
+	}
+	for name, list := range file.Services {
+		for i, ins := range list {
+			if ins.Address == "" {
+				return nil, fmt.Errorf("parse services file %s: instance %d of %s without address", r.path, i, name)
+			}
+		}
+	}
+	if file.Services == nil {
+		file.Services = map[string][]fileInstance{}
+	}
+
+	r.modTime = stat.ModTime()
+	r.services = file.Services
+	return r.services, nil
+}
@@ -0,0 +1,157 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/discovery"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+func writeFile(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// rewrite 覆盖文件并推后修改时间，保证 FileResolver 能发现变化
+func rewrite(t *testing.T, path, text string) {
+	t.Helper()
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	next := stat.ModTime().Add(time.Second)
+	if err := os.Chtimes(path, next, next); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addresses 返回结果中的实例地址，已排序
+func addresses(res discovery.Result) []string {
+	addrs := make([]string, 0, len(res.Instances))
+	for _, ins := range res.Instances {
+		addrs = append(addrs, ins.Address().String())
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+const servicesYAML = `
+services:
+  calculator-service:
+    - address: 127.0.0.1:8888
+      weight: 10
+    - address: 127.0.0.1:8889
+      tags: {zone: b}
+`
+
+func TestFileResolver(t *testing.T) {
+	path := writeFile(t, "services.yaml", servicesYAML)
+	r, err := NewFileResolver(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := r.Target(context.Background(), rpcinfo.NewEndpointInfo("calculator-service", "", nil, nil))
+	if target != "calculator-service" {
+		t.Errorf("Target = %q", target)
+	}
+
+	res, err := r.Resolve(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := addresses(res), []string{"127.0.0.1:8888", "127.0.0.1:8889"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("addresses = %v, want %v", got, want)
+	}
+	if !res.Cacheable || res.CacheKey != target {
+		t.Errorf("result cache = %v %q", res.Cacheable, res.CacheKey)
+	}
+	for _, ins := range res.Instances {
+		if ins.Address().Network() != "tcp" {
+			t.Errorf("%s network = %s, want tcp", ins.Address(), ins.Address().Network())
+		}
+		if ins.Address().String() == "127.0.0.1:8889" {
+			// 未配置权重时使用默认权重
+			if ins.Weight() != discovery.DefaultWeight {
+				t.Errorf("default weight = %d, want %d", ins.Weight(), discovery.DefaultWeight)
+			}
+			if zone, _ := ins.Tag("zone"); zone != "b" {
+				t.Errorf("zone tag = %q, want b", zone)
+			}
+		}
+	}
+
+	if _, err := r.Resolve(context.Background(), "unknown-service"); err == nil {
+		t.Error("unknown service should fail to resolve")
+	}
+}
+
+func TestFileResolverReload(t *testing.T) {
+	path := writeFile(t, "services.yaml", servicesYAML)
+	r, err := NewFileResolver(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 下线一个实例
+	rewrite(t, path, "services:\n  calculator-service:\n    - address: 127.0.0.1:8889\n")
+	res, err := r.Resolve(context.Background(), "calculator-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addresses(res); !reflect.DeepEqual(got, []string{"127.0.0.1:8889"}) {
+		t.Fatalf("addresses after rewrite = %v", got)
+	}
+
+	// 文件内容非法时返回错误，修复后恢复
+	rewrite(t, path, "services:\n  calculator-service:\n    - weight: 5\n")
+	if _, err := r.Resolve(context.Background(), "calculator-service"); err == nil {
+		t.Fatal("instance without address should fail")
+	}
+	rewrite(t, path, "services: [")
+	if _, err := r.Resolve(context.Background(), "calculator-service"); err == nil {
+		t.Fatal("invalid yaml should fail")
+	}
+	rewrite(t, path, servicesYAML)
+	if res, err := r.Resolve(context.Background(), "calculator-service"); err != nil || len(res.Instances) != 2 {
+		t.Fatalf("after fix = %v, %v", addresses(res), err)
+	}
+
+	// 文件被删除
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Resolve(context.Background(), "calculator-service"); err == nil {
+		t.Error("missing file should fail")
+	}
+}
+
+func TestNewFileResolverErrors(t *testing.T) {
+	if _, err := NewFileResolver(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing file should fail")
+	}
+	if _, err := NewFileResolver(writeFile(t, "services.yaml", "services: [")); err == nil {
+		t.Error("invalid yaml should fail")
+	}
+	// 没有任何服务的文件是合法的
+	r, err := NewFileResolver(writeFile(t, "services.yaml", "# empty\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Resolve(context.Background(), "calculator-service"); err == nil {
+		t.Error("empty file should have no instances")
+	}
+}
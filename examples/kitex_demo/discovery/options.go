@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/loadbalance"
+	"github.com/cloudwego/kitex/pkg/loadbalance/lbcache"
+	"github.com/cloudwego/kitex/pkg/registry"
+	"github.com/cloudwego/kitex/server"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Registration 服务端注册，停机时可以先调用 Deregister 下线，再排空进行中的请求
+type Registration struct {
+	registry registry.Registry
+	info     *registry.Info
+}
+
+// NewRegistration 根据配置创建服务端注册
+// etcd 模式下服务启动后自动注册、Stop 时注销；file 和 direct 模式的实例列表是静态的，不需要注册
+func NewRegistration(cfg *Config) (*Registration, error) {
+	info := &registry.Info{Weight: cfg.Server.Weight, Tags: cfg.Server.Tags}
+	if cfg.Server.Advertise != "" {
+		addr, err := net.ResolveTCPAddr("tcp", cfg.Server.Advertise)
+		if err != nil {
+			return nil, fmt.Errorf("resolve advertise address: %w", err)
+		}
+		info.Addr = addr
+		info.SkipListenAddr = true
+	}
+
+	reg := &Registration{registry: registry.NoopRegistry, info: info}
+	if cfg.Type == TypeEtcd {
+		cli, err := newEtcdClient(cfg.Etcd)
+		if err != nil {
+			return nil, err
+		}
+		reg.registry = NewEtcdRegistry(cli, cfg.Etcd.Prefix, cfg.Etcd.LeaseTTL)
+	}
+	return reg, nil
+}
+
+// Options 返回服务端注册选项，Kitex 在服务启动后补全 info 中的服务名和地址
+func (r *Registration) Options() []server.Option {
+	return []server.Option{server.WithRegistry(r.registry), server.WithRegistryInfo(r.info)}
+}
+
+// Deregister 提前注销，Kitex Stop 时再次注销不会报错
+func (r *Registration) Deregister() error {
+	if r.info.ServiceName == "" || r.info.Addr == nil {
+		return nil // 还没有注册
+	}
+	return r.registry.Deregister(r.info)
+}
+
+// ClientOptions 根据配置返回客户端发现和负载均衡选项
+// 实例下线后在下一次刷新前仍可能被选中，配合 middleware 的 retry.connection_error 换实例重试
+func ClientOptions(cfg *Config) ([]client.Option, error) {
+	if cfg.Type == TypeDirect {
+		return []client.Option{client.WithHostPorts(cfg.Direct.Addresses...)}, nil
+	}
+
+	var opts []client.Option
+	switch cfg.Type {
+	case TypeEtcd:
+		cli, err := newEtcdClient(cfg.Etcd)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithResolver(NewEtcdResolver(cli, cfg.Etcd.Prefix)))
+	case TypeFile:
+		r, err := NewFileResolver(cfg.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithResolver(r))
+	}
+
+	var lb loadbalance.Loadbalancer
+	switch cfg.Client.LoadBalancer {
+	case WeightedRandom:
+		lb = loadbalance.NewWeightedRandomBalancer()
+	default:
+		lb = loadbalance.NewWeightedRoundRobinBalancer()
+	}
+	opts = append(opts, client.WithLoadBalancer(lb, &lbcache.Options{RefreshInterval: cfg.Client.RefreshInterval}))
+	return opts, nil
+}
+
+// newEtcdClient 创建 etcd 客户端，客户端的生命周期和进程一致
+func newEtcdClient(cfg EtcdConfig) (*clientv3.Client, error) {
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: cfg.DialTimeout,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connect etcd: %w", err)
+	}
+	return cli, nil
+}
@@ -0,0 +1,146 @@
+// Package health 标准 gRPC 健康检查服务（grpc.health.v1.Health），和业务服务注册在同一个 Kitex 服务器上，
+// 停机时先把状态置为 NOT_SERVING，让探测方在实例真正停止前摘除流量
+package health
+
+import (
+	"context"
+	"sync"
+
+	"example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+
+	"github.com/cloudwego/kitex/pkg/remote/trans/nphttp2/codes"
+	"github.com/cloudwego/kitex/pkg/remote/trans/nphttp2/status"
+)
+
+// 服务状态
+const (
+	Serving    = grpc_health_v1.HealthCheckResponse_SERVING
+	NotServing = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+)
+
+// Server 实现 grpc_health_v1.Health
+// 服务名为空表示整个服务器，NewServer 后默认为 SERVING
+type Server struct {
+	mu        sync.Mutex
+	shutdown  bool
+	done      chan struct{}
+	closeOnce sync.Once
+	statuses  map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+	watchers  map[string]map[chan grpc_health_v1.HealthCheckResponse_ServingStatus]struct{}
+}
+
+// NewServer 创建健康检查服务
+func NewServer() *Server {
+	return &Server{
+		done:     make(chan struct{}),
+		statuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{"": Serving},
+		watchers: make(map[string]map[chan grpc_health_v1.HealthCheckResponse_ServingStatus]struct{}),
+	}
+}
+
+// Check 返回服务的当前状态，未登记的服务返回 NOT_FOUND
+func (s *Server) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.statuses[req.Service]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch 先发送当前状态，之后每次变化时发送，直到客户端取消或调用 Close；未登记的服务发送 SERVICE_UNKNOWN
+func (s *Server) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	select {
+	case <-s.done:
+		return status.Errorf(codes.Unavailable, "server is shutting down")
+	default:
+	}
+
+	// 只保留最新状态，慢的客户端会跳过中间状态
+	update := make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 1)
+
+	s.mu.Lock()
+	if st, ok := s.statuses[req.Service]; ok {
+		update <- st
+	} else {
+		update <- grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	if s.watchers[req.Service] == nil {
+		s.watchers[req.Service] = make(map[chan grpc_health_v1.HealthCheckResponse_ServingStatus]struct{})
+	}
+	s.watchers[req.Service][update] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers[req.Service], update)
+		s.mu.Unlock()
+	}()
+
+	var last grpc_health_v1.HealthCheckResponse_ServingStatus = -1
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.Errorf(codes.Canceled, "watch canceled")
+		case <-s.done:
+			return nil
+		case st := <-update:
+			if st == last {
+				continue
+			}
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+	}
+}
+
+// SetServingStatus 设置服务的状态并通知订阅者，Shutdown 之后的设置被忽略
+func (s *Server) SetServingStatus(service string, st grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return
+	}
+	s.setLocked(service, st)
+}
+
+// Shutdown 把所有服务置为 NOT_SERVING，之后不再接受状态变更，停机开始时调用
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = true
+	for service := range s.statuses {
+		s.setLocked(service, NotServing)
+	}
+}
+
+// Resume 把所有服务置为 SERVING，并重新接受状态变更
+func (s *Server) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = false
+	for service := range s.statuses {
+		s.setLocked(service, Serving)
+	}
+}
+
+// Close 结束所有 Watch 订阅，否则长连接的订阅会让优雅停机一直等到超时
+// 在 Shutdown 之后、停止服务器之前调用，订阅方已经收到 NOT_SERVING
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+func (s *Server) setLocked(service string, st grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.statuses[service] = st
+	for update := range s.watchers[service] {
+		// 丢弃未被读取的旧状态，换成最新状态
+		select {
+		case <-update:
+		default:
+		}
+		update <- st
+	}
+}
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+
+	"github.com/cloudwego/kitex/pkg/remote/trans/nphttp2/codes"
+	"github.com/cloudwego/kitex/pkg/remote/trans/nphttp2/status"
+	"github.com/cloudwego/kitex/pkg/streaming"
+)
+
+// watchStream 记录 Watch 发送的状态，只实现 Context 和 Send
+type watchStream struct {
+	streaming.Stream
+	ctx  context.Context
+	sent chan grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+func newWatchStream(ctx context.Context) *watchStream {
+	return &watchStream{ctx: ctx, sent: make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 16)}
+}
+
+func (s *watchStream) Context() context.Context { return s.ctx }
+
+func (s *watchStream) Send(resp *grpc_health_v1.HealthCheckResponse) error {
+	s.sent <- resp.Status
+	return nil
+}
+
+// next 等待下一次发送的状态
+func (s *watchStream) next(t *testing.T) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	select {
+	case st := <-s.sent:
+		return st
+	case <-time.After(time.Second):
+		t.Fatal("no status sent")
+		return 0
+	}
+}
+
+func check(t *testing.T, s *Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) error = %v", service, err)
+	}
+	return resp.Status
+}
+
+func statusCode(err error) codes.Code {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+	return codes.Unknown
+}
+
+func TestCheck(t *testing.T) {
+	s := NewServer()
+	if got := check(t, s, ""); got != Serving {
+		t.Errorf("server status = %v, want SERVING", got)
+	}
+
+	_, err := s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "api.Calculator"})
+	if statusCode(err) != codes.NotFound {
+		t.Errorf("unknown service error = %v, want NotFound", err)
+	}
+
+	s.SetServingStatus("api.Calculator", NotServing)
+	if got := check(t, s, "api.Calculator"); got != NotServing {
+		t.Errorf("service status = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestShutdownResume(t *testing.T) {
+	s := NewServer()
+	s.SetServingStatus("api.Calculator", Serving)
+
+	s.Shutdown()
+	for _, service := range []string{"", "api.Calculator"} {
+		if got := check(t, s, service); got != NotServing {
+			t.Errorf("after Shutdown %q = %v, want NOT_SERVING", service, got)
+		}
+	}
+	// 停机期间的状态变更被忽略
+	s.SetServingStatus("api.Calculator", Serving)
+	if got := check(t, s, "api.Calculator"); got != NotServing {
+		t.Errorf("SetServingStatus after Shutdown = %v, want NOT_SERVING", got)
+	}
+
+	s.Resume()
+	for _, service := range []string{"", "api.Calculator"} {
+		if got := check(t, s, service); got != Serving {
+			t.Errorf("after Resume %q = %v, want SERVING", service, got)
+		}
+	}
+	s.SetServingStatus("api.Calculator", NotServing)
+	if got := check(t, s, "api.Calculator"); got != NotServing {
+		t.Errorf("SetServingStatus after Resume = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := NewServer()
+	stream := newWatchStream(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Watch(&grpc_health_v1.HealthCheckRequest{}, stream) }()
+
+	if got := stream.next(t); got != Serving {
+		t.Fatalf("initial status = %v, want SERVING", got)
+	}
+	s.Shutdown()
+	if got := stream.next(t); got != NotServing {
+		t.Fatalf("status after Shutdown = %v, want NOT_SERVING", got)
+	}
+	s.Resume()
+	if got := stream.next(t); got != Serving {
+		t.Fatalf("status after Resume = %v, want SERVING", got)
+	}
+	// 状态没有变化时不重复发送
+	s.SetServingStatus("", Serving)
+	select {
+	case st := <-stream.sent:
+		t.Fatalf("unchanged status sent again: %v", st)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch after Close = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after Close")
+	}
+
+	// Close 之后的订阅直接返回 Unavailable
+	err := s.Watch(&grpc_health_v1.HealthCheckRequest{}, newWatchStream(context.Background()))
+	if statusCode(err) != codes.Unavailable {
+		t.Errorf("Watch after Close = %v, want Unavailable", err)
+	}
+}
+
+func TestWatchUnknownAndCancel(t *testing.T) {
+	s := NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newWatchStream(ctx)
+	done := make(chan error, 1)
+	go func() { done <- s.Watch(&grpc_health_v1.HealthCheckRequest{Service: "api.Calculator"}, stream) }()
+
+	if got := stream.next(t); got != grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Fatalf("initial status = %v, want SERVICE_UNKNOWN", got)
+	}
+	// 服务登记后订阅方收到新状态
+	s.SetServingStatus("api.Calculator", Serving)
+	if got := stream.next(t); got != Serving {
+		t.Fatalf("status after register = %v, want SERVING", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if statusCode(err) != codes.Canceled {
+			t.Fatalf("Watch after cancel = %v, want Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
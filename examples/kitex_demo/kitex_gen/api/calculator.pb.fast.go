@@ -82,6 +82,76 @@ func (x *CalcResponse) fastReadField2(buf []byte, _type int8) (offset int, err e
 	return offset, err
 }
 
+func (x *EvalRequest) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_EvalRequest[number], err)
+}
+
+func (x *EvalRequest) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Expression, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EvalStep) FastRead(buf []byte, _type int8, number int32) (offset int, err error) {
+	switch number {
+	case 1:
+		offset, err = x.fastReadField1(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 2:
+		offset, err = x.fastReadField2(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	case 3:
+		offset, err = x.fastReadField3(buf, _type)
+		if err != nil {
+			goto ReadFieldError
+		}
+	default:
+		offset, err = fastpb.Skip(buf, _type, number)
+		if err != nil {
+			goto SkipFieldError
+		}
+	}
+	return offset, nil
+SkipFieldError:
+	return offset, fmt.Errorf("%T cannot parse invalid wire-format data, error: %s", x, err)
+ReadFieldError:
+	return offset, fmt.Errorf("%T read field %d '%s' error: %s", x, number, fieldIDToName_EvalStep[number], err)
+}
+
+func (x *EvalStep) fastReadField1(buf []byte, _type int8) (offset int, err error) {
+	x.Operation, offset, err = fastpb.ReadString(buf, _type)
+	return offset, err
+}
+
+func (x *EvalStep) fastReadField2(buf []byte, _type int8) (offset int, err error) {
+	x.Result, offset, err = fastpb.ReadInt64(buf, _type)
+	return offset, err
+}
+
+func (x *EvalStep) fastReadField3(buf []byte, _type int8) (offset int, err error) {
+	x.Done, offset, err = fastpb.ReadBool(buf, _type)
+	return offset, err
+}
+
 func (x *CalcRequest) FastWrite(buf []byte) (offset int) {
 	if x == nil {
 		return offset
@@ -132,6 +202,56 @@ func (x *CalcResponse) fastWriteField2(buf []byte) (offset int) {
 	return offset
 }
 
+func (x *EvalRequest) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	return offset
+}
+
+func (x *EvalRequest) fastWriteField1(buf []byte) (offset int) {
+	if x.Expression == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetExpression())
+	return offset
+}
+
+func (x *EvalStep) FastWrite(buf []byte) (offset int) {
+	if x == nil {
+		return offset
+	}
+	offset += x.fastWriteField1(buf[offset:])
+	offset += x.fastWriteField2(buf[offset:])
+	offset += x.fastWriteField3(buf[offset:])
+	return offset
+}
+
+func (x *EvalStep) fastWriteField1(buf []byte) (offset int) {
+	if x.Operation == "" {
+		return offset
+	}
+	offset += fastpb.WriteString(buf[offset:], 1, x.GetOperation())
+	return offset
+}
+
+func (x *EvalStep) fastWriteField2(buf []byte) (offset int) {
+	if x.Result == 0 {
+		return offset
+	}
+	offset += fastpb.WriteInt64(buf[offset:], 2, x.GetResult())
+	return offset
+}
+
+func (x *EvalStep) fastWriteField3(buf []byte) (offset int) {
+	if !x.Done {
+		return offset
+	}
+	offset += fastpb.WriteBool(buf[offset:], 3, x.GetDone())
+	return offset
+}
+
 func (x *CalcRequest) Size() (n int) {
 	if x == nil {
 		return n
@@ -182,6 +302,56 @@ func (x *CalcResponse) sizeField2() (n int) {
 	return n
 }
 
+func (x *EvalRequest) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	return n
+}
+
+func (x *EvalRequest) sizeField1() (n int) {
+	if x.Expression == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetExpression())
+	return n
+}
+
+func (x *EvalStep) Size() (n int) {
+	if x == nil {
+		return n
+	}
+	n += x.sizeField1()
+	n += x.sizeField2()
+	n += x.sizeField3()
+	return n
+}
+
+func (x *EvalStep) sizeField1() (n int) {
+	if x.Operation == "" {
+		return n
+	}
+	n += fastpb.SizeString(1, x.GetOperation())
+	return n
+}
+
+func (x *EvalStep) sizeField2() (n int) {
+	if x.Result == 0 {
+		return n
+	}
+	n += fastpb.SizeInt64(2, x.GetResult())
+	return n
+}
+
+func (x *EvalStep) sizeField3() (n int) {
+	if !x.Done {
+		return n
+	}
+	n += fastpb.SizeBool(3, x.GetDone())
+	return n
+}
+
 var fieldIDToName_CalcRequest = map[int32]string{
 	1: "A",
 	2: "B",
@@ -191,3 +361,13 @@ var fieldIDToName_CalcResponse = map[int32]string{
 	1: "Result",
 	2: "Error",
 }
+
+var fieldIDToName_EvalRequest = map[int32]string{
+	1: "Expression",
+}
+
+var fieldIDToName_EvalStep = map[int32]string{
+	1: "Operation",
+	2: "Result",
+	3: "Done",
+}
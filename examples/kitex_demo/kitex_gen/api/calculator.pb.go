@@ -8,6 +8,7 @@ package api
 
 import (
 	context "context"
+	streaming "github.com/cloudwego/kitex/pkg/streaming"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,6 +22,62 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 业务错误码
+type ErrorCode int32
+
+const (
+	ErrorCode_OK                 ErrorCode = 0
+	ErrorCode_DIVIDE_BY_ZERO     ErrorCode = 1001 // 除数或模数为 0
+	ErrorCode_OVERFLOW           ErrorCode = 1002 // 结果超出 int64 范围
+	ErrorCode_NEGATIVE_EXPONENT  ErrorCode = 1003 // 整数乘方不支持负指数
+	ErrorCode_INVALID_EXPRESSION ErrorCode = 1004 // 表达式语法错误
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0:    "OK",
+		1001: "DIVIDE_BY_ZERO",
+		1002: "OVERFLOW",
+		1003: "NEGATIVE_EXPONENT",
+		1004: "INVALID_EXPRESSION",
+	}
+	ErrorCode_value = map[string]int32{
+		"OK":                 0,
+		"DIVIDE_BY_ZERO":     1001,
+		"OVERFLOW":           1002,
+		"NEGATIVE_EXPONENT":  1003,
+		"INVALID_EXPRESSION": 1004,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_calculator_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_api_calculator_proto_enumTypes[0]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_api_calculator_proto_rawDescGZIP(), []int{0}
+}
+
 // 计算请求
 type CalcRequest struct {
 	state         protoimpl.MessageState
@@ -83,8 +140,9 @@ type CalcResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result int64  `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
-	Error  string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Result int64 `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Deprecated: Marked as deprecated in api/calculator.proto.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // 已废弃，错误通过业务错误返回
 }
 
 func (x *CalcResponse) Reset() {
@@ -126,6 +184,7 @@ func (x *CalcResponse) GetResult() int64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/calculator.proto.
 func (x *CalcResponse) GetError() string {
 	if x != nil {
 		return x.Error
@@ -133,6 +192,118 @@ func (x *CalcResponse) GetError() string {
 	return ""
 }
 
+// 表达式求值请求，支持 + - * / % ^ 和括号
+type EvalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+}
+
+func (x *EvalRequest) Reset() {
+	*x = EvalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_calculator_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvalRequest) ProtoMessage() {}
+
+func (x *EvalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_calculator_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvalRequest.ProtoReflect.Descriptor instead.
+func (*EvalRequest) Descriptor() ([]byte, []int) {
+	return file_api_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EvalRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+// 表达式求值的一步
+type EvalStep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"` // 本步计算，如 "3 * 4"
+	Result    int64  `protobuf:"varint,2,opt,name=result,proto3" json:"result,omitempty"`      // 本步结果
+	Done      bool   `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`          // 是否为最终结果
+}
+
+func (x *EvalStep) Reset() {
+	*x = EvalStep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_calculator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EvalStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvalStep) ProtoMessage() {}
+
+func (x *EvalStep) ProtoReflect() protoreflect.Message {
+	mi := &file_api_calculator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvalStep.ProtoReflect.Descriptor instead.
+func (*EvalStep) Descriptor() ([]byte, []int) {
+	return file_api_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EvalStep) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *EvalStep) GetResult() int64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+func (x *EvalStep) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
 var File_api_calculator_proto protoreflect.FileDescriptor
 
 var file_api_calculator_proto_rawDesc = []byte{
@@ -140,21 +311,52 @@ var file_api_calculator_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x22, 0x29, 0x0a, 0x0b, 0x43,
 	0x61, 0x6c, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x61, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x01, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x62, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x01, 0x62, 0x22, 0x3c, 0x0a, 0x0c, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65,
+	0x01, 0x28, 0x03, 0x52, 0x01, 0x62, 0x22, 0x40, 0x0a, 0x0c, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65,
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x32, 0x6d, 0x0a, 0x0a, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74,
-	0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x03, 0x41, 0x64, 0x64, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70,
-	0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x31, 0x0a, 0x08, 0x53, 0x75, 0x62, 0x74, 0x72, 0x61, 0x63, 0x74, 0x12, 0x10, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x42, 0x2b, 0x5a, 0x29, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2f, 0x65,
-	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x2f, 0x6b, 0x69, 0x74, 0x65, 0x78, 0x5f, 0x64, 0x65,
-	0x6d, 0x6f, 0x2f, 0x6b, 0x69, 0x74, 0x65, 0x78, 0x5f, 0x67, 0x65, 0x6e, 0x2f, 0x61, 0x70, 0x69,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x02, 0x18,
+	0x01, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x2d, 0x0a, 0x0b, 0x45, 0x76, 0x61, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x08, 0x45, 0x76, 0x61, 0x6c, 0x53,
+	0x74, 0x65, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x2a, 0x68, 0x0a,
+	0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b,
+	0x10, 0x00, 0x12, 0x13, 0x0a, 0x0e, 0x44, 0x49, 0x56, 0x49, 0x44, 0x45, 0x5f, 0x42, 0x59, 0x5f,
+	0x5a, 0x45, 0x52, 0x4f, 0x10, 0xe9, 0x07, 0x12, 0x0d, 0x0a, 0x08, 0x4f, 0x56, 0x45, 0x52, 0x46,
+	0x4c, 0x4f, 0x57, 0x10, 0xea, 0x07, 0x12, 0x16, 0x0a, 0x11, 0x4e, 0x45, 0x47, 0x41, 0x54, 0x49,
+	0x56, 0x45, 0x5f, 0x45, 0x58, 0x50, 0x4f, 0x4e, 0x45, 0x4e, 0x54, 0x10, 0xeb, 0x07, 0x12, 0x17,
+	0x0a, 0x12, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x45, 0x58, 0x50, 0x52, 0x45, 0x53,
+	0x53, 0x49, 0x4f, 0x4e, 0x10, 0xec, 0x07, 0x32, 0xe3, 0x02, 0x0a, 0x0a, 0x43, 0x61, 0x6c, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2c, 0x0a, 0x03, 0x41, 0x64, 0x64, 0x12, 0x10, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x08, 0x53, 0x75, 0x62, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x08, 0x4d, 0x75, 0x6c, 0x74, 0x69,
+	0x70, 0x6c, 0x79, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x06, 0x44, 0x69,
+	0x76, 0x69, 0x64, 0x65, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c,
+	0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x06, 0x4d,
+	0x6f, 0x64, 0x75, 0x6c, 0x6f, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61,
+	0x6c, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x05,
+	0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61, 0x6c, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x61,
+	0x6c, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2f, 0x0a, 0x08,
+	0x45, 0x76, 0x61, 0x6c, 0x75, 0x61, 0x74, 0x65, 0x12, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x45,
+	0x76, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x45, 0x76, 0x61, 0x6c, 0x53, 0x74, 0x65, 0x70, 0x22, 0x00, 0x30, 0x01, 0x42, 0x2b, 0x5a,
+	0x29, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2f, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x73, 0x2f, 0x6b, 0x69, 0x74, 0x65, 0x78, 0x5f, 0x64, 0x65, 0x6d, 0x6f, 0x2f, 0x6b, 0x69, 0x74,
+	0x65, 0x78, 0x5f, 0x67, 0x65, 0x6e, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -169,18 +371,32 @@ func file_api_calculator_proto_rawDescGZIP() []byte {
 	return file_api_calculator_proto_rawDescData
 }
 
-var file_api_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_calculator_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_api_calculator_proto_goTypes = []interface{}{
-	(*CalcRequest)(nil),  // 0: api.CalcRequest
-	(*CalcResponse)(nil), // 1: api.CalcResponse
+	(ErrorCode)(0),       // 0: api.ErrorCode
+	(*CalcRequest)(nil),  // 1: api.CalcRequest
+	(*CalcResponse)(nil), // 2: api.CalcResponse
+	(*EvalRequest)(nil),  // 3: api.EvalRequest
+	(*EvalStep)(nil),     // 4: api.EvalStep
 }
 var file_api_calculator_proto_depIdxs = []int32{
-	0, // 0: api.Calculator.Add:input_type -> api.CalcRequest
-	0, // 1: api.Calculator.Subtract:input_type -> api.CalcRequest
-	1, // 2: api.Calculator.Add:output_type -> api.CalcResponse
-	1, // 3: api.Calculator.Subtract:output_type -> api.CalcResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
+	1, // 0: api.Calculator.Add:input_type -> api.CalcRequest
+	1, // 1: api.Calculator.Subtract:input_type -> api.CalcRequest
+	1, // 2: api.Calculator.Multiply:input_type -> api.CalcRequest
+	1, // 3: api.Calculator.Divide:input_type -> api.CalcRequest
+	1, // 4: api.Calculator.Modulo:input_type -> api.CalcRequest
+	1, // 5: api.Calculator.Power:input_type -> api.CalcRequest
+	3, // 6: api.Calculator.Evaluate:input_type -> api.EvalRequest
+	2, // 7: api.Calculator.Add:output_type -> api.CalcResponse
+	2, // 8: api.Calculator.Subtract:output_type -> api.CalcResponse
+	2, // 9: api.Calculator.Multiply:output_type -> api.CalcResponse
+	2, // 10: api.Calculator.Divide:output_type -> api.CalcResponse
+	2, // 11: api.Calculator.Modulo:output_type -> api.CalcResponse
+	2, // 12: api.Calculator.Power:output_type -> api.CalcResponse
+	4, // 13: api.Calculator.Evaluate:output_type -> api.EvalStep
+	7, // [7:14] is the sub-list for method output_type
+	0, // [0:7] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -216,19 +432,44 @@ func file_api_calculator_proto_init() {
 				return nil
 			}
 		}
+		file_api_calculator_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_calculator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EvalStep); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_calculator_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_calculator_proto_goTypes,
 		DependencyIndexes: file_api_calculator_proto_depIdxs,
+		EnumInfos:         file_api_calculator_proto_enumTypes,
 		MessageInfos:      file_api_calculator_proto_msgTypes,
 	}.Build()
 	File_api_calculator_proto = out.File
@@ -244,4 +485,14 @@ var _ context.Context
 type Calculator interface {
 	Add(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
 	Subtract(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
+	Multiply(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
+	Divide(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
+	Modulo(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
+	Power(ctx context.Context, req *CalcRequest) (res *CalcResponse, err error)
+	Evaluate(req *EvalRequest, stream Calculator_EvaluateServer) (err error)
+}
+
+type Calculator_EvaluateServer interface {
+	streaming.Stream
+	Send(*EvalStep) error
 }
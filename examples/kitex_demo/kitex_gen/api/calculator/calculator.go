@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	api "example/examples/kitex_demo/kitex_gen/api"
+	"fmt"
 	client "github.com/cloudwego/kitex/client"
 	kitex "github.com/cloudwego/kitex/pkg/serviceinfo"
 	streaming "github.com/cloudwego/kitex/pkg/streaming"
@@ -29,6 +30,41 @@ var serviceMethods = map[string]kitex.MethodInfo{
 		false,
 		kitex.WithStreamingMode(kitex.StreamingUnary),
 	),
+	"Multiply": kitex.NewMethodInfo(
+		multiplyHandler,
+		newMultiplyArgs,
+		newMultiplyResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"Divide": kitex.NewMethodInfo(
+		divideHandler,
+		newDivideArgs,
+		newDivideResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"Modulo": kitex.NewMethodInfo(
+		moduloHandler,
+		newModuloArgs,
+		newModuloResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"Power": kitex.NewMethodInfo(
+		powerHandler,
+		newPowerArgs,
+		newPowerResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"Evaluate": kitex.NewMethodInfo(
+		evaluateHandler,
+		newEvaluateArgs,
+		newEvaluateResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingServer),
+	),
 }
 
 var (
@@ -54,7 +90,7 @@ func serviceInfoForClient() *kitex.ServiceInfo {
 
 // NewServiceInfo creates a new ServiceInfo containing all methods
 func NewServiceInfo() *kitex.ServiceInfo {
-	return newServiceInfo(false, true, true)
+	return newServiceInfo(true, true, true)
 }
 
 // NewServiceInfo creates a new ServiceInfo containing non-streaming methods
@@ -401,32 +437,871 @@ func (p *SubtractResult) GetResult() interface{} {
 	return p.Success
 }
 
-type kClient struct {
-	c client.Client
+func multiplyHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(api.CalcRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(api.Calculator).Multiply(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *MultiplyArgs:
+		success, err := handler.(api.Calculator).Multiply(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*MultiplyResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newMultiplyArgs() interface{} {
+	return &MultiplyArgs{}
 }
 
-func newServiceClient(c client.Client) *kClient {
-	return &kClient{
-		c: c,
+func newMultiplyResult() interface{} {
+	return &MultiplyResult{}
+}
+
+type MultiplyArgs struct {
+	Req *api.CalcRequest
+}
+
+func (p *MultiplyArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(api.CalcRequest)
 	}
+	return p.Req.FastRead(buf, _type, number)
 }
 
-func (p *kClient) Add(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
-	var _args AddArgs
-	_args.Req = Req
-	var _result AddResult
-	if err = p.c.Call(ctx, "Add", &_args, &_result); err != nil {
-		return
+func (p *MultiplyArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
 	}
-	return _result.GetSuccess(), nil
+	return p.Req.FastWrite(buf)
 }
 
-func (p *kClient) Subtract(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
-	var _args SubtractArgs
-	_args.Req = Req
-	var _result SubtractResult
-	if err = p.c.Call(ctx, "Subtract", &_args, &_result); err != nil {
-		return
+func (p *MultiplyArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
 	}
-	return _result.GetSuccess(), nil
+	return p.Req.Size()
+}
+
+func (p *MultiplyArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *MultiplyArgs) Unmarshal(in []byte) error {
+	msg := new(api.CalcRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var MultiplyArgs_Req_DEFAULT *api.CalcRequest
+
+func (p *MultiplyArgs) GetReq() *api.CalcRequest {
+	if !p.IsSetReq() {
+		return MultiplyArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *MultiplyArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *MultiplyArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type MultiplyResult struct {
+	Success *api.CalcResponse
+}
+
+var MultiplyResult_Success_DEFAULT *api.CalcResponse
+
+func (p *MultiplyResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(api.CalcResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *MultiplyResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *MultiplyResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *MultiplyResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *MultiplyResult) Unmarshal(in []byte) error {
+	msg := new(api.CalcResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *MultiplyResult) GetSuccess() *api.CalcResponse {
+	if !p.IsSetSuccess() {
+		return MultiplyResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *MultiplyResult) SetSuccess(x interface{}) {
+	p.Success = x.(*api.CalcResponse)
+}
+
+func (p *MultiplyResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *MultiplyResult) GetResult() interface{} {
+	return p.Success
+}
+
+func divideHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(api.CalcRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(api.Calculator).Divide(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *DivideArgs:
+		success, err := handler.(api.Calculator).Divide(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*DivideResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newDivideArgs() interface{} {
+	return &DivideArgs{}
+}
+
+func newDivideResult() interface{} {
+	return &DivideResult{}
+}
+
+type DivideArgs struct {
+	Req *api.CalcRequest
+}
+
+func (p *DivideArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(api.CalcRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *DivideArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *DivideArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *DivideArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *DivideArgs) Unmarshal(in []byte) error {
+	msg := new(api.CalcRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var DivideArgs_Req_DEFAULT *api.CalcRequest
+
+func (p *DivideArgs) GetReq() *api.CalcRequest {
+	if !p.IsSetReq() {
+		return DivideArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *DivideArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *DivideArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type DivideResult struct {
+	Success *api.CalcResponse
+}
+
+var DivideResult_Success_DEFAULT *api.CalcResponse
+
+func (p *DivideResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(api.CalcResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *DivideResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *DivideResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *DivideResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *DivideResult) Unmarshal(in []byte) error {
+	msg := new(api.CalcResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *DivideResult) GetSuccess() *api.CalcResponse {
+	if !p.IsSetSuccess() {
+		return DivideResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *DivideResult) SetSuccess(x interface{}) {
+	p.Success = x.(*api.CalcResponse)
+}
+
+func (p *DivideResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *DivideResult) GetResult() interface{} {
+	return p.Success
+}
+
+func moduloHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(api.CalcRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(api.Calculator).Modulo(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *ModuloArgs:
+		success, err := handler.(api.Calculator).Modulo(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*ModuloResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newModuloArgs() interface{} {
+	return &ModuloArgs{}
+}
+
+func newModuloResult() interface{} {
+	return &ModuloResult{}
+}
+
+type ModuloArgs struct {
+	Req *api.CalcRequest
+}
+
+func (p *ModuloArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(api.CalcRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *ModuloArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *ModuloArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *ModuloArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *ModuloArgs) Unmarshal(in []byte) error {
+	msg := new(api.CalcRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var ModuloArgs_Req_DEFAULT *api.CalcRequest
+
+func (p *ModuloArgs) GetReq() *api.CalcRequest {
+	if !p.IsSetReq() {
+		return ModuloArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *ModuloArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *ModuloArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type ModuloResult struct {
+	Success *api.CalcResponse
+}
+
+var ModuloResult_Success_DEFAULT *api.CalcResponse
+
+func (p *ModuloResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(api.CalcResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *ModuloResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *ModuloResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *ModuloResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *ModuloResult) Unmarshal(in []byte) error {
+	msg := new(api.CalcResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *ModuloResult) GetSuccess() *api.CalcResponse {
+	if !p.IsSetSuccess() {
+		return ModuloResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *ModuloResult) SetSuccess(x interface{}) {
+	p.Success = x.(*api.CalcResponse)
+}
+
+func (p *ModuloResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *ModuloResult) GetResult() interface{} {
+	return p.Success
+}
+
+func powerHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(api.CalcRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(api.Calculator).Power(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *PowerArgs:
+		success, err := handler.(api.Calculator).Power(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*PowerResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newPowerArgs() interface{} {
+	return &PowerArgs{}
+}
+
+func newPowerResult() interface{} {
+	return &PowerResult{}
+}
+
+type PowerArgs struct {
+	Req *api.CalcRequest
+}
+
+func (p *PowerArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(api.CalcRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *PowerArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *PowerArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *PowerArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *PowerArgs) Unmarshal(in []byte) error {
+	msg := new(api.CalcRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var PowerArgs_Req_DEFAULT *api.CalcRequest
+
+func (p *PowerArgs) GetReq() *api.CalcRequest {
+	if !p.IsSetReq() {
+		return PowerArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *PowerArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *PowerArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type PowerResult struct {
+	Success *api.CalcResponse
+}
+
+var PowerResult_Success_DEFAULT *api.CalcResponse
+
+func (p *PowerResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(api.CalcResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *PowerResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *PowerResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *PowerResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *PowerResult) Unmarshal(in []byte) error {
+	msg := new(api.CalcResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *PowerResult) GetSuccess() *api.CalcResponse {
+	if !p.IsSetSuccess() {
+		return PowerResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *PowerResult) SetSuccess(x interface{}) {
+	p.Success = x.(*api.CalcResponse)
+}
+
+func (p *PowerResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *PowerResult) GetResult() interface{} {
+	return p.Success
+}
+
+func evaluateHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	streamingArgs, ok := arg.(*streaming.Args)
+	if !ok {
+		return errInvalidMessageType
+	}
+	st := streamingArgs.Stream
+	stream := &calculatorEvaluateServer{st}
+	req := new(api.EvalRequest)
+	if err := st.RecvMsg(req); err != nil {
+		return err
+	}
+	return handler.(api.Calculator).Evaluate(req, stream)
+}
+
+type calculatorEvaluateClient struct {
+	streaming.Stream
+}
+
+func (x *calculatorEvaluateClient) DoFinish(err error) {
+	if finisher, ok := x.Stream.(streaming.WithDoFinish); ok {
+		finisher.DoFinish(err)
+	} else {
+		panic(fmt.Sprintf("streaming.WithDoFinish is not implemented by %T", x.Stream))
+	}
+}
+func (x *calculatorEvaluateClient) Recv() (*api.EvalStep, error) {
+	m := new(api.EvalStep)
+	return m, x.Stream.RecvMsg(m)
+}
+
+type calculatorEvaluateServer struct {
+	streaming.Stream
+}
+
+func (x *calculatorEvaluateServer) Send(m *api.EvalStep) error {
+	return x.Stream.SendMsg(m)
+}
+
+func newEvaluateArgs() interface{} {
+	return &EvaluateArgs{}
+}
+
+func newEvaluateResult() interface{} {
+	return &EvaluateResult{}
+}
+
+type EvaluateArgs struct {
+	Req *api.EvalRequest
+}
+
+func (p *EvaluateArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(api.EvalRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *EvaluateArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *EvaluateArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *EvaluateArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *EvaluateArgs) Unmarshal(in []byte) error {
+	msg := new(api.EvalRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var EvaluateArgs_Req_DEFAULT *api.EvalRequest
+
+func (p *EvaluateArgs) GetReq() *api.EvalRequest {
+	if !p.IsSetReq() {
+		return EvaluateArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *EvaluateArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *EvaluateArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type EvaluateResult struct {
+	Success *api.EvalStep
+}
+
+var EvaluateResult_Success_DEFAULT *api.EvalStep
+
+func (p *EvaluateResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(api.EvalStep)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *EvaluateResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *EvaluateResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *EvaluateResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *EvaluateResult) Unmarshal(in []byte) error {
+	msg := new(api.EvalStep)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *EvaluateResult) GetSuccess() *api.EvalStep {
+	if !p.IsSetSuccess() {
+		return EvaluateResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *EvaluateResult) SetSuccess(x interface{}) {
+	p.Success = x.(*api.EvalStep)
+}
+
+func (p *EvaluateResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *EvaluateResult) GetResult() interface{} {
+	return p.Success
+}
+
+type kClient struct {
+	c client.Client
+}
+
+func newServiceClient(c client.Client) *kClient {
+	return &kClient{
+		c: c,
+	}
+}
+
+func (p *kClient) Add(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args AddArgs
+	_args.Req = Req
+	var _result AddResult
+	if err = p.c.Call(ctx, "Add", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Subtract(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args SubtractArgs
+	_args.Req = Req
+	var _result SubtractResult
+	if err = p.c.Call(ctx, "Subtract", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Multiply(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args MultiplyArgs
+	_args.Req = Req
+	var _result MultiplyResult
+	if err = p.c.Call(ctx, "Multiply", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Divide(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args DivideArgs
+	_args.Req = Req
+	var _result DivideResult
+	if err = p.c.Call(ctx, "Divide", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Modulo(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args ModuloArgs
+	_args.Req = Req
+	var _result ModuloResult
+	if err = p.c.Call(ctx, "Modulo", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Power(ctx context.Context, Req *api.CalcRequest) (r *api.CalcResponse, err error) {
+	var _args PowerArgs
+	_args.Req = Req
+	var _result PowerResult
+	if err = p.c.Call(ctx, "Power", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Evaluate(ctx context.Context, req *api.EvalRequest) (Calculator_EvaluateClient, error) {
+	streamClient, ok := p.c.(client.Streaming)
+	if !ok {
+		return nil, fmt.Errorf("client not support streaming")
+	}
+	res := new(streaming.Result)
+	err := streamClient.Stream(ctx, "Evaluate", nil, res)
+	if err != nil {
+		return nil, err
+	}
+	stream := &calculatorEvaluateClient{res.Stream}
+
+	if err := stream.Stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.Stream.Close(); err != nil {
+		return nil, err
+	}
+	return stream, nil
 }
@@ -7,12 +7,31 @@ import (
 	api "example/examples/kitex_demo/kitex_gen/api"
 	client "github.com/cloudwego/kitex/client"
 	callopt "github.com/cloudwego/kitex/client/callopt"
+	streaming "github.com/cloudwego/kitex/pkg/streaming"
+	transport "github.com/cloudwego/kitex/transport"
+	"github.com/cloudwego/kitex/client/streamclient"
+	"github.com/cloudwego/kitex/client/callopt/streamcall"
 )
 
 // Client is designed to provide IDL-compatible methods with call-option parameter for kitex framework.
 type Client interface {
 	Add(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
 	Subtract(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
+	Multiply(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
+	Divide(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
+	Modulo(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
+	Power(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error)
+	Evaluate(ctx context.Context, Req *api.EvalRequest, callOptions ...callopt.Option) (stream Calculator_EvaluateClient, err error)
+}
+
+// StreamClient is designed to provide Interface for Streaming APIs.
+type StreamClient interface {
+	Evaluate(ctx context.Context, Req *api.EvalRequest, callOptions ...streamcall.Option) (stream Calculator_EvaluateClient, err error)
+}
+
+type Calculator_EvaluateClient interface {
+	streaming.Stream
+	Recv() (*api.EvalStep, error)
 }
 
 // NewClient creates a client for the service defined in IDL.
@@ -20,6 +39,8 @@ func NewClient(destService string, opts ...client.Option) (Client, error) {
 	var options []client.Option
 	options = append(options, client.WithDestService(destService))
 
+	options = append(options, client.WithTransportProtocol(transport.GRPC))
+
 	options = append(options, opts...)
 
 	kc, err := client.NewClient(serviceInfo(), options...)
@@ -53,3 +74,63 @@ func (p *kCalculatorClient) Subtract(ctx context.Context, Req *api.CalcRequest,
 	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
 	return p.kClient.Subtract(ctx, Req)
 }
+
+func (p *kCalculatorClient) Multiply(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Multiply(ctx, Req)
+}
+
+func (p *kCalculatorClient) Divide(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Divide(ctx, Req)
+}
+
+func (p *kCalculatorClient) Modulo(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Modulo(ctx, Req)
+}
+
+func (p *kCalculatorClient) Power(ctx context.Context, Req *api.CalcRequest, callOptions ...callopt.Option) (r *api.CalcResponse, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Power(ctx, Req)
+}
+
+func (p *kCalculatorClient) Evaluate(ctx context.Context, Req *api.EvalRequest, callOptions ...callopt.Option) (stream Calculator_EvaluateClient, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, callOptions)
+	return p.kClient.Evaluate(ctx, Req)
+}
+
+// NewStreamClient creates a stream client for the service's streaming APIs defined in IDL.
+func NewStreamClient(destService string, opts ...streamclient.Option) (StreamClient, error) {
+	var options []client.Option
+	options = append(options, client.WithDestService(destService))
+	options = append(options, client.WithTransportProtocol(transport.GRPC))
+	options = append(options, streamclient.GetClientOptions(opts)...)
+
+	kc, err := client.NewClient(serviceInfoForStreamClient(), options...)
+	if err != nil {
+		return nil, err
+	}
+	return &kCalculatorStreamClient{
+		kClient: newServiceClient(kc),
+	}, nil
+}
+
+// MustNewStreamClient creates a stream client for the service's streaming APIs defined in IDL.
+// It panics if any error occurs.
+func MustNewStreamClient(destService string, opts ...streamclient.Option) StreamClient {
+	kc, err := NewStreamClient(destService, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return kc
+}
+
+type kCalculatorStreamClient struct {
+	*kClient
+}
+
+func (p *kCalculatorStreamClient) Evaluate(ctx context.Context, Req *api.EvalRequest, callOptions ...streamcall.Option) (stream Calculator_EvaluateClient, err error) {
+	ctx = client.NewCtxWithCallOptions(ctx, streamcall.GetCallOptions(callOptions))
+	return p.kClient.Evaluate(ctx, Req)
+}
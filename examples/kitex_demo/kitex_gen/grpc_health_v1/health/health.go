@@ -0,0 +1,457 @@
+// Code generated by Kitex v0.12.3. DO NOT EDIT.
+
+package health
+
+import (
+	"context"
+	"errors"
+	grpc_health_v1 "example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+	"fmt"
+	client "github.com/cloudwego/kitex/client"
+	kitex "github.com/cloudwego/kitex/pkg/serviceinfo"
+	streaming "github.com/cloudwego/kitex/pkg/streaming"
+	proto "google.golang.org/protobuf/proto"
+)
+
+var errInvalidMessageType = errors.New("invalid message type for service method handler")
+
+var serviceMethods = map[string]kitex.MethodInfo{
+	"Check": kitex.NewMethodInfo(
+		checkHandler,
+		newCheckArgs,
+		newCheckResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingUnary),
+	),
+	"Watch": kitex.NewMethodInfo(
+		watchHandler,
+		newWatchArgs,
+		newWatchResult,
+		false,
+		kitex.WithStreamingMode(kitex.StreamingServer),
+	),
+}
+
+var (
+	healthServiceInfo                = NewServiceInfo()
+	healthServiceInfoForClient       = NewServiceInfoForClient()
+	healthServiceInfoForStreamClient = NewServiceInfoForStreamClient()
+)
+
+// for server
+func serviceInfo() *kitex.ServiceInfo {
+	return healthServiceInfo
+}
+
+// for stream client
+func serviceInfoForStreamClient() *kitex.ServiceInfo {
+	return healthServiceInfoForStreamClient
+}
+
+// for client
+func serviceInfoForClient() *kitex.ServiceInfo {
+	return healthServiceInfoForClient
+}
+
+// NewServiceInfo creates a new ServiceInfo containing all methods
+func NewServiceInfo() *kitex.ServiceInfo {
+	return newServiceInfo(true, true, true)
+}
+
+// NewServiceInfo creates a new ServiceInfo containing non-streaming methods
+func NewServiceInfoForClient() *kitex.ServiceInfo {
+	return newServiceInfo(false, false, true)
+}
+func NewServiceInfoForStreamClient() *kitex.ServiceInfo {
+	return newServiceInfo(true, true, false)
+}
+
+func newServiceInfo(hasStreaming bool, keepStreamingMethods bool, keepNonStreamingMethods bool) *kitex.ServiceInfo {
+	serviceName := "Health"
+	handlerType := (*grpc_health_v1.Health)(nil)
+	methods := map[string]kitex.MethodInfo{}
+	for name, m := range serviceMethods {
+		if m.IsStreaming() && !keepStreamingMethods {
+			continue
+		}
+		if !m.IsStreaming() && !keepNonStreamingMethods {
+			continue
+		}
+		methods[name] = m
+	}
+	extra := map[string]interface{}{
+		"PackageName": "grpc.health.v1",
+	}
+	if hasStreaming {
+		extra["streaming"] = hasStreaming
+	}
+	svcInfo := &kitex.ServiceInfo{
+		ServiceName:     serviceName,
+		HandlerType:     handlerType,
+		Methods:         methods,
+		PayloadCodec:    kitex.Protobuf,
+		KiteXGenVersion: "v0.12.3",
+		Extra:           extra,
+	}
+	return svcInfo
+}
+
+func checkHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	switch s := arg.(type) {
+	case *streaming.Args:
+		st := s.Stream
+		req := new(grpc_health_v1.HealthCheckRequest)
+		if err := st.RecvMsg(req); err != nil {
+			return err
+		}
+		resp, err := handler.(grpc_health_v1.Health).Check(ctx, req)
+		if err != nil {
+			return err
+		}
+		return st.SendMsg(resp)
+	case *CheckArgs:
+		success, err := handler.(grpc_health_v1.Health).Check(ctx, s.Req)
+		if err != nil {
+			return err
+		}
+		realResult := result.(*CheckResult)
+		realResult.Success = success
+		return nil
+	default:
+		return errInvalidMessageType
+	}
+}
+func newCheckArgs() interface{} {
+	return &CheckArgs{}
+}
+
+func newCheckResult() interface{} {
+	return &CheckResult{}
+}
+
+type CheckArgs struct {
+	Req *grpc_health_v1.HealthCheckRequest
+}
+
+func (p *CheckArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(grpc_health_v1.HealthCheckRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *CheckArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *CheckArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *CheckArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *CheckArgs) Unmarshal(in []byte) error {
+	msg := new(grpc_health_v1.HealthCheckRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var CheckArgs_Req_DEFAULT *grpc_health_v1.HealthCheckRequest
+
+func (p *CheckArgs) GetReq() *grpc_health_v1.HealthCheckRequest {
+	if !p.IsSetReq() {
+		return CheckArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *CheckArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *CheckArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type CheckResult struct {
+	Success *grpc_health_v1.HealthCheckResponse
+}
+
+var CheckResult_Success_DEFAULT *grpc_health_v1.HealthCheckResponse
+
+func (p *CheckResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(grpc_health_v1.HealthCheckResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *CheckResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *CheckResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *CheckResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *CheckResult) Unmarshal(in []byte) error {
+	msg := new(grpc_health_v1.HealthCheckResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *CheckResult) GetSuccess() *grpc_health_v1.HealthCheckResponse {
+	if !p.IsSetSuccess() {
+		return CheckResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *CheckResult) SetSuccess(x interface{}) {
+	p.Success = x.(*grpc_health_v1.HealthCheckResponse)
+}
+
+func (p *CheckResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *CheckResult) GetResult() interface{} {
+	return p.Success
+}
+
+func watchHandler(ctx context.Context, handler interface{}, arg, result interface{}) error {
+	streamingArgs, ok := arg.(*streaming.Args)
+	if !ok {
+		return errInvalidMessageType
+	}
+	st := streamingArgs.Stream
+	stream := &healthWatchServer{st}
+	req := new(grpc_health_v1.HealthCheckRequest)
+	if err := st.RecvMsg(req); err != nil {
+		return err
+	}
+	return handler.(grpc_health_v1.Health).Watch(req, stream)
+}
+
+type healthWatchClient struct {
+	streaming.Stream
+}
+
+func (x *healthWatchClient) DoFinish(err error) {
+	if finisher, ok := x.Stream.(streaming.WithDoFinish); ok {
+		finisher.DoFinish(err)
+	} else {
+		panic(fmt.Sprintf("streaming.WithDoFinish is not implemented by %T", x.Stream))
+	}
+}
+func (x *healthWatchClient) Recv() (*grpc_health_v1.HealthCheckResponse, error) {
+	m := new(grpc_health_v1.HealthCheckResponse)
+	return m, x.Stream.RecvMsg(m)
+}
+
+type healthWatchServer struct {
+	streaming.Stream
+}
+
+func (x *healthWatchServer) Send(m *grpc_health_v1.HealthCheckResponse) error {
+	return x.Stream.SendMsg(m)
+}
+
+func newWatchArgs() interface{} {
+	return &WatchArgs{}
+}
+
+func newWatchResult() interface{} {
+	return &WatchResult{}
+}
+
+type WatchArgs struct {
+	Req *grpc_health_v1.HealthCheckRequest
+}
+
+func (p *WatchArgs) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetReq() {
+		p.Req = new(grpc_health_v1.HealthCheckRequest)
+	}
+	return p.Req.FastRead(buf, _type, number)
+}
+
+func (p *WatchArgs) FastWrite(buf []byte) (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.FastWrite(buf)
+}
+
+func (p *WatchArgs) Size() (n int) {
+	if !p.IsSetReq() {
+		return 0
+	}
+	return p.Req.Size()
+}
+
+func (p *WatchArgs) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetReq() {
+		return out, nil
+	}
+	return proto.Marshal(p.Req)
+}
+
+func (p *WatchArgs) Unmarshal(in []byte) error {
+	msg := new(grpc_health_v1.HealthCheckRequest)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Req = msg
+	return nil
+}
+
+var WatchArgs_Req_DEFAULT *grpc_health_v1.HealthCheckRequest
+
+func (p *WatchArgs) GetReq() *grpc_health_v1.HealthCheckRequest {
+	if !p.IsSetReq() {
+		return WatchArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *WatchArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *WatchArgs) GetFirstArgument() interface{} {
+	return p.Req
+}
+
+type WatchResult struct {
+	Success *grpc_health_v1.HealthCheckResponse
+}
+
+var WatchResult_Success_DEFAULT *grpc_health_v1.HealthCheckResponse
+
+func (p *WatchResult) FastRead(buf []byte, _type int8, number int32) (n int, err error) {
+	if !p.IsSetSuccess() {
+		p.Success = new(grpc_health_v1.HealthCheckResponse)
+	}
+	return p.Success.FastRead(buf, _type, number)
+}
+
+func (p *WatchResult) FastWrite(buf []byte) (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.FastWrite(buf)
+}
+
+func (p *WatchResult) Size() (n int) {
+	if !p.IsSetSuccess() {
+		return 0
+	}
+	return p.Success.Size()
+}
+
+func (p *WatchResult) Marshal(out []byte) ([]byte, error) {
+	if !p.IsSetSuccess() {
+		return out, nil
+	}
+	return proto.Marshal(p.Success)
+}
+
+func (p *WatchResult) Unmarshal(in []byte) error {
+	msg := new(grpc_health_v1.HealthCheckResponse)
+	if err := proto.Unmarshal(in, msg); err != nil {
+		return err
+	}
+	p.Success = msg
+	return nil
+}
+
+func (p *WatchResult) GetSuccess() *grpc_health_v1.HealthCheckResponse {
+	if !p.IsSetSuccess() {
+		return WatchResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *WatchResult) SetSuccess(x interface{}) {
+	p.Success = x.(*grpc_health_v1.HealthCheckResponse)
+}
+
+func (p *WatchResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *WatchResult) GetResult() interface{} {
+	return p.Success
+}
+
+type kClient struct {
+	c client.Client
+}
+
+func newServiceClient(c client.Client) *kClient {
+	return &kClient{
+		c: c,
+	}
+}
+
+func (p *kClient) Check(ctx context.Context, Req *grpc_health_v1.HealthCheckRequest) (r *grpc_health_v1.HealthCheckResponse, err error) {
+	var _args CheckArgs
+	_args.Req = Req
+	var _result CheckResult
+	if err = p.c.Call(ctx, "Check", &_args, &_result); err != nil {
+		return
+	}
+	return _result.GetSuccess(), nil
+}
+
+func (p *kClient) Watch(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (Health_WatchClient, error) {
+	streamClient, ok := p.c.(client.Streaming)
+	if !ok {
+		return nil, fmt.Errorf("client not support streaming")
+	}
+	res := new(streaming.Result)
+	err := streamClient.Stream(ctx, "Watch", nil, res)
+	if err != nil {
+		return nil, err
+	}
+	stream := &healthWatchClient{res.Stream}
+
+	if err := stream.Stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.Stream.Close(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
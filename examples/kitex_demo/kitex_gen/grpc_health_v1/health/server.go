@@ -0,0 +1,24 @@
+// Code generated by Kitex v0.12.3. DO NOT EDIT.
+package health
+
+import (
+	grpc_health_v1 "example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+	server "github.com/cloudwego/kitex/server"
+)
+
+// NewServer creates a server.Server with the given handler and options.
+func NewServer(handler grpc_health_v1.Health, opts ...server.Option) server.Server {
+	var options []server.Option
+
+	options = append(options, opts...)
+
+	svr := server.NewServer(options...)
+	if err := svr.RegisterService(serviceInfo(), handler); err != nil {
+		panic(err)
+	}
+	return svr
+}
+
+func RegisterService(svr server.Server, handler grpc_health_v1.Health, opts ...server.RegisterOption) error {
+	return svr.RegisterService(serviceInfo(), handler, opts...)
+}
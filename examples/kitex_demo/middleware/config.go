@@ -0,0 +1,109 @@
+// Package middleware Kitex 服务端和客户端的通用中间件：请求日志（带 trace id）、panic 恢复、
+// 按方法的超时、并发和 QPS 限制，以及客户端重试，全部通过 YAML 配置
+package middleware
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config 中间件配置文件
+type Config struct {
+	Server ServerConfig `yaml:"server"`
+	Client ClientConfig `yaml:"client"`
+}
+
+// ServerConfig 服务端中间件配置
+type ServerConfig struct {
+	Log      LogConfig     `yaml:"log"`
+	Recovery bool          `yaml:"recovery"`
+	Timeout  TimeoutConfig `yaml:"timeout"`
+	Limit    LimitConfig   `yaml:"limit"`
+}
+
+// ClientConfig 客户端中间件配置
+type ClientConfig struct {
+	Log     LogConfig     `yaml:"log"`
+	Timeout TimeoutConfig `yaml:"timeout"`
+	Retry   RetryConfig   `yaml:"retry"`
+}
+
+// LogConfig 请求日志配置
+type LogConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 超过该耗时的请求以 Warn 级别记录，为 0 不区分
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+}
+
+// TimeoutConfig 超时配置，Methods 中的方法覆盖 Default
+type TimeoutConfig struct {
+	Default time.Duration            `yaml:"default"`
+	Connect time.Duration            `yaml:"connect"` // 仅客户端
+	Methods map[string]time.Duration `yaml:"methods"`
+}
+
+// For 返回方法的超时时间，为 0 表示不限制
+func (c TimeoutConfig) For(method string) time.Duration {
+	if d, ok := c.Methods[method]; ok {
+		return d
+	}
+	return c.Default
+}
+
+// LimitConfig 服务端限流配置
+type LimitConfig struct {
+	// 整个服务的连接数和 QPS 上限，由 Kitex 内置限流器执行
+	MaxConnections int `yaml:"max_connections"`
+	MaxQPS         int `yaml:"max_qps"`
+	// 按方法的并发和 QPS 上限
+	Methods map[string]MethodLimit `yaml:"methods"`
+}
+
+// MethodLimit 单个方法的限制，为 0 表示不限制
+type MethodLimit struct {
+	MaxConcurrency int `yaml:"max_concurrency"`
+	MaxQPS         int `yaml:"max_qps"`
+}
+
+// RetryConfig 客户端失败重试配置，默认只重试超时的请求
+type RetryConfig struct {
+	MaxRetries  int           `yaml:"max_retries"`  // 为 0 关闭重试
+	MaxDuration time.Duration `yaml:"max_duration"` // 包含重试在内的总耗时上限
+	Backoff     time.Duration `yaml:"backoff"`      // 两次重试之间的固定间隔
+	// 连接实例失败（实例已下线）时也重试，请求还没有发出，重试会换一个实例
+	ConnectionError bool `yaml:"connection_error"`
+	// 允许重试的方法，为空时所有方法都重试；非幂等的方法不要放在这里
+	Methods []string `yaml:"methods"`
+}
+
+// Load 读取配置文件
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read middleware config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse middleware config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid middleware config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for method, l := range c.Server.Limit.Methods {
+		if l.MaxConcurrency < 0 || l.MaxQPS < 0 {
+			return fmt.Errorf("limit of %s must not be negative", method)
+		}
+	}
+	if c.Client.Retry.MaxRetries < 0 {
+		return fmt.Errorf("retry.max_retries must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "middleware.yaml")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+server:
+  recovery: true
+  timeout:
+    default: 1s
+    methods:
+      Evaluate: 5s
+  limit:
+    methods:
+      Divide: {max_concurrency: 10, max_qps: 100}
+client:
+  retry:
+    max_retries: 2
+    methods: [Add]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Server.Recovery || cfg.Server.Timeout.For("Evaluate") != 5*time.Second {
+		t.Errorf("server config = %+v", cfg.Server)
+	}
+	if l := cfg.Server.Limit.Methods["Divide"]; l.MaxConcurrency != 10 || l.MaxQPS != 100 {
+		t.Errorf("Divide limit = %+v", l)
+	}
+	if r := cfg.Client.Retry; r.MaxRetries != 2 || len(r.Methods) != 1 {
+		t.Errorf("retry = %+v", r)
+	}
+
+	// 仓库中的示例配置可以加载
+	if _, err := Load("../conf/middleware.yaml"); err != nil {
+		t.Errorf("conf/middleware.yaml: %v", err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"negative limit": "server:\n  limit:\n    methods:\n      Add: {max_qps: -1}\n",
+		"negative retry": "client:\n  retry:\n    max_retries: -1\n",
+		"bad duration":   "server:\n  timeout:\n    default: soon\n",
+	}
+	for name, text := range tests {
+		if _, err := Load(writeConfig(t, text)); err == nil {
+			t.Errorf("%s: Load succeeded, want error", name)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing file: Load succeeded, want error")
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// MethodLimiter 按方法限制并发数和 QPS，超限时返回 kerrors.ErrOverlimit
+func MethodLimiter(limits map[string]MethodLimit) endpoint.Middleware {
+	limiters := make(map[string]*methodLimiter, len(limits))
+	for method, l := range limits {
+		limiters[method] = newMethodLimiter(l)
+	}
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			method := methodName(ctx)
+			l, ok := limiters[method]
+			if !ok {
+				return next(ctx, req, resp)
+			}
+
+			if err := l.acquire(); err != nil {
+				return kerrors.ErrOverlimit.WithCause(fmt.Errorf("method %s: %w", method, err))
+			}
+			defer l.release()
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+type methodLimiter struct {
+	maxConcurrency int64
+	running        atomic.Int64
+	bucket         *tokenBucket
+}
+
+func newMethodLimiter(l MethodLimit) *methodLimiter {
+	ml := &methodLimiter{maxConcurrency: int64(l.MaxConcurrency)}
+	if l.MaxQPS > 0 {
+		ml.bucket = newTokenBucket(l.MaxQPS)
+	}
+	return ml
+}
+
+// acquire 先占用并发名额再取 QPS 令牌，因并发超限被拒绝的请求不消耗令牌
+func (l *methodLimiter) acquire() error {
+	if n := l.running.Add(1); l.maxConcurrency > 0 && n > l.maxConcurrency {
+		l.running.Add(-1)
+		return fmt.Errorf("concurrency over %d", l.maxConcurrency)
+	}
+	if l.bucket != nil && !l.bucket.allow() {
+		l.running.Add(-1)
+		return fmt.Errorf("qps over %d", l.bucket.rate)
+	}
+	return nil
+}
+
+func (l *methodLimiter) release() {
+	l.running.Add(-1)
+}
+
+// tokenBucket 令牌桶，每秒补充 rate 个令牌，最多积累 rate 个
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   int
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: float64(rate), last: time.Now()}
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * float64(b.rate)
+	if b.tokens > float64(b.rate) {
+		b.tokens = float64(b.rate)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
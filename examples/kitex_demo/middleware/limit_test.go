@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// withMethod 返回带 rpcinfo 的 ctx，中间件从中取方法名
+func withMethod(method string) context.Context {
+	ri := rpcinfo.NewRPCInfo(nil, nil, rpcinfo.NewInvocation("calculator", method), nil, nil)
+	return rpcinfo.NewCtxWithRPCInfo(context.Background(), ri)
+}
+
+// blockingEndpoint 在 release 关闭前阻塞，started 在每次进入时收到信号
+func blockingEndpoint() (ep endpoint.Endpoint, started chan struct{}, release chan struct{}) {
+	started, release = make(chan struct{}, 16), make(chan struct{})
+	ep = func(ctx context.Context, req, resp interface{}) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	return ep, started, release
+}
+
+func noop(ctx context.Context, req, resp interface{}) error { return nil }
+
+func TestMethodLimiterConcurrency(t *testing.T) {
+	ep, started, release := blockingEndpoint()
+	call := MethodLimiter(map[string]MethodLimit{"Add": {MaxConcurrency: 2}})(ep)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := call(withMethod("Add"), nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+		<-started
+	}
+
+	if err := call(withMethod("Add"), nil, nil); !errors.Is(err, kerrors.ErrOverlimit) {
+		t.Fatalf("third concurrent call = %v, want ErrOverlimit", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if err := call(withMethod("Add"), nil, nil); err != nil {
+		t.Fatalf("call after release = %v", err)
+	}
+}
+
+func TestMethodLimiterQPS(t *testing.T) {
+	call := MethodLimiter(map[string]MethodLimit{"Add": {MaxQPS: 2}})(noop)
+
+	for i := 0; i < 2; i++ {
+		if err := call(withMethod("Add"), nil, nil); err != nil {
+			t.Fatalf("call %d = %v", i, err)
+		}
+	}
+	if err := call(withMethod("Add"), nil, nil); !errors.Is(err, kerrors.ErrOverlimit) {
+		t.Fatalf("call over qps = %v, want ErrOverlimit", err)
+	}
+	// 其他方法和没有 rpcinfo 的调用不受限制
+	if err := call(withMethod("Subtract"), nil, nil); err != nil {
+		t.Errorf("unlimited method = %v", err)
+	}
+	if err := call(context.Background(), nil, nil); err != nil {
+		t.Errorf("call without rpcinfo = %v", err)
+	}
+}
+
+func TestMethodLimiterConcurrencyRejectKeepsToken(t *testing.T) {
+	ep, started, release := blockingEndpoint()
+	call := MethodLimiter(map[string]MethodLimit{"Add": {MaxConcurrency: 1, MaxQPS: 2}})(ep)
+
+	done := make(chan error)
+	go func() { done <- call(withMethod("Add"), nil, nil) }()
+	<-started
+
+	// 因并发超限被拒绝的请求不消耗令牌
+	for i := 0; i < 5; i++ {
+		if err := call(withMethod("Add"), nil, nil); !errors.Is(err, kerrors.ErrOverlimit) {
+			t.Fatalf("call while busy = %v, want ErrOverlimit", err)
+		}
+	}
+	release <- struct{}{}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { done <- call(withMethod("Add"), nil, nil) }()
+	select {
+	case <-started:
+	case err := <-done:
+		t.Fatalf("call after busy period = %v, want the remaining token", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMethodLimiterQPSRejectKeepsConcurrency(t *testing.T) {
+	l := newMethodLimiter(MethodLimit{MaxConcurrency: 1, MaxQPS: 1})
+	if err := l.acquire(); err != nil {
+		t.Fatal(err)
+	}
+	l.release()
+
+	// 令牌用完被拒绝时归还并发名额
+	if err := l.acquire(); err == nil {
+		t.Fatal("second acquire within a second should be rejected by qps")
+	}
+	if n := l.running.Load(); n != 0 {
+		t.Errorf("running = %d after qps rejection, want 0", n)
+	}
+}
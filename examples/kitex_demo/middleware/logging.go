@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+)
+
+// ServerLogging 记录每个请求的方法、调用方、trace id、耗时和错误
+func ServerLogging(cfg LogConfig) endpoint.Middleware {
+	return logging(cfg, "server", false)
+}
+
+// ClientLogging 记录每个调用，ctx 中没有 trace id 时生成一个并传给服务端
+func ClientLogging(cfg LogConfig) endpoint.Middleware {
+	return logging(cfg, "client", true)
+}
+
+func logging(cfg LogConfig, side string, injectTrace bool) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			if injectTrace {
+				ctx = WithTraceID(ctx)
+			}
+
+			start := time.Now()
+			err := next(ctx, req, resp)
+			cost := time.Since(start)
+
+			ri := rpcinfo.GetRPCInfo(ctx)
+			method, peer := "", ""
+			var bizErr kerrors.BizStatusErrorIface
+			if ri != nil {
+				method = ri.Invocation().MethodName()
+				// 业务错误由框架放在 rpcinfo 中，不经过中间件的返回值
+				bizErr = ri.Invocation().BizStatusErr()
+				if side == "server" {
+					peer = ri.From().ServiceName()
+					if addr := ri.From().Address(); addr != nil {
+						peer += "@" + addr.String()
+					}
+				} else {
+					peer = ri.To().ServiceName()
+				}
+			}
+
+			switch {
+			case err != nil:
+				klog.CtxErrorf(ctx, "[%s] method=%s peer=%s trace_id=%s cost=%s err=%v", side, method, peer, TraceID(ctx), cost, err)
+			case bizErr != nil:
+				klog.CtxWarnf(ctx, "[%s] method=%s peer=%s trace_id=%s cost=%s biz_code=%d biz_msg=%s",
+					side, method, peer, TraceID(ctx), cost, bizErr.BizStatusCode(), bizErr.BizMessage())
+			case cfg.SlowThreshold > 0 && cost > cfg.SlowThreshold:
+				klog.CtxWarnf(ctx, "[%s] slow method=%s peer=%s trace_id=%s cost=%s", side, method, peer, TraceID(ctx), cost)
+			default:
+				klog.CtxInfof(ctx, "[%s] method=%s peer=%s trace_id=%s cost=%s", side, method, peer, TraceID(ctx), cost)
+			}
+			return err
+		}
+	}
+}
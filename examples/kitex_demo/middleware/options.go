@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/kitex/client"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/limit"
+	"github.com/cloudwego/kitex/pkg/retry"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/server"
+)
+
+// ServerOptions 根据配置返回服务端选项
+// 中间件从外到内依次为：日志、panic 恢复、方法限流、超时，限流和超时的拒绝也会被记录
+func ServerOptions(cfg ServerConfig) []server.Option {
+	// gRPC 传输下非流式方法也按流式调用，开启后中间件能拿到实际的请求和响应
+	opts := []server.Option{server.WithCompatibleMiddlewareForUnary()}
+	if cfg.Log.Enabled {
+		opts = append(opts, server.WithMiddleware(ServerLogging(cfg.Log)))
+	}
+	if cfg.Recovery {
+		opts = append(opts, server.WithMiddleware(Recovery()))
+	}
+	if len(cfg.Limit.Methods) > 0 {
+		opts = append(opts, server.WithMiddleware(MethodLimiter(cfg.Limit.Methods)))
+	}
+	if cfg.Timeout.Default > 0 || len(cfg.Timeout.Methods) > 0 {
+		opts = append(opts, server.WithMiddleware(ServerTimeout(cfg.Timeout)))
+	}
+
+	lim := &limit.Option{MaxConnections: cfg.Limit.MaxConnections, MaxQPS: cfg.Limit.MaxQPS}
+	if lim.Valid() {
+		opts = append(opts, server.WithLimit(lim))
+	}
+	return opts
+}
+
+// ClientOptions 根据配置返回客户端选项
+func ClientOptions(cfg ClientConfig) []client.Option {
+	var opts []client.Option
+	if cfg.Log.Enabled {
+		opts = append(opts, client.WithMiddleware(ClientLogging(cfg.Log)))
+	}
+	if cfg.Timeout.Default > 0 || cfg.Timeout.Connect > 0 || len(cfg.Timeout.Methods) > 0 {
+		opts = append(opts, client.WithTimeoutProvider(clientTimeouts{cfg: cfg.Timeout}))
+	}
+
+	if r := cfg.Retry; r.MaxRetries > 0 {
+		policy := retry.NewFailurePolicy()
+		if r.ConnectionError {
+			policy = retry.NewFailurePolicyWithResultRetry(&retry.ShouldResultRetry{
+				ErrorRetryWithCtx: func(_ context.Context, err error, _ rpcinfo.RPCInfo) bool {
+					return errors.Is(err, kerrors.ErrGetConnection)
+				},
+			})
+		}
+		policy.WithMaxRetryTimes(r.MaxRetries)
+		if r.MaxDuration > 0 {
+			policy.WithMaxDurationMS(uint32(r.MaxDuration.Milliseconds()))
+		}
+		if r.Backoff > 0 {
+			policy.WithFixedBackOff(int(r.Backoff.Milliseconds()))
+		}
+
+		if len(r.Methods) == 0 {
+			opts = append(opts, client.WithFailureRetry(policy))
+		} else {
+			methods := make(map[string]retry.Policy, len(r.Methods))
+			for _, m := range r.Methods {
+				methods[m] = retry.BuildFailurePolicy(policy)
+			}
+			opts = append(opts, client.WithRetryMethodPolicies(methods))
+		}
+	}
+	return opts
+}
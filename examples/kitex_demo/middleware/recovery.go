@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// Recovery 捕获中间件中的 panic 并返回 kerrors.ErrPanic
+// 业务代码的 panic 已由 Kitex 转换为 kerrors.ErrPanic，这里补充 trace id 和堆栈日志
+func Recovery() endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = kerrors.ErrPanic.WithCauseAndStack(fmt.Errorf("%v", r), string(debug.Stack()))
+				}
+				var de *kerrors.DetailedError
+				if errors.As(err, &de) && errors.Is(err, kerrors.ErrPanic) {
+					klog.CtxErrorf(ctx, "panic in %s trace_id=%s: %v\n%s", methodName(ctx), TraceID(ctx), err, de.Stack())
+				}
+			}()
+			return next(ctx, req, resp)
+		}
+	}
+}
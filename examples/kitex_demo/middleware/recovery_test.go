@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+func TestRecovery(t *testing.T) {
+	panicking := func(ctx context.Context, req, resp interface{}) error {
+		panic("boom")
+	}
+	err := Recovery()(panicking)(withMethod("Add"), nil, nil)
+	if !errors.Is(err, kerrors.ErrPanic) {
+		t.Fatalf("err = %v, want ErrPanic", err)
+	}
+	var de *kerrors.DetailedError
+	if !errors.As(err, &de) || !strings.Contains(de.Stack(), "recovery_test.go") {
+		t.Error("panic error should carry the stack of the panicking call")
+	}
+
+	if err := Recovery()(noop)(withMethod("Add"), nil, nil); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	ctx := WithTraceID(context.Background())
+	id := TraceID(ctx)
+	if len(id) != 16 {
+		t.Fatalf("trace id = %q, want 16 hex characters", id)
+	}
+	if got := TraceID(WithTraceID(ctx)); got != id {
+		t.Errorf("WithTraceID replaced existing id %q with %q", id, got)
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/endpoint"
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/streaming"
+)
+
+// ServerTimeout 按方法为请求设置截止时间
+// 截止时间通过 ctx 传给业务代码，业务返回时已超时则返回 kerrors.ErrRPCTimeout；
+// 业务代码需要检查 ctx 才能提前结束。流式方法只设置 ctx 截止时间
+func ServerTimeout(cfg TimeoutConfig) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req, resp interface{}) error {
+			method := methodName(ctx)
+			d := cfg.For(method)
+			if d <= 0 {
+				return next(ctx, req, resp)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			err := next(ctx, req, resp)
+			if _, ok := req.(*streaming.Args); ok {
+				return err
+			}
+			if ctx.Err() == context.DeadlineExceeded {
+				return kerrors.ErrRPCTimeout.WithCause(fmt.Errorf("method %s exceeded %s", method, d))
+			}
+			return err
+		}
+	}
+}
+
+// clientTimeouts 按方法提供客户端超时
+type clientTimeouts struct {
+	cfg TimeoutConfig
+}
+
+func (p clientTimeouts) Timeouts(ri rpcinfo.RPCInfo) rpcinfo.Timeouts {
+	return timeouts{rpc: p.cfg.For(ri.Invocation().MethodName()), connect: p.cfg.Connect}
+}
+
+type timeouts struct {
+	rpc, connect time.Duration
+}
+
+func (t timeouts) RPCTimeout() time.Duration       { return t.rpc }
+func (t timeouts) ConnectTimeout() time.Duration   { return t.connect }
+func (t timeouts) ReadWriteTimeout() time.Duration { return t.rpc }
+
+func methodName(ctx context.Context) string {
+	if ri := rpcinfo.GetRPCInfo(ctx); ri != nil {
+		return ri.Invocation().MethodName()
+	}
+	return ""
+}
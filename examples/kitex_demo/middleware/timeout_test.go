@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+	"github.com/cloudwego/kitex/pkg/streaming"
+)
+
+func TestTimeoutConfigFor(t *testing.T) {
+	cfg := TimeoutConfig{Default: time.Second, Methods: map[string]time.Duration{"Evaluate": 5 * time.Second, "Add": 0}}
+	tests := map[string]time.Duration{
+		"Evaluate": 5 * time.Second,
+		"Add":      0, // 显式为 0 表示不限制
+		"Divide":   time.Second,
+	}
+	for method, want := range tests {
+		if got := cfg.For(method); got != want {
+			t.Errorf("For(%s) = %s, want %s", method, got, want)
+		}
+	}
+}
+
+func TestServerTimeout(t *testing.T) {
+	mw := ServerTimeout(TimeoutConfig{Methods: map[string]time.Duration{"Slow": 10 * time.Millisecond}})
+	waitDone := func(ctx context.Context, req, resp interface{}) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	if err := mw(waitDone)(withMethod("Slow"), nil, nil); !errors.Is(err, kerrors.ErrRPCTimeout) {
+		t.Errorf("slow unary call = %v, want ErrRPCTimeout", err)
+	}
+
+	// 流式方法只设置截止时间，返回值保持不变
+	if err := mw(waitDone)(withMethod("Slow"), &streaming.Args{}, nil); err != nil {
+		t.Errorf("slow streaming call = %v, want nil", err)
+	}
+
+	// 没有配置超时的方法不设置截止时间
+	noDeadline := func(ctx context.Context, req, resp interface{}) error {
+		if _, ok := ctx.Deadline(); ok {
+			return errors.New("unexpected deadline")
+		}
+		return nil
+	}
+	if err := mw(noDeadline)(withMethod("Add"), nil, nil); err != nil {
+		t.Error(err)
+	}
+
+	bizErr := errors.New("biz")
+	fail := func(ctx context.Context, req, resp interface{}) error { return bizErr }
+	if err := mw(fail)(withMethod("Slow"), nil, nil); err != bizErr {
+		t.Errorf("error within deadline = %v, want it unchanged", err)
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/bytedance/gopkg/cloud/metainfo"
+)
+
+// TraceIDKey trace id 在 metainfo 中的键，作为持久值随调用链向下游传递
+// gRPC 传输会把键转换为 HTTP 头再转换回来，只有大写加下划线的键能保持不变
+const TraceIDKey = "TRACE_ID"
+
+// TraceID 返回 ctx 中的 trace id
+func TraceID(ctx context.Context) string {
+	id, _ := metainfo.GetPersistentValue(ctx, TraceIDKey)
+	return id
+}
+
+// WithTraceID ctx 中没有 trace id 时生成一个
+func WithTraceID(ctx context.Context) context.Context {
+	if TraceID(ctx) != "" {
+		return ctx
+	}
+	return metainfo.WithPersistentValue(ctx, TraceIDKey, newTraceID())
+}
+
+func newTraceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
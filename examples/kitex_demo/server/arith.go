@@ -0,0 +1,74 @@
+package main
+
+import "math"
+
+// 带溢出检查的整数运算，Calculator 的各个方法和 Evaluate 共用
+
+func add(a, b int64) (int64, error) {
+	r := a + b
+	if (b > 0 && r < a) || (b < 0 && r > a) {
+		return 0, errOverflow
+	}
+	return r, nil
+}
+
+func subtract(a, b int64) (int64, error) {
+	r := a - b
+	if (b > 0 && r > a) || (b < 0 && r < a) {
+		return 0, errOverflow
+	}
+	return r, nil
+}
+
+func multiply(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	r := a * b
+	if r/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, errOverflow
+	}
+	return r, nil
+}
+
+// divide 整数除法，结果向零取整
+func divide(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errDivideByZero
+	}
+	if a == math.MinInt64 && b == -1 {
+		return 0, errOverflow
+	}
+	return a / b, nil
+}
+
+// modulo 取模，结果符号与被除数相同
+func modulo(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, errDivideByZero
+	}
+	return a % b, nil
+}
+
+// power 整数乘方，使用快速幂
+func power(base, exp int64) (int64, error) {
+	if exp < 0 {
+		return 0, errNegativeExponent
+	}
+	result := int64(1)
+	for exp > 0 {
+		var err error
+		if exp&1 == 1 {
+			if result, err = multiply(result, base); err != nil {
+				return 0, err
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, err = multiply(base, base); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"example/examples/kitex_demo/kitex_gen/api"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// errorCode 取出业务错误码，不是业务错误时返回 -1
+func errorCode(err error) api.ErrorCode {
+	if bizErr, ok := kerrors.FromBizStatusError(err); ok {
+		return api.ErrorCode(bizErr.BizStatusCode())
+	}
+	return -1
+}
+
+func TestArith(t *testing.T) {
+	const ok = api.ErrorCode(0)
+	tests := []struct {
+		name string
+		op   func(a, b int64) (int64, error)
+		a, b int64
+		want int64
+		code api.ErrorCode
+	}{
+		{"add", add, 2, 3, 5, ok},
+		{"add max overflow", add, math.MaxInt64, 1, 0, api.ErrorCode_OVERFLOW},
+		{"add min overflow", add, math.MinInt64, -1, 0, api.ErrorCode_OVERFLOW},
+		{"add to min", add, math.MinInt64 + 1, -1, math.MinInt64, ok},
+
+		{"subtract", subtract, 2, 5, -3, ok},
+		{"subtract min overflow", subtract, math.MinInt64, 1, 0, api.ErrorCode_OVERFLOW},
+		{"subtract max overflow", subtract, math.MaxInt64, -1, 0, api.ErrorCode_OVERFLOW},
+		{"subtract min from zero", subtract, 0, math.MinInt64, 0, api.ErrorCode_OVERFLOW},
+		{"subtract min from -1", subtract, -1, math.MinInt64, math.MaxInt64, ok},
+
+		{"multiply", multiply, -4, 5, -20, ok},
+		{"multiply by zero", multiply, math.MinInt64, 0, 0, ok},
+		{"multiply overflow", multiply, math.MaxInt64, 2, 0, api.ErrorCode_OVERFLOW},
+		{"multiply min by -1", multiply, math.MinInt64, -1, 0, api.ErrorCode_OVERFLOW},
+		{"multiply -1 by min", multiply, -1, math.MinInt64, 0, api.ErrorCode_OVERFLOW},
+		{"multiply to min", multiply, math.MinInt64 / 2, 2, math.MinInt64, ok},
+		{"multiply large", multiply, 3037000499, 3037000499, 9223372030926249001, ok},
+		{"multiply large overflow", multiply, 3037000500, 3037000500, 0, api.ErrorCode_OVERFLOW},
+
+		{"divide truncates toward zero", divide, -7, 2, -3, ok},
+		{"divide by zero", divide, 1, 0, 0, api.ErrorCode_DIVIDE_BY_ZERO},
+		{"divide min by -1", divide, math.MinInt64, -1, 0, api.ErrorCode_OVERFLOW},
+
+		{"modulo keeps dividend sign", modulo, -7, 3, -1, ok},
+		{"modulo negative divisor", modulo, 7, -3, 1, ok},
+		{"modulo by zero", modulo, 1, 0, 0, api.ErrorCode_DIVIDE_BY_ZERO},
+		{"modulo min by -1", modulo, math.MinInt64, -1, 0, ok},
+
+		{"power", power, 3, 4, 81, ok},
+		{"power zero exponent", power, 0, 0, 1, ok},
+		{"power negative base", power, -2, 3, -8, ok},
+		{"power -1 large exponent", power, -1, math.MaxInt64, -1, ok},
+		{"power 2^62", power, 2, 62, 1 << 62, ok},
+		{"power 2^63 overflow", power, 2, 63, 0, api.ErrorCode_OVERFLOW},
+		{"power -2^63", power, -2, 63, math.MinInt64, ok},
+		{"power 3^39", power, 3, 39, 4052555153018976267, ok},
+		{"power 3^40 overflow", power, 3, 40, 0, api.ErrorCode_OVERFLOW},
+		{"power negative exponent", power, 2, -1, 0, api.ErrorCode_NEGATIVE_EXPONENT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op(tt.a, tt.b)
+			if tt.code != ok {
+				if code := errorCode(err); code != tt.code {
+					t.Fatalf("(%d, %d) error = %v (code %d), want code %d", tt.a, tt.b, err, code, tt.code)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("(%d, %d) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+			}
+		})
+	}
+}
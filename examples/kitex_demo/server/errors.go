@@ -0,0 +1,24 @@
+package main
+
+import (
+	"example/examples/kitex_demo/kitex_gen/api"
+
+	"github.com/cloudwego/kitex/pkg/kerrors"
+)
+
+// 业务错误，客户端通过 kerrors.FromBizStatusError 取出错误码
+var (
+	errDivideByZero     = bizError(api.ErrorCode_DIVIDE_BY_ZERO, "divide by zero")
+	errOverflow         = bizError(api.ErrorCode_OVERFLOW, "integer overflow")
+	errNegativeExponent = bizError(api.ErrorCode_NEGATIVE_EXPONENT, "negative exponent")
+)
+
+// bizError 创建业务错误，同时适用于 TTHeader 和 gRPC 传输
+func bizError(code api.ErrorCode, msg string) error {
+	return kerrors.NewGRPCBizStatusError(int32(code), msg)
+}
+
+// invalidExpression 表达式语法错误
+func invalidExpression(msg string) error {
+	return bizError(api.ErrorCode_INVALID_EXPRESSION, msg)
+}
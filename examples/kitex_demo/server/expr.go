@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// 表达式限制，避免超长输入和过深的递归
+const (
+	maxExpressionLen = 1024
+	maxNestingDepth  = 64
+)
+
+// evaluator 整数表达式求值，支持 + - * / % ^ 和括号
+// 优先级从低到高：+ -，* / %，一元负号，^（右结合）；每完成一次运算调用 onStep
+type evaluator struct {
+	tokens []string
+	pos    int
+	depth  int
+	onStep func(operation string, result int64) error
+}
+
+// evaluate 解析并计算表达式
+func evaluate(expression string, onStep func(operation string, result int64) error) (int64, error) {
+	if len(expression) > maxExpressionLen {
+		return 0, invalidExpression(fmt.Sprintf("expression longer than %d characters", maxExpressionLen))
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return 0, err
+	}
+	if len(tokens) == 0 {
+		return 0, invalidExpression("empty expression")
+	}
+
+	e := &evaluator{tokens: tokens, onStep: onStep}
+	result, err := e.expr()
+	if err != nil {
+		return 0, err
+	}
+	if e.pos < len(e.tokens) {
+		return 0, invalidExpression(fmt.Sprintf("unexpected %q", e.tokens[e.pos]))
+	}
+	return result, nil
+}
+
+// tokenize 拆分为数字、运算符和括号
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case isOperator(c) || c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			return nil, invalidExpression(fmt.Sprintf("unexpected character %q at %d", c, i))
+		}
+	}
+	return tokens, nil
+}
+
+func isOperator(c rune) bool {
+	switch c {
+	case '+', '-', '*', '/', '%', '^':
+		return true
+	}
+	return false
+}
+
+func (e *evaluator) peek() string {
+	return e.lookahead(0)
+}
+
+func (e *evaluator) lookahead(n int) string {
+	if e.pos+n < len(e.tokens) {
+		return e.tokens[e.pos+n]
+	}
+	return ""
+}
+
+func (e *evaluator) next() string {
+	tok := e.peek()
+	e.pos++
+	return tok
+}
+
+// expr := term (('+' | '-') term)*
+func (e *evaluator) expr() (int64, error) {
+	left, err := e.term()
+	if err != nil {
+		return 0, err
+	}
+	for op := e.peek(); op == "+" || op == "-"; op = e.peek() {
+		e.next()
+		right, err := e.term()
+		if err != nil {
+			return 0, err
+		}
+		if left, err = e.apply(op, left, right); err != nil {
+			return 0, err
+		}
+	}
+	return left, nil
+}
+
+// term := unary (('*' | '/' | '%') unary)*
+func (e *evaluator) term() (int64, error) {
+	left, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	for op := e.peek(); op == "*" || op == "/" || op == "%"; op = e.peek() {
+		e.next()
+		right, err := e.unary()
+		if err != nil {
+			return 0, err
+		}
+		if left, err = e.apply(op, left, right); err != nil {
+			return 0, err
+		}
+	}
+	return left, nil
+}
+
+// unary := '-' unary | power
+func (e *evaluator) unary() (int64, error) {
+	if e.peek() != "-" {
+		return e.power()
+	}
+	e.next()
+
+	// 负数字面量直接解析，不算作一步（同时支持 -9223372036854775808）；-2^2 仍按 -(2^2) 计算
+	if tok := e.peek(); isNumber(tok) && e.lookahead(1) != "^" {
+		e.next()
+		return parseNumber("-" + tok)
+	}
+
+	if err := e.enter(); err != nil {
+		return 0, err
+	}
+	defer e.leave()
+
+	v, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	return e.apply("-", 0, v)
+}
+
+// power := primary ('^' unary)?
+func (e *evaluator) power() (int64, error) {
+	base, err := e.primary()
+	if err != nil {
+		return 0, err
+	}
+	if e.peek() != "^" {
+		return base, nil
+	}
+	e.next()
+
+	if err := e.enter(); err != nil {
+		return 0, err
+	}
+	defer e.leave()
+
+	exp, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	return e.apply("^", base, exp)
+}
+
+// primary := number | '(' expr ')'
+func (e *evaluator) primary() (int64, error) {
+	tok := e.next()
+	switch {
+	case tok == "":
+		return 0, invalidExpression("unexpected end of expression")
+	case tok == "(":
+		if err := e.enter(); err != nil {
+			return 0, err
+		}
+		defer e.leave()
+
+		v, err := e.expr()
+		if err != nil {
+			return 0, err
+		}
+		if e.next() != ")" {
+			return 0, invalidExpression("missing )")
+		}
+		return v, nil
+	case isNumber(tok):
+		return parseNumber(tok)
+	default:
+		return 0, invalidExpression(fmt.Sprintf("unexpected %q", tok))
+	}
+}
+
+// apply 计算一步并回调；一元负号以 0 - v 计算
+func (e *evaluator) apply(op string, a, b int64) (int64, error) {
+	var (
+		result    int64
+		err       error
+		operation = fmt.Sprintf("%d %s %d", a, op, b)
+	)
+	switch op {
+	case "+":
+		result, err = add(a, b)
+	case "-":
+		result, err = subtract(a, b)
+	case "*":
+		result, err = multiply(a, b)
+	case "/":
+		result, err = divide(a, b)
+	case "%":
+		result, err = modulo(a, b)
+	case "^":
+		result, err = power(a, b)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if e.onStep != nil {
+		if err := e.onStep(operation, result); err != nil {
+			return 0, err
+		}
+	}
+	return result, nil
+}
+
+func (e *evaluator) enter() error {
+	e.depth++
+	if e.depth > maxNestingDepth {
+		return invalidExpression(fmt.Sprintf("nesting deeper than %d", maxNestingDepth))
+	}
+	return nil
+}
+
+func (e *evaluator) leave() {
+	e.depth--
+}
+
+func isNumber(tok string) bool {
+	return tok != "" && tok[0] >= '0' && tok[0] <= '9'
+}
+
+func parseNumber(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errOverflow
+	}
+	return v, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"example/examples/kitex_demo/kitex_gen/api"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr string
+		want int64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"100 / 10 / 5", 2},
+		{"7 % 4 * 2", 6},
+		{"2 ^ 3 ^ 2", 512}, // 右结合：2^(3^2)
+		{"(2 ^ 3) ^ 2", 64},
+		{"2 * 3 ^ 2", 18},
+		{"-2 ^ 2", -4}, // -(2^2)
+		{"(-2) ^ 2", 4},
+		{"--3", 3},
+		{"-(1 + 2)", -3},
+		{"-9223372036854775808", math.MinInt64},
+		{"9223372036854775807", math.MaxInt64},
+		{" 42 ", 42},
+	}
+	for _, tt := range tests {
+		got, err := evaluate(tt.expr, nil)
+		if err != nil || got != tt.want {
+			t.Errorf("evaluate(%q) = %d, %v, want %d", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		code api.ErrorCode
+	}{
+		{"", api.ErrorCode_INVALID_EXPRESSION},
+		{"   ", api.ErrorCode_INVALID_EXPRESSION},
+		{"1 +", api.ErrorCode_INVALID_EXPRESSION},
+		{"(1 + 2", api.ErrorCode_INVALID_EXPRESSION},
+		{"1 + 2)", api.ErrorCode_INVALID_EXPRESSION},
+		{"1 2", api.ErrorCode_INVALID_EXPRESSION},
+		{"1 + a", api.ErrorCode_INVALID_EXPRESSION},
+		{"*1", api.ErrorCode_INVALID_EXPRESSION},
+		{"1 / 0", api.ErrorCode_DIVIDE_BY_ZERO},
+		{"5 % (3 - 3)", api.ErrorCode_DIVIDE_BY_ZERO},
+		{"2 ^ -1", api.ErrorCode_NEGATIVE_EXPONENT},
+		{"9223372036854775808", api.ErrorCode_OVERFLOW},
+		{"9223372036854775807 + 1", api.ErrorCode_OVERFLOW},
+		{"-9223372036854775808 / -1", api.ErrorCode_OVERFLOW},
+		{"-(-9223372036854775808)", api.ErrorCode_OVERFLOW},
+		{"2 ^ 64", api.ErrorCode_OVERFLOW},
+		{"2 ^ 2 ^ 6", api.ErrorCode_OVERFLOW},
+	}
+	for _, tt := range tests {
+		_, err := evaluate(tt.expr, nil)
+		if code := errorCode(err); code != tt.code {
+			t.Errorf("evaluate(%q) error = %v (code %d), want code %d", tt.expr, err, code, tt.code)
+		}
+	}
+}
+
+func TestEvaluateLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "1" + strings.Repeat(")", depth)
+	}
+	tests := []struct {
+		name string
+		expr string
+		ok   bool
+	}{
+		{"max nesting", nested(maxNestingDepth), true},
+		{"too deep nesting", nested(maxNestingDepth + 1), false},
+		{"too many unary minus", strings.Repeat("-", maxNestingDepth+2) + "(1)", false},
+		{"too long power chain", strings.Repeat("1 ^ ", maxNestingDepth+1) + "1", false},
+		{"power chain within limit", strings.Repeat("1 ^ ", maxNestingDepth) + "1", true},
+		{"too long", strings.Repeat("1+", maxExpressionLen/2) + "1", false},
+	}
+	for _, tt := range tests {
+		_, err := evaluate(tt.expr, nil)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && errorCode(err) != api.ErrorCode_INVALID_EXPRESSION {
+			t.Errorf("%s: error = %v, want INVALID_EXPRESSION", tt.name, err)
+		}
+	}
+}
+
+func TestEvaluateSteps(t *testing.T) {
+	var steps []string
+	result, err := evaluate("-2 ^ 2 + 10 / (3 - 1)", func(operation string, result int64) error {
+		steps = append(steps, operation)
+		return nil
+	})
+	if err != nil || result != 1 {
+		t.Fatalf("result = %d, %v, want 1", result, err)
+	}
+	want := []string{"2 ^ 2", "0 - 4", "3 - 1", "10 / 2", "-4 + 5"}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %q, want %q", steps, want)
+	}
+
+	// 回调出错时停止求值
+	stop := errors.New("stop")
+	calls := 0
+	_, err = evaluate("1 + 2 + 3", func(string, int64) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want stop after 1", err, calls)
+	}
+}
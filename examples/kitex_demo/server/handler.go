@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+
+	"example/examples/kitex_demo/kitex_gen/api"
+)
+
+// CalculatorImpl 实现生成的接口
+type CalculatorImpl struct{}
+
+// Add 实现加法
+func (s *CalculatorImpl) Add(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(add, req)
+}
+
+// Subtract 实现减法
+func (s *CalculatorImpl) Subtract(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(subtract, req)
+}
+
+// Multiply 实现乘法
+func (s *CalculatorImpl) Multiply(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(multiply, req)
+}
+
+// Divide 实现除法，除数为 0 时返回 DIVIDE_BY_ZERO
+func (s *CalculatorImpl) Divide(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(divide, req)
+}
+
+// Modulo 实现取模，模数为 0 时返回 DIVIDE_BY_ZERO
+func (s *CalculatorImpl) Modulo(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(modulo, req)
+}
+
+// Power 实现乘方，指数为负时返回 NEGATIVE_EXPONENT
+func (s *CalculatorImpl) Power(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
+	return calc(power, req)
+}
+
+// Evaluate 表达式求值，每完成一步运算发送一条 EvalStep，最后一条 Done 为 true
+func (s *CalculatorImpl) Evaluate(req *api.EvalRequest, stream api.Calculator_EvaluateServer) error {
+	ctx := stream.Context()
+	result, err := evaluate(req.Expression, func(operation string, result int64) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return stream.Send(&api.EvalStep{Operation: operation, Result: result})
+	})
+	if err != nil {
+		return err
+	}
+	return stream.Send(&api.EvalStep{Operation: req.Expression, Result: result, Done: true})
+}
+
+// calc 执行二元运算，错误以业务错误返回
+func calc(op func(a, b int64) (int64, error), req *api.CalcRequest) (*api.CalcResponse, error) {
+	result, err := op(req.A, req.B)
+	if err != nil {
+		return nil, err
+	}
+	return &api.CalcResponse{Result: result}, nil
+}
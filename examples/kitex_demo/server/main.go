@@ -1,40 +1,80 @@
 package main
 
 import (
-	"context"
+	"flag"
 	"log"
 	"net"
+	"time"
 
-	"example/examples/kitex_demo/kitex_gen/api"
+	"example/examples/kitex_demo/discovery"
+	"example/examples/kitex_demo/health"
 	"example/examples/kitex_demo/kitex_gen/api/calculator"
+	healthsvc "example/examples/kitex_demo/kitex_gen/grpc_health_v1/health"
+	"example/examples/kitex_demo/middleware"
 
+	"github.com/cloudwego/kitex/pkg/klog"
+	"github.com/cloudwego/kitex/pkg/rpcinfo"
+	"github.com/cloudwego/kitex/pkg/transmeta"
 	"github.com/cloudwego/kitex/server"
 )
 
-// CalculatorImpl 实现生成的接口
-type CalculatorImpl struct{}
-
-// Add 实现加法
-func (s *CalculatorImpl) Add(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
-	result := req.A + req.B
-	return &api.CalcResponse{
-		Result: result,
-	}, nil
-}
-
-// Subtract 实现减法
-func (s *CalculatorImpl) Subtract(ctx context.Context, req *api.CalcRequest) (resp *api.CalcResponse, err error) {
-	result := req.A - req.B
-	return &api.CalcResponse{
-		Result: result,
-	}, nil
-}
+const (
+	// serviceName 注册到注册中心的服务名，客户端按这个名字发现实例
+	serviceName = "calculator-service"
+	// calculatorService 健康检查中计算服务的名字，按 gRPC 约定使用 IDL 中的完整服务名
+	calculatorService = "api.Calculator"
+)
 
 func main() {
-	impl := &CalculatorImpl{}
-	svr := calculator.NewServer(impl, server.WithServiceAddr(&net.TCPAddr{Port: 8888}))
-	err := svr.Run()
+	addr := flag.String("addr", ":8888", "监听地址")
+	configPath := flag.String("config", "conf/middleware.yaml", "中间件配置文件")
+	discoveryPath := flag.String("discovery", "conf/discovery.yaml", "服务发现配置文件")
+	drainDelay := flag.Duration("drain-delay", 2*time.Second, "停机时下线后继续处理请求的时间，等待客户端摘除本实例")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "停机时等待进行中的请求完成的最长时间")
+	flag.Parse()
+
+	cfg, err := middleware.Load(*configPath)
 	if err != nil {
 		log.Fatal(err)
 	}
+	dcfg, err := discovery.Load(*discoveryPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	listen, err := net.ResolveTCPAddr("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	reg, err := discovery.NewRegistration(dcfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hs := health.NewServer()
+	hs.SetServingStatus(calculatorService, health.Serving)
+
+	opts := []server.Option{
+		server.WithServiceAddr(listen),
+		server.WithServerBasicInfo(&rpcinfo.EndpointBasicInfo{ServiceName: serviceName}),
+		server.WithExitSignal(gracefulExit(hs, reg, *drainDelay)),
+		server.WithExitWaitTime(*shutdownTimeout),
+		// 业务错误通过传输层元信息返回给客户端
+		server.WithMetaHandler(transmeta.ServerTTHeaderHandler),
+		server.WithMetaHandler(transmeta.ServerHTTP2Handler),
+	}
+	opts = append(opts, middleware.ServerOptions(cfg.Server)...)
+	opts = append(opts, reg.Options()...)
+
+	// 计算服务和健康检查服务注册在同一个服务器上
+	svr := server.NewServer(opts...)
+	if err := calculator.RegisterService(svr, &CalculatorImpl{}); err != nil {
+		log.Fatal(err)
+	}
+	if err := healthsvc.RegisterService(svr, hs); err != nil {
+		log.Fatal(err)
+	}
+	if err := svr.Run(); err != nil {
+		log.Fatal(err)
+	}
+	klog.Info("server stopped")
 }
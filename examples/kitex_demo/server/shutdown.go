@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example/examples/kitex_demo/discovery"
+	"example/examples/kitex_demo/health"
+
+	"github.com/cloudwego/kitex/pkg/klog"
+)
+
+// gracefulExit 返回 Kitex 的退出信号，收到 SIGINT/SIGTERM 后按顺序停机：
+//  1. 健康检查置为 NOT_SERVING，探测方开始摘除本实例
+//  2. 从注册中心注销，客户端在下一次刷新实例列表时不再选中本实例
+//  3. 等待 drainDelay，期间仍正常处理请求；再次收到信号时跳过等待
+//  4. 结束健康检查的 Watch 订阅，返回后 Kitex 停止接受新请求，并在 WithExitWaitTime 的期限内等待进行中的请求完成
+func gracefulExit(hs *health.Server, reg *discovery.Registration, drainDelay time.Duration) func() <-chan error {
+	return func() <-chan error {
+		errCh := make(chan error, 1)
+		// 在返回前注册，之后到达的信号都由这里处理
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			// 排空结束后恢复默认行为，第三次信号直接结束进程
+			defer signal.Stop(sig)
+
+			s := <-sig
+			klog.Infof("received %s, shutting down (drain delay %s)", s, drainDelay)
+			hs.Shutdown()
+			if err := reg.Deregister(); err != nil {
+				klog.Warnf("deregister failed: %v", err)
+			}
+
+			select {
+			case <-time.After(drainDelay):
+			case s := <-sig:
+				klog.Warnf("received %s again, skip drain delay", s)
+			}
+			hs.Close()
+			errCh <- nil
+		}()
+		return errCh
+	}
+}
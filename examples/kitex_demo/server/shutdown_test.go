@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"example/examples/kitex_demo/discovery"
+	"example/examples/kitex_demo/health"
+	"example/examples/kitex_demo/kitex_gen/grpc_health_v1"
+)
+
+func newTestExit(t *testing.T, drainDelay time.Duration) (*health.Server, <-chan error) {
+	t.Helper()
+	reg, err := discovery.NewRegistration(&discovery.Config{Type: discovery.TypeDirect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := health.NewServer()
+	return hs, gracefulExit(hs, reg, drainDelay)()
+}
+
+func sendSignal(t *testing.T) {
+	t.Helper()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitNotServing 等待健康检查变为 NOT_SERVING
+func waitNotServing(t *testing.T, hs *health.Server) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := hs.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status == health.NotServing {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("health status is still SERVING after signal")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGracefulExitDrains(t *testing.T) {
+	hs, errCh := newTestExit(t, 100*time.Millisecond)
+	start := time.Now()
+	sendSignal(t)
+	waitNotServing(t, hs)
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("exit error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("exit signal not delivered after drain delay")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("exited after %s, want at least the drain delay", elapsed)
+	}
+
+	// 返回前结束了健康检查的订阅
+	if err := hs.Watch(&grpc_health_v1.HealthCheckRequest{}, nil); err == nil {
+		t.Error("Watch after exit should fail")
+	}
+}
+
+func TestGracefulExitSecondSignalSkipsDrain(t *testing.T) {
+	hs, errCh := newTestExit(t, time.Hour)
+	sendSignal(t)
+	waitNotServing(t, hs)
+
+	select {
+	case <-errCh:
+		t.Fatal("exited before drain delay")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	sendSignal(t)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("exit error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second signal did not skip drain delay")
+	}
+}
@@ -1,8 +1,11 @@
 module learn/golg/gin-simple-use
 
-go 1.22.4
+go 1.24.1
 
-require github.com/gin-gonic/gin v1.8.2
+require (
+	example v0.0.0
+	github.com/gin-gonic/gin v1.8.2
+)
 
 require (
 	github.com/gin-contrib/sse v0.1.0 // indirect
@@ -10,7 +13,6 @@ require (
 	github.com/go-playground/universal-translator v0.18.0 // indirect
 	github.com/go-playground/validator/v10 v10.11.1 // indirect
 	github.com/goccy/go-json v0.9.11 // indirect
-	github.com/golang/protobuf v1.5.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/leodido/go-urn v1.2.1 // indirect
 	github.com/mattn/go-isatty v0.0.16 // indirect
@@ -25,3 +27,5 @@ require (
 	google.golang.org/protobuf v1.28.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
+
+replace example => ../../..
@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
+	"example/pkg/basic/tlsconf"
+	"flag"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	tlsCfg := tlsconf.Flags(flag.CommandLine)
+	flag.Parse()
+
 	// 创建默认的gin路由引擎
 	r := gin.Default()
 
@@ -51,5 +62,24 @@ func main() {
 		})
 	})
 
-	r.Run(":8080")
+	// mTLS 时返回客户端证书的名称
+	r.GET("/whoami", func(ctx *gin.Context) {
+		if ctx.Request.TLS == nil || len(ctx.Request.TLS.PeerCertificates) == 0 {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "client certificate required",
+			})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{
+			"client": ctx.Request.TLS.PeerCertificates[0].Subject.CommonName,
+		})
+	})
+
+	// 替代 r.Run，配置了证书时使用 HTTPS，证书文件修改后自动重新加载，收到信号后优雅停机
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("Listening on %s (tls: %v, mtls: %v)", *addr, tlsCfg.Enabled(), tlsCfg.ClientCAFile != "")
+	if err := tlsconf.ListenAndServe(ctx, *addr, r, *tlsCfg); err != nil {
+		log.Fatal(err)
+	}
 }
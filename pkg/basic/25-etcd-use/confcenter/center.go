@@ -0,0 +1,264 @@
+// Package confcenter 基于 etcd 的配置中心：从一个前缀加载类型化的配置结构体，
+// 监听前缀下的变化，校验通过后热更新并通知订阅者，校验失败时继续使用旧配置
+//
+//	c := confcenter.New[Config](cli, "/config/myapp/")
+//	if err := c.Start(ctx); err != nil { ... }
+//	defer c.Close()
+//	cfg := c.Get()
+//	c.Subscribe(func(old, new *Config) { ... })
+package confcenter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// DefaultDebounce 默认的合并窗口，逐个修改多个键时只重新加载一次
+const DefaultDebounce = 100 * time.Millisecond
+
+// Validator 配置结构体实现该接口时，加载和热更新前都会校验
+type Validator interface {
+	Validate() error
+}
+
+// Option 配置中心选项
+type Option func(*options)
+
+type options struct {
+	debounce time.Duration
+	onError  func(error)
+}
+
+// WithDebounce 设置变化的合并窗口，为 0 时每次变化立即重新加载
+func WithDebounce(d time.Duration) Option {
+	return func(o *options) { o.debounce = d }
+}
+
+// WithErrorHandler 热更新失败（解析或校验失败）时调用，默认打印日志
+func WithErrorHandler(f func(error)) Option {
+	return func(o *options) { o.onError = f }
+}
+
+// Center 配置中心，Get 和 Subscribe 可以被并发调用
+type Center[T any] struct {
+	cli    *clientv3.Client
+	prefix string
+	opts   options
+
+	mu       sync.RWMutex
+	current  *T
+	revision int64
+	subs     map[int]func(old, new *T)
+	nextSub  int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New 创建配置中心，prefix 建议以 / 结尾，避免 /config/app 匹配到 /config/app2
+func New[T any](cli *clientv3.Client, prefix string, opts ...Option) *Center[T] {
+	o := options{debounce: DefaultDebounce, onError: func(err error) { log.Print(err) }}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Center[T]{cli: cli, prefix: prefix, opts: o, subs: make(map[int]func(old, new *T))}
+}
+
+// Start 加载配置并开始监听，首次加载失败（包括校验失败）时返回错误
+func (c *Center[T]) Start(ctx context.Context) error {
+	cfg, rev, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.current, c.revision = cfg, rev
+	c.mu.Unlock()
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.watch(watchCtx)
+	return nil
+}
+
+// Close 停止监听
+func (c *Center[T]) Close() {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+}
+
+// Get 返回当前配置，调用方不能修改返回的结构体
+func (c *Center[T]) Get() *T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+// Revision 当前配置对应的 etcd 版本号
+func (c *Center[T]) Revision() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.revision
+}
+
+// Subscribe 订阅配置变化，配置热更新后在监听协程中依次调用，返回取消订阅的函数
+func (c *Center[T]) Subscribe(f func(old, new *T)) func() {
+	c.mu.Lock()
+	id := c.nextSub
+	c.nextSub++
+	c.subs[id] = f
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		delete(c.subs, id)
+		c.mu.Unlock()
+	}
+}
+
+// Reload 立即重新加载，配置无效时返回错误并保留旧配置
+func (c *Center[T]) Reload(ctx context.Context) error {
+	cfg, rev, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if rev <= c.revision {
+		c.mu.Unlock()
+		return nil
+	}
+	old := c.current
+	c.current, c.revision = cfg, rev
+	subs := make([]func(old, new *T), 0, len(c.subs))
+	for _, f := range c.subs {
+		subs = append(subs, f)
+	}
+	c.mu.Unlock()
+
+	for _, f := range subs {
+		f(old, cfg)
+	}
+	return nil
+}
+
+// load 读取前缀下的全部键并解析、校验，返回的版本号为读取时的 etcd 版本
+func (c *Center[T]) load(ctx context.Context) (*T, int64, error) {
+	resp, err := c.cli.Get(ctx, c.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, fmt.Errorf("confcenter: load %s: %w", c.prefix, err)
+	}
+	kvs := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = string(kv.Value)
+	}
+
+	cfg := new(T)
+	if err := decode(c.prefix, kvs, cfg); err != nil {
+		return nil, 0, fmt.Errorf("confcenter: %s: %w", c.prefix, err)
+	}
+	if v, ok := interface{}(cfg).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, 0, fmt.Errorf("confcenter: invalid config at revision %d: %w", resp.Header.Revision, err)
+		}
+	}
+	return cfg, resp.Header.Revision, nil
+}
+
+// watch 监听前缀，收到变化后等待合并窗口再重新加载；监听中断时从当前版本重新监听
+func (c *Center[T]) watch(ctx context.Context) {
+	defer close(c.done)
+
+	backoff := 100 * time.Millisecond
+	for ctx.Err() == nil {
+		wch := c.cli.Watch(clientv3.WithRequireLeader(ctx), c.prefix, clientv3.WithPrefix(), clientv3.WithRev(c.Revision()+1))
+		for resp := range wch {
+			if err := resp.Err(); err != nil {
+				// 版本已被压缩时无法补齐中间的事件，直接加载最新配置
+				if errors.Is(err, rpctypes.ErrCompacted) {
+					c.reload(ctx)
+				}
+				break
+			}
+			backoff = 100 * time.Millisecond
+			if len(resp.Events) == 0 {
+				continue
+			}
+			c.debounce(ctx, wch)
+			c.reload(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 5*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// debounce 在合并窗口内继续消费事件
+func (c *Center[T]) debounce(ctx context.Context, wch clientv3.WatchChan) {
+	if c.opts.debounce <= 0 {
+		return
+	}
+	timer := time.NewTimer(c.opts.debounce)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			return
+		case resp, ok := <-wch:
+			if !ok || resp.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// reload 热更新，失败时交给错误处理函数
+func (c *Center[T]) reload(ctx context.Context) {
+	if err := c.Reload(ctx); err != nil && ctx.Err() == nil {
+		c.opts.onError(err)
+	}
+}
+
+// Put 把配置写入 prefix，在一个事务中写入所有键并删除结构体中已不存在的键，监听方只会收到一次变化
+// 事务的操作数受 etcd 的 --max-txn-ops 限制（默认 128），适合常规规模的配置
+func Put[T any](ctx context.Context, cli *clientv3.Client, prefix string, cfg *T) error {
+	kvs, err := encode(prefix, cfg)
+	if err != nil {
+		return fmt.Errorf("confcenter: encode config: %w", err)
+	}
+	resp, err := cli.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return fmt.Errorf("confcenter: put %s: %w", prefix, err)
+	}
+
+	ops := make([]clientv3.Op, 0, len(kvs)+len(resp.Kvs))
+	for k, v := range kvs {
+		ops = append(ops, clientv3.OpPut(k, v))
+	}
+	for _, kv := range resp.Kvs {
+		if _, ok := kvs[string(kv.Key)]; !ok {
+			ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+		}
+	}
+	if _, err := cli.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return fmt.Errorf("confcenter: put %s: %w", prefix, err)
+	}
+	return nil
+}
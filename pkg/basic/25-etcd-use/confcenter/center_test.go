@@ -0,0 +1,184 @@
+package confcenter
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// testConfig 与 27-use-yaml 的 Config 结构相同
+type testConfig struct {
+	Server   testServer   `yaml:"server"`
+	Database testDatabase `yaml:"database"`
+	Features []string     `yaml:"features"`
+}
+
+type testServer struct {
+	Host    string        `yaml:"host"`
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type testDatabase struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"user"`
+}
+
+func (c *testConfig) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return errors.New("server.port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// startEtcd 启动嵌入式 etcd，数据目录在测试结束后删除
+func startEtcd(t *testing.T) *clientv3.Client {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL, _ := url.Parse("http://127.0.0.1:0")
+	peerURL, _ := url.Parse("http://127.0.0.1:0")
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.Name + "=" + peerURL.String()
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("etcd not ready")
+	}
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{e.Clients[0].Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cli.Close() })
+	return cli
+}
+
+const prefix = "/config/myapp/"
+
+func TestLoadFromKeys(t *testing.T) {
+	cli := startEtcd(t)
+	ctx := context.Background()
+
+	// 和 25-etcd-use 一样按层级逐个写入
+	for k, v := range map[string]string{
+		prefix + "server/host":       "127.0.0.1",
+		prefix + "server/port":       "8080",
+		prefix + "server/timeout":    "10s",
+		prefix + "database":          `{"host": "localhost", "port": 3306, "user": "root"}`,
+		prefix + "features":          `["a", "b"]`,
+		"/config/myapp2/server/port": "1",
+	} {
+		if _, err := cli.Put(ctx, k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := New[testConfig](cli, prefix)
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cfg := c.Get()
+	if cfg.Server.Host != "127.0.0.1" || cfg.Server.Port != 8080 || cfg.Server.Timeout != 10*time.Second {
+		t.Errorf("server = %+v", cfg.Server)
+	}
+	if cfg.Database.Username != "root" || cfg.Database.Port != 3306 {
+		t.Errorf("database = %+v", cfg.Database)
+	}
+	if len(cfg.Features) != 2 || cfg.Features[1] != "b" {
+		t.Errorf("features = %v", cfg.Features)
+	}
+}
+
+func TestStartInvalid(t *testing.T) {
+	cli := startEtcd(t)
+	cli.Put(context.Background(), prefix+"server/port", "0")
+	if err := New[testConfig](cli, prefix).Start(context.Background()); err == nil {
+		t.Fatal("Start with invalid config should fail")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	cli := startEtcd(t)
+	ctx := context.Background()
+	initial := &testConfig{Server: testServer{Host: "0.0.0.0", Port: 8080}}
+	if err := Put(ctx, cli, prefix, initial); err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 10)
+	c := New[testConfig](cli, prefix, WithDebounce(50*time.Millisecond), WithErrorHandler(func(err error) { errCh <- err }))
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	updates := make(chan [2]*testConfig, 10)
+	c.Subscribe(func(old, new *testConfig) { updates <- [2]*testConfig{old, new} })
+
+	// 逐个修改多个键，合并为一次更新
+	cli.Put(ctx, prefix+"server/port", "9090")
+	cli.Put(ctx, prefix+"server/timeout", "5s")
+	select {
+	case u := <-updates:
+		if u[0].Server.Port != 8080 || u[1].Server.Port != 9090 || u[1].Server.Timeout != 5*time.Second {
+			t.Errorf("update old=%+v new=%+v", u[0].Server, u[1].Server)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no update received")
+	}
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected second update %+v", u[1].Server)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// 无效的配置不生效
+	cli.Put(ctx, prefix+"server/port", "70000")
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Error("expected validation error")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no validation error reported")
+	}
+	if got := c.Get().Server.Port; got != 9090 {
+		t.Errorf("port after invalid update = %d, want 9090", got)
+	}
+
+	// Put 整体替换，删除已不存在的键
+	next := &testConfig{Server: testServer{Host: "127.0.0.1", Port: 7070}}
+	if err := Put(ctx, cli, prefix, next); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case u := <-updates:
+		if u[1].Server.Port != 7070 || u[1].Server.Timeout != 0 {
+			t.Errorf("after Put = %+v", u[1].Server)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no update after Put")
+	}
+}
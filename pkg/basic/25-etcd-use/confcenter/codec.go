@@ -0,0 +1,99 @@
+package confcenter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decode 把 prefix 下的键值还原为结构体
+// 键按 / 分层，对应 yaml 标签：<prefix>server/port = 8080 对应 Config.Server.Port；
+// 值按 YAML 解析，数字、布尔、时长（10s）和 JSON 对象都可以直接写入
+func decode(prefix string, kvs map[string]string, out interface{}) error {
+	tree := make(map[string]interface{})
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := strings.Split(strings.Trim(strings.TrimPrefix(key, prefix), "/"), "/")
+		if len(path) == 1 && path[0] == "" {
+			continue
+		}
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(kvs[key]), &value); err != nil {
+			return fmt.Errorf("parse %s: %w", key, err)
+		}
+
+		node := tree
+		for i, name := range path {
+			if i == len(path)-1 {
+				if _, ok := node[name].(map[string]interface{}); ok {
+					return fmt.Errorf("key %s conflicts with keys under it", key)
+				}
+				node[name] = value
+				break
+			}
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				if _, exists := node[name]; exists {
+					return fmt.Errorf("key %s conflicts with %s", key, prefix+strings.Join(path[:i+1], "/"))
+				}
+				child = make(map[string]interface{})
+				node[name] = child
+			}
+			node = child
+		}
+	}
+
+	// 借助 yaml 完成类型转换，结构体沿用 yaml 标签
+	data, err := yaml.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode config: %w", err)
+	}
+	return nil
+}
+
+// encode 把结构体展开为 prefix 下的键值，是 decode 的逆过程
+func encode(prefix string, in interface{}) (map[string]string, error) {
+	data, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	kvs := make(map[string]string)
+	if err := flatten(strings.TrimSuffix(prefix, "/"), tree, kvs); err != nil {
+		return nil, err
+	}
+	return kvs, nil
+}
+
+// flatten 嵌套的 map 展开为叶子节点，列表和值一样整体写入一个键
+func flatten(path string, node map[string]interface{}, kvs map[string]string) error {
+	for name, value := range node {
+		key := path + "/" + name
+		if child, ok := value.(map[string]interface{}); ok {
+			if err := flatten(key, child, kvs); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		kvs[key] = strings.TrimSuffix(string(data), "\n")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example.com/etcd-use/confcenter"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Config 与 27-use-yaml 的配置结构相同，etcd 中的键为 <prefix>server/port、<prefix>database/host 等
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Redis    RedisConfig    `yaml:"redis"`
+}
+
+type ServerConfig struct {
+	Host    string        `yaml:"host"`
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+}
+
+type RedisConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Password string `yaml:"password"`
+	Database int    `yaml:"database"`
+}
+
+// Validate 校验配置，实现 confcenter.Validator
+func (c *Config) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port %d out of range", c.Server.Port)
+	}
+	if c.Server.Timeout < 0 {
+		return errors.New("server.timeout must not be negative")
+	}
+	if c.Database.Host == "" {
+		return errors.New("database.host is required")
+	}
+	return nil
+}
+
+// defaultConfig -seed 时写入的初始配置
+func defaultConfig() *Config {
+	return &Config{
+		Server:   ServerConfig{Host: "127.0.0.1", Port: 8080, Timeout: 10 * time.Second},
+		Database: DatabaseConfig{Host: "localhost", Port: 3306, Username: "root", Name: "mydatabase"},
+		Redis:    RedisConfig{Host: "localhost", Port: 6379},
+	}
+}
+
+func main() {
+	endpoint := flag.String("endpoint", "http://127.0.0.1:2379", "etcd 地址")
+	prefix := flag.String("prefix", "/config/myapp/", "配置前缀")
+	seed := flag.Bool("seed", false, "写入默认配置后退出")
+	flag.Parse()
+
+	// 创建etcd 客户端
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{*endpoint},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cli.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *seed {
+		if err := confcenter.Put(ctx, cli, *prefix, defaultConfig()); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("默认配置已写入", *prefix)
+		return
+	}
+
+	// 加载配置并监听变化
+	center := confcenter.New[Config](cli, *prefix)
+	if err := center.Start(ctx); err != nil {
+		log.Fatal(err)
+	}
+	defer center.Close()
+
+	cfg := center.Get()
+	fmt.Printf("服务器地址： %s:%d，超时 %v\n", cfg.Server.Host, cfg.Server.Port, cfg.Server.Timeout)
+	fmt.Printf("数据库地址： %s@%s:%d/%s\n", cfg.Database.Username, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name)
+
+	center.Subscribe(func(old, new *Config) {
+		fmt.Printf("配置已更新（版本 %d）\n", center.Revision())
+		if old.Server != new.Server {
+			fmt.Printf("  server: %+v -> %+v\n", old.Server, new.Server)
+		}
+		if old.Database != new.Database {
+			fmt.Printf("  database: %+v -> %+v\n", old.Database, new.Database)
+		}
+		if old.Redis != new.Redis {
+			fmt.Printf("  redis: %+v -> %+v\n", old.Redis, new.Redis)
+		}
+	})
+
+	fmt.Printf("监听 %s，例如：etcdctl put %sserver/port 9090\n", *prefix, *prefix)
+	<-ctx.Done()
+}
@@ -0,0 +1,231 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example.com/etcd-use/etcdtest"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestLockMutualExclusion(t *testing.T) {
+	cli := etcdtest.Start(t)
+	ctx := context.Background()
+
+	var running, maxRunning, total int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := WithLock(ctx, cli, "/locks/job", func(ctx context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&total, 1)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxRunning != 1 || total != 5 {
+		t.Fatalf("max concurrent = %d, total = %d", maxRunning, total)
+	}
+}
+
+func TestTryAcquireAndFence(t *testing.T) {
+	cli := etcdtest.Start(t)
+	ctx := context.Background()
+
+	l1, err := TryAcquire(ctx, cli, "/locks/try")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryAcquire(ctx, cli, "/locks/try"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second TryAcquire err = %v, want ErrLocked", err)
+	}
+	ran, err := TryWithLock(ctx, cli, "/locks/try", func(context.Context) error { return nil })
+	if ran || err != nil {
+		t.Fatalf("TryWithLock ran = %v, err = %v", ran, err)
+	}
+	if err := l1.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if l1.Context().Err() == nil {
+		t.Fatal("lock context not cancelled after release")
+	}
+
+	l2, err := TryAcquire(ctx, cli, "/locks/try")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l2.Release(ctx)
+	if l2.Fence() <= l1.Fence() {
+		t.Fatalf("fence not increasing: %d then %d", l1.Fence(), l2.Fence())
+	}
+}
+
+func TestAcquireContextCancel(t *testing.T) {
+	cli := etcdtest.Start(t)
+	l, err := Acquire(context.Background(), cli, "/locks/wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, cli, "/locks/wait"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	// 放弃等待的一方不能留下排队键
+	resp, err := cli.Get(context.Background(), "/locks/wait/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 {
+		t.Fatalf("%d keys under lock prefix, want 1", resp.Count)
+	}
+}
+
+func TestLockSessionLost(t *testing.T) {
+	cli := etcdtest.Start(t)
+	ctx := context.Background()
+
+	err := WithLock(ctx, cli, "/locks/lost", func(ctx context.Context) error {
+		resp, err := cli.Get(ctx, "/locks/lost/", clientv3.WithPrefix())
+		if err != nil || len(resp.Kvs) != 1 {
+			t.Fatalf("get lock key: %v", err)
+		}
+		// 模拟租约被撤销（例如长时间 GC 停顿后租约过期）
+		if _, err := cli.Revoke(ctx, clientv3.LeaseID(resp.Kvs[0].Lease)); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("ctx not cancelled after session loss")
+		}
+	}, WithTTL(time.Second))
+	if !errors.Is(err, ErrSessionLost) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want session lost", err)
+	}
+}
+
+func TestElectionFailover(t *testing.T) {
+	cli := etcdtest.Start(t)
+
+	type leaderRun struct {
+		id  string
+		ctx context.Context
+	}
+	elected := make(chan leaderRun, 4)
+	start := func(id string) (*Election, context.CancelFunc, chan error) {
+		e := NewElection(cli, "/election/job", id, WithTTL(time.Second))
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- e.Run(ctx, func(ctx context.Context) error {
+				elected <- leaderRun{id: id, ctx: ctx}
+				<-ctx.Done()
+				return nil
+			})
+		}()
+		return e, cancel, done
+	}
+	waitLeader := func() leaderRun {
+		select {
+		case r := <-elected:
+			return r
+		case <-time.After(10 * time.Second):
+			t.Fatal("no leader elected")
+			return leaderRun{}
+		}
+	}
+
+	a, cancelA, doneA := start("a")
+	first := waitLeader()
+	if first.id != "a" {
+		t.Fatalf("first leader = %s", first.id)
+	}
+	_, cancelB, doneB := start("b")
+	defer func() {
+		cancelB()
+		<-doneB
+	}()
+
+	select {
+	case r := <-elected:
+		t.Fatalf("%s elected while a is leader", r.id)
+	case <-time.After(300 * time.Millisecond):
+	}
+	if id, err := a.Leader(context.Background()); err != nil || id != "a" {
+		t.Fatalf("Leader() = %q, %v", id, err)
+	}
+
+	cancelA()
+	if err := <-doneA; err != nil {
+		t.Fatal(err)
+	}
+	if first.ctx.Err() == nil {
+		t.Fatal("leader context not cancelled")
+	}
+	if next := waitLeader(); next.id != "b" {
+		t.Fatalf("leader after failover = %s", next.id)
+	}
+	if id, err := a.Leader(context.Background()); err != nil || id != "b" {
+		t.Fatalf("Leader() = %q, %v", id, err)
+	}
+}
+
+func TestElectionSessionLost(t *testing.T) {
+	cli := etcdtest.Start(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := NewElection(cli, "/election/lost", "a", WithTTL(time.Second))
+	var terms int32
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Run(ctx, func(ctx context.Context) error {
+			if atomic.AddInt32(&terms, 1) == 1 {
+				resp, err := cli.Get(ctx, "/election/lost/", clientv3.WithPrefix())
+				if err != nil || len(resp.Kvs) != 1 {
+					t.Errorf("get leader key: %v", err)
+					return err
+				}
+				cli.Revoke(ctx, clientv3.LeaseID(resp.Kvs[0].Lease))
+				<-ctx.Done()
+				return nil
+			}
+			// 会话丢失后重新当选，返回错误结束 Run
+			return errors.New("second term")
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "second term" || terms != 2 {
+			t.Fatalf("Run err = %v, terms = %d", err, terms)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("not re-elected after session loss")
+	}
+	if _, err := e.Leader(context.Background()); !errors.Is(err, ErrNoLeader) {
+		t.Fatalf("Leader() err = %v, want ErrNoLeader", err)
+	}
+}
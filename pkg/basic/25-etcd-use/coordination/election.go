@@ -0,0 +1,137 @@
+package coordination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// ErrNoLeader 当前没有 leader
+var ErrNoLeader = errors.New("coordination: no leader elected")
+
+// 会话丢失或 etcd 不可用后重新参选的退避时间
+const (
+	minRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
+)
+
+// Election 一个参选者，同一 prefix 下的参选者中同一时间最多一个是 leader
+type Election struct {
+	cli    *clientv3.Client
+	prefix string
+	id     string
+	opts   options
+}
+
+// NewElection 创建参选者，参选键为 prefix + "/" + 租约 ID；id 用来标识自己（如主机名），会作为 leader 的值写入 etcd
+func NewElection(cli *clientv3.Client, prefix, id string, opts ...Option) *Election {
+	return &Election{cli: cli, prefix: prefix, id: id, opts: newOptions(opts)}
+}
+
+// ID 参选者的标识
+func (e *Election) ID() string { return e.id }
+
+// Run 循环参选直到 ctx 取消：当选后调用 fn，fn 的 ctx 在失去 leader 身份（租约过期、被撤销）
+// 或 ctx 取消时被取消。fn 返回 nil 时主动让位后重新参选；返回错误时让位并把错误返回；
+// 会话丢失或 etcd 不可用时退避后重新参选。ctx 取消时返回 nil
+func (e *Election) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := minRetryBackoff
+	for {
+		err := e.runOnce(ctx, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var fnErr *leaderError
+		switch {
+		case errors.As(err, &fnErr):
+			return fnErr.err
+		case err == nil:
+			backoff = minRetryBackoff
+			continue
+		}
+		log.Printf("coordination: election %s: %v, retry in %v", e.prefix, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+// leaderError 包装 fn 返回的错误，与会话和 etcd 的错误区分
+type leaderError struct{ err error }
+
+func (e *leaderError) Error() string { return e.err.Error() }
+
+// runOnce 一轮完整的参选：建立会话、参选、执行 fn、让位
+func (e *Election) runOnce(ctx context.Context, fn func(ctx context.Context) error) error {
+	s, err := newSession(ctx, e.cli, e.opts)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// 会话丢失时参选和 fn 都要停下
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.Done():
+			cancel()
+		case <-sessionCtx.Done():
+		}
+	}()
+
+	el := concurrency.NewElection(s, e.prefix)
+	if err := el.Campaign(sessionCtx, e.id); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if sessionLost(s) {
+			return ErrSessionLost
+		}
+		return fmt.Errorf("campaign: %w", err)
+	}
+
+	fnErr := fn(sessionCtx)
+	if ctx.Err() == nil && sessionLost(s) {
+		return ErrSessionLost
+	}
+
+	resignCtx, cancelResign := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelResign()
+	if err := el.Resign(resignCtx); err != nil && fnErr == nil {
+		return fmt.Errorf("resign: %w", err)
+	}
+	if fnErr != nil {
+		return &leaderError{err: fnErr}
+	}
+	return nil
+}
+
+func sessionLost(s *concurrency.Session) bool {
+	select {
+	case <-s.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// Leader 返回当前 leader 的 id，没有 leader 时返回 ErrNoLeader
+func (e *Election) Leader(ctx context.Context) (string, error) {
+	resp, err := e.cli.Get(ctx, e.prefix+"/", clientv3.WithFirstCreate()...)
+	if err != nil {
+		return "", fmt.Errorf("coordination: get leader: %w", err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", ErrNoLeader
+	}
+	return string(resp.Kvs[0].Value), nil
+}
@@ -0,0 +1,201 @@
+// Package coordination 基于 etcd 租约和 revision 的分布式锁与选主工具，
+// 用于让定时任务、数据管道这类单例作业在多副本部署时只在一个副本上运行
+//
+//	err := coordination.WithLock(ctx, cli, "/locks/excel-pipeline", func(ctx context.Context) error {
+//		// ctx 在锁丢失（租约过期、被撤销）或调用方取消时被取消
+//		return runPipeline(ctx)
+//	})
+//
+//	e := coordination.NewElection(cli, "/election/casbin-reload", hostname)
+//	err := e.Run(ctx, func(ctx context.Context) error {
+//		// 只有 leader 会执行到这里，失去 leader 身份时 ctx 被取消
+//		return reloadLoop(ctx)
+//	})
+package coordination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// DefaultTTL 默认的租约时间，持有者崩溃后最多经过这么久锁或 leader 身份才会被释放
+const DefaultTTL = 10 * time.Second
+
+var (
+	// ErrLocked 锁已被其他会话持有（TryAcquire）
+	ErrLocked = errors.New("coordination: lock is held by another session")
+	// ErrSessionLost 租约过期或被撤销，锁或 leader 身份已经不再可靠
+	ErrSessionLost = errors.New("coordination: session lost")
+)
+
+// Option 锁和选主的选项
+type Option func(*options)
+
+type options struct {
+	ttl time.Duration
+}
+
+// WithTTL 设置租约时间，最小 1 秒；租约由后台 keepalive 续期
+func WithTTL(d time.Duration) Option {
+	return func(o *options) { o.ttl = d }
+}
+
+func newOptions(opts []Option) options {
+	o := options{ttl: DefaultTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ttl < time.Second {
+		o.ttl = time.Second
+	}
+	return o
+}
+
+// newSession 用调用方的 ctx 申请租约（etcd 不可用时 ctx 能让调用及时返回），
+// 之后的 keepalive 与 ctx 无关，由 session 自己负责直到 Close
+func newSession(ctx context.Context, cli *clientv3.Client, o options) (*concurrency.Session, error) {
+	ttl := int(o.ttl / time.Second)
+	resp, err := cli.Grant(ctx, int64(ttl))
+	if err != nil {
+		return nil, fmt.Errorf("coordination: grant lease: %w", err)
+	}
+	s, err := concurrency.NewSession(cli, concurrency.WithLease(resp.ID), concurrency.WithTTL(ttl))
+	if err != nil {
+		cli.Revoke(context.Background(), resp.ID)
+		return nil, fmt.Errorf("coordination: new session: %w", err)
+	}
+	return s, nil
+}
+
+// Lock 已获得的分布式锁，持有期间租约自动续期
+type Lock struct {
+	session *concurrency.Session
+	mutex   *concurrency.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Acquire 阻塞直到获得锁，ctx 取消时放弃等待并清理自己的排队键
+func Acquire(ctx context.Context, cli *clientv3.Client, key string, opts ...Option) (*Lock, error) {
+	return acquire(ctx, cli, key, newOptions(opts), false)
+}
+
+// TryAcquire 尝试获得锁，已被其他会话持有时立即返回 ErrLocked
+func TryAcquire(ctx context.Context, cli *clientv3.Client, key string, opts ...Option) (*Lock, error) {
+	return acquire(ctx, cli, key, newOptions(opts), true)
+}
+
+func acquire(ctx context.Context, cli *clientv3.Client, key string, o options, try bool) (*Lock, error) {
+	s, err := newSession(ctx, cli, o)
+	if err != nil {
+		return nil, err
+	}
+	m := concurrency.NewMutex(s, key)
+	if try {
+		err = m.TryLock(ctx)
+	} else {
+		err = m.Lock(ctx)
+	}
+	if err != nil {
+		s.Close()
+		if errors.Is(err, concurrency.ErrLocked) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("coordination: lock %s: %w", key, err)
+	}
+
+	l := &Lock{session: s, mutex: m}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-s.Done():
+		case <-l.ctx.Done():
+		}
+		l.cancel()
+	}()
+	return l, nil
+}
+
+// Key 锁在 etcd 中的实际键（前缀加租约 ID）
+func (l *Lock) Key() string { return l.mutex.Key() }
+
+// Fence 获得锁时的 revision，单调递增，可作为 fencing token 交给下游存储拒绝过期持有者的写入
+func (l *Lock) Fence() int64 { return l.mutex.Header().Revision }
+
+// Lost 返回一个在租约过期或被撤销时关闭的 channel，Release 不会关闭它
+func (l *Lock) Lost() <-chan struct{} { return l.session.Done() }
+
+// Context 返回一个在锁丢失或 Release 后被取消的 context，持锁执行的工作应使用它
+func (l *Lock) Context() context.Context { return l.ctx }
+
+// Release 释放锁并撤销租约，可以重复调用；锁已经丢失时返回 ErrSessionLost
+func (l *Lock) Release(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		select {
+		case <-l.session.Done():
+			err = ErrSessionLost
+		default:
+			if e := l.mutex.Unlock(ctx); e != nil {
+				err = fmt.Errorf("coordination: unlock %s: %w", l.Key(), e)
+			}
+		}
+		l.cancel()
+		l.session.Close()
+	})
+	return err
+}
+
+// WithLock 获得锁后执行 fn 并释放锁；fn 的 ctx 在调用方取消或锁丢失时被取消，
+// 执行期间锁丢失时返回的错误包含 ErrSessionLost
+func WithLock(ctx context.Context, cli *clientv3.Client, key string, fn func(ctx context.Context) error, opts ...Option) error {
+	l, err := Acquire(ctx, cli, key, opts...)
+	if err != nil {
+		return err
+	}
+	return l.run(ctx, fn)
+}
+
+// TryWithLock 与 WithLock 相同，但锁被占用时不等待，返回 ran=false；
+// 适合每个副本都会触发、但同一时间只需一个副本执行的作业
+func TryWithLock(ctx context.Context, cli *clientv3.Client, key string, fn func(ctx context.Context) error, opts ...Option) (ran bool, err error) {
+	l, err := TryAcquire(ctx, cli, key, opts...)
+	if errors.Is(err, ErrLocked) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, l.run(ctx, fn)
+}
+
+func (l *Lock) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	runCtx, cancel := mergeContext(ctx, l.ctx)
+	defer cancel()
+	err := fn(runCtx)
+
+	releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRelease()
+	if rerr := l.Release(releaseCtx); rerr != nil {
+		return errors.Join(err, rerr)
+	}
+	return err
+}
+
+// mergeContext 返回一个在 a 或 b 任意一个结束时被取消的 context
+func mergeContext(a, b context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(a)
+	stop := context.AfterFunc(b, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
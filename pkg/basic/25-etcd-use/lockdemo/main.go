@@ -0,0 +1,116 @@
+// lockdemo 演示单例作业在多副本下只运行一次，同时启动多个进程观察效果：
+//
+//	go run ./lockdemo -id a -mode leader   # 只有 leader 周期性执行作业（如 Casbin 策略重新加载）
+//	go run ./lockdemo -id b -mode leader   # 在 a 退出或被 kill 后接管
+//	go run ./lockdemo -id c -mode lock     # 每轮尝试加锁执行（如 Excel 解析管道），被占用时跳过
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example.com/etcd-use/coordination"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func main() {
+	hostname, _ := os.Hostname()
+	endpoint := flag.String("endpoint", "http://127.0.0.1:2379", "etcd 地址")
+	id := flag.String("id", fmt.Sprintf("%s-%d", hostname, os.Getpid()), "副本标识")
+	mode := flag.String("mode", "leader", "leader：选主后周期执行；lock：每轮抢锁执行")
+	key := flag.String("key", "/jobs/demo", "选主前缀或锁的键")
+	interval := flag.Duration("interval", 2*time.Second, "作业周期")
+	work := flag.Duration("work", time.Second, "每次作业耗时（模拟）")
+	ttl := flag.Duration("ttl", 5*time.Second, "租约时间，进程崩溃后最多经过这么久由其他副本接管")
+	flag.Parse()
+
+	// 创建etcd 客户端
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{*endpoint},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cli.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch *mode {
+	case "leader":
+		err = runLeader(ctx, cli, *id, *key, *interval, *work, *ttl)
+	case "lock":
+		err = runLock(ctx, cli, *id, *key, *interval, *work, *ttl)
+	default:
+		log.Fatalf("unknown mode %q", *mode)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runLeader 参选，当选后周期执行作业，失去 leader 身份时立即停止
+func runLeader(ctx context.Context, cli *clientv3.Client, id, prefix string, interval, work, ttl time.Duration) error {
+	e := coordination.NewElection(cli, prefix, id, coordination.WithTTL(ttl))
+	if leader, err := e.Leader(ctx); err == nil {
+		fmt.Printf("[%s] 当前 leader：%s，等待接管\n", id, leader)
+	}
+	return e.Run(ctx, func(ctx context.Context) error {
+		fmt.Printf("[%s] 当选 leader\n", id)
+		defer fmt.Printf("[%s] 不再是 leader\n", id)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := job(ctx, id, work); err != nil {
+				fmt.Printf("[%s] 作业中断：%v\n", id, err)
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// runLock 每轮都尝试加锁，拿到锁的副本执行作业，其余副本跳过本轮
+func runLock(ctx context.Context, cli *clientv3.Client, id, key string, interval, work, ttl time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ran, err := coordination.TryWithLock(ctx, cli, key, func(ctx context.Context) error {
+			return job(ctx, id, work)
+		}, coordination.WithTTL(ttl))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			fmt.Printf("[%s] 作业失败：%v\n", id, err)
+		case !ran:
+			fmt.Printf("[%s] 锁被其他副本持有，跳过本轮\n", id)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// job 模拟一次作业，ctx 被取消时中断
+func job(ctx context.Context, id string, work time.Duration) error {
+	fmt.Printf("[%s] %s 开始执行作业\n", id, time.Now().Format("15:04:05.000"))
+	select {
+	case <-time.After(work):
+		fmt.Printf("[%s] 作业完成\n", id)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,44 +1,128 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"example/rpc/client/service"
+	"example/rpc/client/userclient"
 	"flag"
+	"fmt"
 	"log"
-	"net/rpc"
+	"sync"
 	"time"
 )
 
 func main() {
+	configPath := flag.String("config", "", "客户端配置文件，如 client.json")
+	transport := flag.String("transport", userclient.TransportTCP, "传输方式: tcp、jsonrpc 或 http")
+	addr := flag.String("addr", "", "RPC Server 地址，默认 tcp 为 localhost:1234，jsonrpc 为 localhost:1235，http 为 localhost:1236")
+	timeout := flag.Duration("timeout", userclient.DefaultTimeout, "单次调用超时")
+	flag.Parse()
 
-	// 连接RPC Server
+	// 先读取配置文件，命令行显式指定的参数覆盖配置文件
+	cfg := userclient.Config{Transport: *transport, Addr: *addr, Timeout: userclient.Duration(*timeout)}
+	if *configPath != "" {
+		fileCfg, err := userclient.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg = *fileCfg
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "transport":
+				cfg.Transport = *transport
+			case "addr":
+				cfg.Addr = *addr
+			case "timeout":
+				cfg.Timeout = userclient.Duration(*timeout)
+			}
+		})
+	}
 
-	client, err := rpc.Dial("tcp", "localhost:1234")
+	if cfg.Addr == "" {
+		cfg.Addr = userclient.DefaultAddr[cfg.Transport]
+	}
+
+	// 连接RPC Server
+	client, err := userclient.Dial(cfg)
 	if err != nil {
 		log.Fatal("连接失败:", err)
 	}
-	// 调用远程服务
-	user := service.User{Id: 1, Name: "张三", Age: 20}
+	defer client.Close()
+	log.Printf("transport: %s, addr: %s", cfg.Transport, cfg.Addr)
 
-	var response bool
-	err = client.Call("UserServiceImpl.CreateUser", user, &response)
+	ctx := context.Background()
+
+	// 并发创建用户，服务端存储加锁，不会出现数据竞争
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			user := service.User{Id: id, Name: fmt.Sprintf("用户%c", 'A'+id-1), Age: 18 + id}
+			if err := client.CreateUser(ctx, user); err != nil {
+				log.Printf("创建用户 %d 失败: %v", id, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// 获取用户
+	user, err := client.GetUser(ctx, 1)
 	if err != nil {
 		log.Fatal("调用失败:", err)
 	}
+	log.Printf("获取用户: %+v", *user)
 
-	time.Sleep(2 * time.Second)
+	// 更新用户
+	user.Name = "张三"
+	if err := client.UpdateUser(ctx, *user); err != nil {
+		log.Fatal("调用失败:", err)
+	}
 
-	// 获取用户
-	var result service.User
-	err = client.Call("UserServiceImpl.GetUser", 1, &result)
+	// 删除用户
+	if err := client.DeleteUser(ctx, 2); err != nil {
+		log.Fatal("调用失败:", err)
+	}
+
+	// 分页获取用户
+	list, err := client.ListUsers(ctx, 0, 3)
 	if err != nil {
 		log.Fatal("调用失败:", err)
 	}
-	log.Printf("获取用户: %+v", result)
+	log.Printf("用户列表（共 %d 个）: %+v", list.Total, list.Users)
 
-	var name string
-	var age int
-	var sex bool
-	flag.StringVar(&name, "name", "张三", "姓名")
-	flag.IntVar(&age, "age", 15, "年龄")
-	flag.BoolVar(&sex, "sex", true, "性别")
+	// 错误处理：服务端返回的错误带有错误码，用 FromError 还原
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"创建重复用户", func() error { return client.CreateUser(ctx, service.User{Id: 1, Name: "重复", Age: 20}) }},
+		{"创建空名用户", func() error { return client.CreateUser(ctx, service.User{Id: 10, Name: "", Age: 20}) }},
+		{"更新非法年龄", func() error { return client.UpdateUser(ctx, service.User{Id: 11, Name: "李四", Age: 200}) }},
+		{"获取已删除用户", func() error { _, err := client.GetUser(ctx, 2); return err }},
+	}
+	for _, c := range calls {
+		err := c.call()
+		e, ok := service.FromError(err)
+		if !ok {
+			log.Printf("%s: 非业务错误 %v", c.name, err)
+			continue
+		}
+		switch {
+		case errors.Is(e, service.ErrUserNotFound):
+			log.Printf("%s: 用户不存在", c.name)
+		case e.Code == service.CodeInvalidArgument:
+			log.Printf("%s: 参数错误，字段 %s %s", c.name, e.Field, e.Message)
+		default:
+			log.Printf("%s: %s %s", c.name, e.Code, e.Message)
+		}
+	}
+
+	// 超时：ctx 的截止时间优先于配置的默认超时
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Microsecond)
+	defer cancel()
+	if _, err := client.GetUser(timeoutCtx, 1); errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("调用超时: %v", err)
+	}
 }
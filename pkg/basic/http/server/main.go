@@ -1,23 +1,43 @@
 package main
 
 import (
+	"context"
+	"example/pkg/basic/tlsconf"
+	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func helloWorld(w http.ResponseWriter, r *http.Request) {
 	// 设置响应头信息
 	w.Header().Set("Content-Type", "text/plain")
-	// 写入响应体
+	// 写入响应体，mTLS 时带上客户端证书的名称
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		fmt.Fprintf(w, "Hello, %s!", r.TLS.PeerCertificates[0].Subject.CommonName)
+		return
+	}
 	fmt.Fprintf(w, "Hello, World!")
 }
 
 func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	tlsCfg := tlsconf.Flags(flag.CommandLine)
+	flag.Parse()
+
 	// 注册处理器函数到路由"/"
-	http.HandleFunc("/", helloWorld)
-	// 监听并服务在8080端口
-	fmt.Println("Starting server at port 8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", helloWorld)
+
+	// 收到信号后优雅停机
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 配置了证书时使用 HTTPS
+	fmt.Printf("Starting server at %s (tls: %v, mtls: %v)\n", *addr, tlsCfg.Enabled(), tlsCfg.ClientCAFile != "")
+	if err := tlsconf.ListenAndServe(ctx, *addr, mux, *tlsCfg); err != nil {
 		fmt.Println(err)
 	}
 }
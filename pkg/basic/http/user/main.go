@@ -1,14 +1,35 @@
 package main
 
 import (
+	"example/pkg/basic/tlsconf"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 )
 
 func main() {
+	url := flag.String("url", "", "请求地址，默认 http://localhost:8080/，使用 TLS 时为 https://localhost:8080/")
+	tlsCfg := tlsconf.ClientFlags(flag.CommandLine)
+	flag.Parse()
+
+	client := http.DefaultClient
+	target := "http://localhost:8080/"
+	if tlsCfg.Enabled {
+		config, err := tlsCfg.TLSConfig()
+		if err != nil {
+			fmt.Println("Error loading TLS config:", err)
+			return
+		}
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: config, ForceAttemptHTTP2: true}}
+		target = "https://localhost:8080/"
+	}
+	if *url != "" {
+		target = *url
+	}
+
 	// 创建一个新的HTTP GET请求
-	resp, err := http.Get("http://localhost:8080/")
+	resp, err := client.Get(target)
 	if err != nil {
 		fmt.Println("Error fetching URL:", err)
 		return
@@ -16,12 +37,12 @@ func main() {
 	defer resp.Body.Close()
 
 	// 读取响应体
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		fmt.Println("Error reading response body:", err)
 		return
 	}
 
 	// 打印响应内容
-	fmt.Println("Response:", string(body))
+	fmt.Println("Response:", resp.Proto, string(body))
 }
@@ -1,26 +1,78 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"example/rpc/client/service"
+	"flag"
 	"log"
 	"net"
+	"net/http"
 	"net/rpc"
+
+	_ "github.com/go-sql-driver/mysql"
 )
 
 func main() {
+	addr := flag.String("addr", ":1234", "gob RPC 监听地址，为空时不启用")
+	jsonAddr := flag.String("json-addr", ":1235", "JSON-RPC 监听地址，为空时不启用")
+	httpAddr := flag.String("http-addr", ":1236", "HTTP 监听地址，提供 JSON-RPC 和 net/rpc HTTP 协议，为空时不启用")
+	dsn := flag.String("dsn", "", "MySQL DSN，如 root:123456@tcp(127.0.0.1:3306)/test，为空时使用内存存储")
+	flag.Parse()
+
+	// 选择存储
+	var repo service.UserRepository
+	if *dsn == "" {
+		repo = service.NewMemoryRepository()
+		log.Println("using memory repository")
+	} else {
+		db, err := sql.Open("mysql", *dsn)
+		if err != nil {
+			log.Fatal("open database error:", err)
+		}
+		defer db.Close()
+		if err := db.Ping(); err != nil {
+			log.Fatal("connect database error:", err)
+		}
+		repo, err = service.NewSQLRepository(context.Background(), db)
+		if err != nil {
+			log.Fatal("create table error:", err)
+		}
+		log.Println("using mysql repository")
+	}
 
-	// 注册服务
-	userService := &service.UserServiceImpl{
-		Users: make(map[int]*service.User),
+	// 注册服务，三种传输共用同一个服务实例
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(service.ServiceName, service.NewUserService(repo)); err != nil {
+		log.Fatal("register service error:", err)
 	}
-	rpc.Register(userService)
 
 	// 启动RPC 服务器
-	listener, err := net.Listen("tcp", ":1234")
-	if err != nil {
-		log.Fatal("ListenTCP error:", err)
+	errCh := make(chan error, 3)
+	if *addr != "" {
+		l, err := net.Listen("tcp", *addr)
+		if err != nil {
+			log.Fatal("ListenTCP error:", err)
+		}
+		log.Println("gob RPC server is running... ADDR:", *addr)
+		go func() { errCh <- serveTCP(srv, l) }()
+	}
+	if *jsonAddr != "" {
+		l, err := net.Listen("tcp", *jsonAddr)
+		if err != nil {
+			log.Fatal("ListenTCP error:", err)
+		}
+		log.Println("JSON-RPC server is running... ADDR:", *jsonAddr)
+		go func() { errCh <- serveJSON(srv, l) }()
+	}
+	if *httpAddr != "" {
+		log.Printf("HTTP server is running... ADDR: %s, JSON-RPC path: %s", *httpAddr, JSONRPCPath)
+		go func() { errCh <- http.ListenAndServe(*httpAddr, newHTTPHandler(srv)) }()
+	}
+	if *addr == "" && *jsonAddr == "" && *httpAddr == "" {
+		log.Fatal("no transport enabled")
 	}
 
-	log.Println("RPC server is running... PORT: 1234")
-	rpc.Accept(listener)
+	// 任一传输退出时结束进程
+	log.Fatal("serve error:", <-errCh)
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/rpc"
+	"regexp"
+)
+
+// 错误码
+const (
+	CodeInvalidArgument = "INVALID_ARGUMENT" // 参数校验失败
+	CodeNotFound        = "NOT_FOUND"        // 用户不存在
+	CodeAlreadyExists   = "ALREADY_EXISTS"   // 用户 ID 已存在
+	CodeInternal        = "INTERNAL"         // 存储出错
+)
+
+var (
+	// ErrUserNotFound 用户不存在
+	ErrUserNotFound = &Error{Code: CodeNotFound, Message: "user not found"}
+	// ErrUserExists 用户 ID 已存在
+	ErrUserExists = &Error{Code: CodeAlreadyExists, Message: "user already exists"}
+)
+
+// Error 带错误码的 RPC 错误
+// net/rpc 只把错误字符串传给客户端，客户端用 FromError 还原，再用 errors.Is 或 Code 判断：
+//
+//	if e, ok := service.FromError(err); ok && e.Code == service.CodeInvalidArgument { ... }
+//	if errors.Is(err, service.ErrUserNotFound) { ... } // 仅服务端
+type Error struct {
+	Code    string
+	Field   string // 校验失败的字段，可以为空
+	Message string
+}
+
+// errorPattern 解析 Error() 的输出，如 "INVALID_ARGUMENT(name): must not be empty"
+var errorPattern = regexp.MustCompile(`^([A-Z_]+)(?:\(([^)]*)\))?: (.*)$`)
+
+func (e *Error) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s(%s): %s", e.Code, e.Field, e.Message)
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Is 错误码相同即视为同一种错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// FromError 从服务端返回的错误中还原 *Error，其他错误（如网络错误）返回 false
+func FromError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	var serverErr rpc.ServerError
+	if !errors.As(err, &serverErr) {
+		return nil, false
+	}
+	m := errorPattern.FindStringSubmatch(string(serverErr))
+	if m == nil {
+		return nil, false
+	}
+	return &Error{Code: m[1], Field: m[2], Message: m[3]}, true
+}
+
+// invalidArgument 参数校验错误
+func invalidArgument(field, format string, args ...interface{}) error {
+	return &Error{Code: CodeInvalidArgument, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// internalError 存储错误，具体原因只记录在服务端日志
+func internalError(op string) error {
+	return &Error{Code: CodeInternal, Message: op + " failed"}
+}
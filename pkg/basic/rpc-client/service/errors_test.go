@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+)
+
+// newTestClient 通过内存连接调用 net/rpc 服务
+func newTestClient(t *testing.T, s *UserServiceImpl) *rpc.Client {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName(ServiceName, s); err != nil {
+		t.Fatal(err)
+	}
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+	client := rpc.NewClient(clientConn)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestFromErrorRoundTrip(t *testing.T) {
+	client := newTestClient(t, NewUserService(NewMemoryRepository()))
+	var ok bool
+	var user User
+
+	tests := []struct {
+		name string
+		call func() error
+		want *Error
+	}{
+		{
+			name: "invalid argument with field",
+			call: func() error { return client.Call(ServiceName+".CreateUser", &User{Id: 1}, &ok) },
+			want: &Error{Code: CodeInvalidArgument, Field: "name", Message: "must not be empty"},
+		},
+		{
+			name: "not found without field",
+			call: func() error { return client.Call(ServiceName+".GetUser", 1, &user) },
+			want: ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var serverErr rpc.ServerError
+			if !errors.As(err, &serverErr) {
+				t.Fatalf("err = %T %v, want rpc.ServerError", err, err)
+			}
+			e, ok := FromError(err)
+			if !ok || *e != *tt.want {
+				t.Errorf("FromError = %+v, %v, want %+v", e, ok, tt.want)
+			}
+			// 还原后可以和服务端的错误变量比较
+			if !errors.Is(e, &Error{Code: tt.want.Code}) {
+				t.Errorf("errors.Is(%v, code %s) = false", e, tt.want.Code)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	if e, ok := FromError(ErrUserExists); !ok || e != ErrUserExists {
+		t.Errorf("FromError(*Error) = %v, %v, want the same error", e, ok)
+	}
+	for _, err := range []error{
+		nil,
+		errors.New("connection refused"),
+		rpc.ErrShutdown,
+		rpc.ServerError("rpc: can't find method UserServiceImpl.Foo"),
+	} {
+		if e, ok := FromError(err); ok {
+			t.Errorf("FromError(%v) = %+v, want false", err, e)
+		}
+	}
+}
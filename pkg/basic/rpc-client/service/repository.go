@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// UserRepository 用户存储，找不到用户时返回 ErrUserNotFound，ID 重复时返回 ErrUserExists
+type UserRepository interface {
+	Get(ctx context.Context, id int) (*User, error)
+	// List 按 ID 升序分页返回用户和总数
+	List(ctx context.Context, offset, limit int) ([]User, int, error)
+	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id int) error
+}
+
+// MemoryRepository 内存存储，并发安全，进程退出后数据丢失
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	users map[int]User
+}
+
+// NewMemoryRepository 创建内存存储
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{users: make(map[int]User)}
+}
+
+// Get 获取用户
+func (r *MemoryRepository) Get(_ context.Context, id int) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &u, nil
+}
+
+// List 分页获取用户
+func (r *MemoryRepository) List(_ context.Context, offset, limit int) ([]User, int, error) {
+	r.mu.RLock()
+	ids := make([]int, 0, len(r.users))
+	for id := range r.users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	users := make([]User, 0, limit)
+	for i := offset; i < len(ids) && len(users) < limit; i++ {
+		users = append(users, r.users[ids[i]])
+	}
+	r.mu.RUnlock()
+	return users, len(ids), nil
+}
+
+// Create 创建用户
+func (r *MemoryRepository) Create(_ context.Context, user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.Id]; ok {
+		return ErrUserExists
+	}
+	r.users[user.Id] = *user
+	return nil
+}
+
+// Update 更新用户
+func (r *MemoryRepository) Update(_ context.Context, user *User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.Id]; !ok {
+		return ErrUserNotFound
+	}
+	r.users[user.Id] = *user
+	return nil
+}
+
+// Delete 删除用户
+func (r *MemoryRepository) Delete(_ context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
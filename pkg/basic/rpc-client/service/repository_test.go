@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryRepositoryList(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+	// 乱序插入，List 按 ID 升序返回
+	for _, id := range []int{5, 1, 4, 2, 3} {
+		if err := repo.Create(ctx, &User{Id: id, Name: "u", Age: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []int
+	}{
+		{name: "first page", offset: 0, limit: 2, want: []int{1, 2}},
+		{name: "middle page", offset: 2, limit: 2, want: []int{3, 4}},
+		{name: "last partial page", offset: 4, limit: 2, want: []int{5}},
+		{name: "offset past end", offset: 10, limit: 2, want: []int{}},
+		{name: "limit larger than total", offset: 0, limit: 100, want: []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, total, err := repo.List(ctx, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 5 {
+				t.Errorf("total = %d, want 5", total)
+			}
+			if got := userIds(users); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRepository(t *testing.T) {
+	testRepository(t, NewMemoryRepository())
+}
+
+// testRepository 检查 UserRepository 实现的公共约定，repo 必须为空
+func testRepository(t *testing.T, repo UserRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	alice := User{Id: 1, Name: "alice", Age: 20}
+	if err := repo.Create(ctx, &alice); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, &User{Id: 1, Name: "other"}); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Create duplicate = %v, want ErrUserExists", err)
+	}
+	if u, err := repo.Get(ctx, 1); err != nil || *u != alice {
+		t.Errorf("Get = %v, %v, want %v", u, err, alice)
+	}
+	if _, err := repo.Get(ctx, 2); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Get missing = %v, want ErrUserNotFound", err)
+	}
+
+	// 更新为相同的值也算成功
+	alice.Age = 21
+	for i := 0; i < 2; i++ {
+		if err := repo.Update(ctx, &alice); err != nil {
+			t.Fatalf("Update #%d: %v", i, err)
+		}
+	}
+	if u, _ := repo.Get(ctx, 1); u == nil || u.Age != 21 {
+		t.Errorf("Get after Update = %v, want age 21", u)
+	}
+	if err := repo.Update(ctx, &User{Id: 2, Name: "bob"}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Update missing = %v, want ErrUserNotFound", err)
+	}
+
+	for _, id := range []int{3, 2} {
+		if err := repo.Create(ctx, &User{Id: id, Name: "u"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users, total, err := repo.List(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || !reflect.DeepEqual(userIds(users), []int{2}) {
+		t.Errorf("List(1, 1) = %v, %d, want [2], 3", userIds(users), total)
+	}
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(ctx, 1); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Delete twice = %v, want ErrUserNotFound", err)
+	}
+	if _, total, _ := repo.List(ctx, 0, 10); total != 2 {
+		t.Errorf("total after Delete = %d, want 2", total)
+	}
+}
+
+// userIds 返回用户 ID 列表
+func userIds(users []User) []int {
+	ids := make([]int, len(users))
+	for i, u := range users {
+		ids[i] = u.Id
+	}
+	return ids
+}
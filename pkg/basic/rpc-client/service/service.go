@@ -1,14 +1,31 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// ServiceName 注册的服务名，方法名为 ServiceName + ".GetUser" 等
+const ServiceName = "UserServiceImpl"
+
+// 分页参数
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+	maxNameLength    = 50
+	maxAge           = 150
 )
 
 // 用户服务接口
 type UserService interface {
 	GetUser(id int, user *User) error
+	ListUsers(req ListRequest, resp *ListResponse) error
 	CreateUser(user *User, response *bool) error
+	UpdateUser(user *User, response *bool) error
+	DeleteUser(id int, response *bool) error
 }
 
 // 用户结构体
@@ -18,27 +35,133 @@ type User struct {
 	Age  int    `json:"age"`
 }
 
-// 用户服务实现
+// ListRequest 分页查询参数，Limit 为 0 时使用 DefaultListLimit
+type ListRequest struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// ListResponse 分页查询结果，Total 为用户总数
+type ListResponse struct {
+	Users []User `json:"users"`
+	Total int    `json:"total"`
+}
+
+// 用户服务实现，存储由 UserRepository 决定，方法可以被并发调用
 type UserServiceImpl struct {
-	Users map[int]*User
+	repo UserRepository
 }
 
-var ErrUserNotFound = errors.New("user not found")
+// NewUserService 创建用户服务
+func NewUserService(repo UserRepository) *UserServiceImpl {
+	return &UserServiceImpl{repo: repo}
+}
 
 // 获取用户
 func (s *UserServiceImpl) GetUser(id int, user *User) error {
-	if u, ok := s.Users[id]; ok {
-		*user = *u
-		log.Printf("RPC client Get user: %v", user)
-		return nil
+	if err := validateId(id); err != nil {
+		return err
+	}
+	u, err := s.repo.Get(context.Background(), id)
+	if err != nil {
+		return s.convertError("get user", err)
 	}
-	return ErrUserNotFound
+	*user = *u
+	log.Printf("get user: %v", user)
+	return nil
+}
+
+// 分页获取用户
+func (s *UserServiceImpl) ListUsers(req ListRequest, resp *ListResponse) error {
+	if req.Offset < 0 {
+		return invalidArgument("offset", "must not be negative")
+	}
+	if req.Limit < 0 || req.Limit > MaxListLimit {
+		return invalidArgument("limit", "must be between 0 and %d", MaxListLimit)
+	}
+	if req.Limit == 0 {
+		req.Limit = DefaultListLimit
+	}
+
+	users, total, err := s.repo.List(context.Background(), req.Offset, req.Limit)
+	if err != nil {
+		return s.convertError("list users", err)
+	}
+	resp.Users = users
+	resp.Total = total
+	return nil
 }
 
 // 创建用户
 func (s *UserServiceImpl) CreateUser(user *User, response *bool) error {
-	s.Users[user.Id] = user
+	if err := validateUser(user); err != nil {
+		return err
+	}
+	if err := s.repo.Create(context.Background(), user); err != nil {
+		return s.convertError("create user", err)
+	}
 	log.Printf("create user: %v", user)
 	*response = true
 	return nil
 }
+
+// 更新用户
+func (s *UserServiceImpl) UpdateUser(user *User, response *bool) error {
+	if err := validateUser(user); err != nil {
+		return err
+	}
+	if err := s.repo.Update(context.Background(), user); err != nil {
+		return s.convertError("update user", err)
+	}
+	log.Printf("update user: %v", user)
+	*response = true
+	return nil
+}
+
+// 删除用户
+func (s *UserServiceImpl) DeleteUser(id int, response *bool) error {
+	if err := validateId(id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(context.Background(), id); err != nil {
+		return s.convertError("delete user", err)
+	}
+	log.Printf("delete user: %d", id)
+	*response = true
+	return nil
+}
+
+// convertError 存储返回的 *Error 原样返回，其他错误记录日志后转为 INTERNAL，不把数据库细节暴露给客户端
+func (s *UserServiceImpl) convertError(op string, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	log.Printf("%s: %v", op, err)
+	return internalError(op)
+}
+
+// validateId 校验用户 ID
+func validateId(id int) error {
+	if id <= 0 {
+		return invalidArgument("id", "must be positive")
+	}
+	return nil
+}
+
+// validateUser 校验用户字段
+func validateUser(user *User) error {
+	if err := validateId(user.Id); err != nil {
+		return err
+	}
+	if strings.TrimSpace(user.Name) == "" {
+		return invalidArgument("name", "must not be empty")
+	}
+	if utf8.RuneCountInString(user.Name) > maxNameLength {
+		return invalidArgument("name", "must be at most %d characters", maxNameLength)
+	}
+	if user.Age < 0 || user.Age > maxAge {
+		return invalidArgument("age", "must be between 0 and %d", maxAge)
+	}
+	return nil
+}
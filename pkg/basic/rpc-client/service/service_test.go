@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	s := NewUserService(NewMemoryRepository())
+	var ok bool
+	var user User
+	var list ListResponse
+
+	tests := []struct {
+		name      string
+		call      func() error
+		wantField string
+	}{
+		{name: "get zero id", call: func() error { return s.GetUser(0, &user) }, wantField: "id"},
+		{name: "delete negative id", call: func() error { return s.DeleteUser(-1, &ok) }, wantField: "id"},
+		{name: "create zero id", call: func() error { return s.CreateUser(&User{Name: "a"}, &ok) }, wantField: "id"},
+		{name: "create blank name", call: func() error { return s.CreateUser(&User{Id: 1, Name: "  "}, &ok) }, wantField: "name"},
+		{name: "create long name", call: func() error {
+			return s.CreateUser(&User{Id: 1, Name: strings.Repeat("名", maxNameLength+1)}, &ok)
+		}, wantField: "name"},
+		{name: "create negative age", call: func() error { return s.CreateUser(&User{Id: 1, Name: "a", Age: -1}, &ok) }, wantField: "age"},
+		{name: "update age too large", call: func() error { return s.UpdateUser(&User{Id: 1, Name: "a", Age: maxAge + 1}, &ok) }, wantField: "age"},
+		{name: "list negative offset", call: func() error { return s.ListUsers(ListRequest{Offset: -1}, &list) }, wantField: "offset"},
+		{name: "list limit too large", call: func() error { return s.ListUsers(ListRequest{Limit: MaxListLimit + 1}, &list) }, wantField: "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			e, ok := FromError(err)
+			if !ok || e.Code != CodeInvalidArgument || e.Field != tt.wantField {
+				t.Errorf("err = %v, want %s(%s)", err, CodeInvalidArgument, tt.wantField)
+			}
+		})
+	}
+
+	// 名称按字符计算长度
+	if err := s.CreateUser(&User{Id: 1, Name: strings.Repeat("名", maxNameLength)}, &ok); err != nil {
+		t.Errorf("CreateUser with %d characters: %v", maxNameLength, err)
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	s := NewUserService(NewMemoryRepository())
+	var ok bool
+
+	if err := s.CreateUser(&User{Id: 1, Name: "alice", Age: 20}, &ok); err != nil || !ok {
+		t.Fatalf("CreateUser = %v, %v", ok, err)
+	}
+	if err := s.CreateUser(&User{Id: 1, Name: "alice"}, &ok); !errors.Is(err, ErrUserExists) {
+		t.Errorf("CreateUser duplicate = %v, want ErrUserExists", err)
+	}
+	if err := s.UpdateUser(&User{Id: 1, Name: "alice", Age: 21}, &ok); err != nil {
+		t.Fatal(err)
+	}
+	var user User
+	if err := s.GetUser(1, &user); err != nil || user.Age != 21 {
+		t.Errorf("GetUser = %+v, %v, want age 21", user, err)
+	}
+	if err := s.DeleteUser(1, &ok); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.GetUser(1, &user); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUser after delete = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestListUsersDefaultLimit(t *testing.T) {
+	s := NewUserService(NewMemoryRepository())
+	var ok bool
+	for id := 1; id <= DefaultListLimit+5; id++ {
+		if err := s.CreateUser(&User{Id: id, Name: "u"}, &ok); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var resp ListResponse
+	if err := s.ListUsers(ListRequest{}, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Users) != DefaultListLimit || resp.Total != DefaultListLimit+5 {
+		t.Errorf("ListUsers = %d users, total %d, want %d, %d", len(resp.Users), resp.Total, DefaultListLimit, DefaultListLimit+5)
+	}
+}
+
+func TestConcurrentCreateUser(t *testing.T) {
+	s := NewUserService(NewMemoryRepository())
+
+	// 每个 ID 由 4 个 goroutine 同时创建，只能有一个成功
+	const users, callers = 50, 4
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := make(map[int]int)
+	for id := 1; id <= users; id++ {
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				var ok bool
+				err := s.CreateUser(&User{Id: id, Name: "u"}, &ok)
+				switch {
+				case err == nil:
+					mu.Lock()
+					created[id]++
+					mu.Unlock()
+				case !errors.Is(err, ErrUserExists):
+					t.Errorf("CreateUser(%d) = %v", id, err)
+				}
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	for id := 1; id <= users; id++ {
+		if created[id] != 1 {
+			t.Errorf("user %d created %d times, want 1", id, created[id])
+		}
+	}
+	var resp ListResponse
+	if err := s.ListUsers(ListRequest{Limit: MaxListLimit}, &resp); err != nil || resp.Total != users {
+		t.Errorf("ListUsers total = %d, %v, want %d", resp.Total, err, users)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// SQLRepository 基于 database/sql 的存储，只使用 ? 占位符和标准 SQL，MySQL、SQLite 都可以使用
+// 驱动由调用方导入，如 _ "github.com/go-sql-driver/mysql"
+type SQLRepository struct {
+	db *sql.DB
+}
+
+// NewSQLRepository 创建 SQL 存储，并在表不存在时建表
+func NewSQLRepository(ctx context.Context, db *sql.DB) (*SQLRepository, error) {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS rpc_users (
+			id INT PRIMARY KEY,
+			name VARCHAR(50) NOT NULL,
+			age INT NOT NULL
+		)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLRepository{db: db}, nil
+}
+
+// Get 获取用户
+func (r *SQLRepository) Get(ctx context.Context, id int) (*User, error) {
+	var u User
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, age FROM rpc_users WHERE id = ?", id).
+		Scan(&u.Id, &u.Name, &u.Age)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// List 分页获取用户
+func (r *SQLRepository) List(ctx context.Context, offset, limit int) ([]User, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM rpc_users").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, age FROM rpc_users ORDER BY id LIMIT ? OFFSET ?", limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]User, 0, limit)
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.Id, &u.Name, &u.Age); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, u)
+	}
+	return users, total, rows.Err()
+}
+
+// Create 创建用户，ID 由主键保证唯一
+func (r *SQLRepository) Create(ctx context.Context, user *User) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO rpc_users (id, name, age) VALUES (?, ?, ?)", user.Id, user.Name, user.Age)
+	if err != nil {
+		// 各驱动的主键冲突错误不同，插入失败后查一次判断是否重复
+		if _, getErr := r.Get(ctx, user.Id); getErr == nil {
+			return ErrUserExists
+		}
+		return err
+	}
+	return nil
+}
+
+// Update 更新用户
+func (r *SQLRepository) Update(ctx context.Context, user *User) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE rpc_users SET name = ?, age = ? WHERE id = ?", user.Name, user.Age, user.Id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(ctx, res, user.Id)
+}
+
+// Delete 删除用户
+func (r *SQLRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM rpc_users WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return r.checkAffected(ctx, res, id)
+}
+
+// checkAffected 没有影响任何行时判断用户是否存在
+// MySQL 默认只统计实际改变的行，更新为相同的值时影响行数为 0，需要再查一次
+func (r *SQLRepository) checkAffected(ctx context.Context, res sql.Result, id int) error {
+	n, err := res.RowsAffected()
+	if err != nil || n > 0 {
+		return err
+	}
+	_, err = r.Get(ctx, id)
+	return err
+}
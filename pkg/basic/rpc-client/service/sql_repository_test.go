@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+)
+
+// openTestDB 打开临时 SQLite 数据库
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "users.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLRepository(t *testing.T) {
+	db := openTestDB(t)
+	repo, err := NewSQLRepository(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRepository(t, repo)
+
+	// 表已存在时可以再次创建
+	if _, err := NewSQLRepository(context.Background(), db); err != nil {
+		t.Errorf("NewSQLRepository on existing table: %v", err)
+	}
+}
+
+func TestSQLRepositoryError(t *testing.T) {
+	db := openTestDB(t)
+	repo, err := NewSQLRepository(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// 数据库错误不能被当作用户不存在或已存在，服务转为 INTERNAL
+	s := NewUserService(repo)
+	var ok bool
+	err = s.CreateUser(&User{Id: 1, Name: "alice"}, &ok)
+	if e, _ := FromError(err); e == nil || e.Code != CodeInternal {
+		t.Errorf("CreateUser on closed db = %v, want %s", err, CodeInternal)
+	}
+	var user User
+	err = s.GetUser(1, &user)
+	if e, _ := FromError(err); e == nil || e.Code != CodeInternal {
+		t.Errorf("GetUser on closed db = %v, want %s", err, CodeInternal)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+)
+
+// JSONRPCPath HTTP 上 JSON-RPC 的路径
+const JSONRPCPath = "/jsonrpc"
+
+// maxRequestBody HTTP 请求体上限
+const maxRequestBody = 1 << 20
+
+// serveTCP 在 TCP 上提供 gob 编码的 RPC，Go 客户端使用 rpc.Dial 连接
+func serveTCP(srv *rpc.Server, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.ServeConn(conn)
+	}
+}
+
+// serveJSON 在 TCP 上提供 JSON-RPC 1.0，每行一个请求，任何语言都可以直接连接：
+//
+//	echo '{"method":"UserServiceImpl.GetUser","params":[1],"id":1}' | nc localhost 1235
+func serveJSON(srv *rpc.Server, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// newHTTPHandler 返回 HTTP 处理器
+// POST /jsonrpc 为 JSON-RPC 1.0，一次请求一个调用：
+//
+//	curl -d '{"method":"UserServiceImpl.GetUser","params":[1],"id":1}' localhost:1236/jsonrpc
+//
+// rpc.DefaultRPCPath 保留 net/rpc 的 HTTP 协议，Go 客户端使用 rpc.DialHTTP 连接
+func newHTTPHandler(srv *rpc.Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(rpc.DefaultRPCPath, srv)
+	mux.HandleFunc(JSONRPCPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var resp bytes.Buffer
+		codec := jsonrpc.NewServerCodec(&httpConn{
+			Reader: http.MaxBytesReader(w, r.Body, maxRequestBody),
+			Writer: &resp,
+		})
+		// 业务错误在 JSON-RPC 响应的 error 字段中返回，HTTP 状态码为 200；
+		// 方法不存在、参数无法解码时 ServeRequest 返回错误，但已经写入了带 id 的错误响应，同样原样返回
+		if err := srv.ServeRequest(codec); err != nil && resp.Len() == 0 {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resp.Bytes())
+	})
+	return mux
+}
+
+// httpConn 把请求体和响应缓冲组合成 jsonrpc 编解码器需要的连接
+type httpConn struct {
+	io.Reader
+	io.Writer
+}
+
+func (c *httpConn) Close() error { return nil }
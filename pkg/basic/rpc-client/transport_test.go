@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"example/rpc/client/service"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"strings"
+	"testing"
+)
+
+// jsonResponse JSON-RPC 1.0 响应
+type jsonResponse struct {
+	Id     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+func TestHTTPJSONRPC(t *testing.T) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(service.ServiceName, service.NewUserService(service.NewMemoryRepository())); err != nil {
+		t.Fatal(err)
+	}
+	handler := newHTTPHandler(srv)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantError  string // JSON-RPC 响应的 error 字段，为空时期望成功
+	}{
+		{
+			name:       "success",
+			method:     http.MethodPost,
+			body:       `{"method":"UserServiceImpl.CreateUser","params":[{"id":1,"name":"alice","age":20}],"id":1}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "business error",
+			method:     http.MethodPost,
+			body:       `{"method":"UserServiceImpl.GetUser","params":[2],"id":2}`,
+			wantStatus: http.StatusOK,
+			wantError:  "NOT_FOUND: user not found",
+		},
+		{
+			name:       "unknown method",
+			method:     http.MethodPost,
+			body:       `{"method":"UserServiceImpl.Foo","params":[1],"id":3}`,
+			wantStatus: http.StatusOK,
+			wantError:  "rpc: can't find method UserServiceImpl.Foo",
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			body:       `{"method":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not post",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, JSONRPCPath, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp jsonResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode %s: %v", rec.Body, err)
+			}
+			if tt.wantError == "" {
+				if resp.Error != nil {
+					t.Errorf("error = %v, want nil", resp.Error)
+				}
+				return
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("error = %v, want %q", resp.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package userclient UserService 客户端，支持 gob、JSON-RPC 和 HTTP 三种传输，调用都可以通过 ctx 设置超时
+package userclient
+
+import (
+	"context"
+	"example/rpc/client/service"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+)
+
+// caller 一种传输方式的调用实现
+type caller interface {
+	call(ctx context.Context, method string, args, reply interface{}) error
+	close() error
+}
+
+// Client UserService 客户端，可以被并发使用
+// 服务端返回的业务错误为 rpc.ServerError，用 service.FromError 还原错误码
+type Client struct {
+	timeout time.Duration
+	caller  caller
+}
+
+// Dial 根据配置连接服务端，http 传输不需要预先建立连接
+func Dial(cfg Config) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	timeout := time.Duration(cfg.Timeout)
+
+	var c caller
+	switch cfg.Transport {
+	case TransportHTTP:
+		c = newHTTPCaller(cfg.Addr)
+	default:
+		conn, err := net.DialTimeout("tcp", cfg.Addr, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("dial %s: %w", cfg.Addr, err)
+		}
+		if cfg.Transport == TransportJSONRPC {
+			c = &rpcCaller{client: jsonrpc.NewClient(conn)}
+		} else {
+			c = &rpcCaller{client: rpc.NewClient(conn)}
+		}
+	}
+	return &Client{timeout: timeout, caller: c}, nil
+}
+
+// Call 调用服务方法，method 不带服务名，如 "GetUser"
+// ctx 没有截止时间时使用配置的默认超时
+func (c *Client) Call(ctx context.Context, method string, args, reply interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return c.caller.call(ctx, service.ServiceName+"."+method, args, reply)
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.caller.close()
+}
+
+// GetUser 获取用户
+func (c *Client) GetUser(ctx context.Context, id int) (*service.User, error) {
+	var user service.User
+	if err := c.Call(ctx, "GetUser", id, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListUsers 分页获取用户
+func (c *Client) ListUsers(ctx context.Context, offset, limit int) (*service.ListResponse, error) {
+	var resp service.ListResponse
+	if err := c.Call(ctx, "ListUsers", service.ListRequest{Offset: offset, Limit: limit}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateUser 创建用户
+func (c *Client) CreateUser(ctx context.Context, user service.User) error {
+	var ok bool
+	return c.Call(ctx, "CreateUser", user, &ok)
+}
+
+// UpdateUser 更新用户
+func (c *Client) UpdateUser(ctx context.Context, user service.User) error {
+	var ok bool
+	return c.Call(ctx, "UpdateUser", user, &ok)
+}
+
+// DeleteUser 删除用户
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	var ok bool
+	return c.Call(ctx, "DeleteUser", id, &ok)
+}
+
+// rpcCaller 基于长连接的 net/rpc 客户端，gob 和 JSON-RPC 只是编解码器不同
+type rpcCaller struct {
+	client *rpc.Client
+}
+
+// call 超时后立即返回 ctx 的错误，请求已经发出时服务端仍会执行完，响应到达后被丢弃
+func (c *rpcCaller) call(ctx context.Context, method string, args, reply interface{}) error {
+	call := c.client.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return fmt.Errorf("call %s: %w", method, ctx.Err())
+	}
+}
+
+func (c *rpcCaller) close() error {
+	return c.client.Close()
+}
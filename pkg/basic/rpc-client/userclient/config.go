@@ -0,0 +1,94 @@
+package userclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// 传输方式
+const (
+	TransportTCP     = "tcp"     // gob over TCP，仅 Go 客户端
+	TransportJSONRPC = "jsonrpc" // JSON-RPC 1.0 over TCP
+	TransportHTTP    = "http"    // JSON-RPC 1.0 over HTTP POST
+)
+
+// 默认配置
+const (
+	DefaultTimeout  = 3 * time.Second
+	defaultJSONPath = "/jsonrpc"
+)
+
+// DefaultAddr 各传输方式的默认服务端地址，与服务端的默认监听端口一致
+var DefaultAddr = map[string]string{
+	TransportTCP:     "localhost:1234",
+	TransportJSONRPC: "localhost:1235",
+	TransportHTTP:    "localhost:1236",
+}
+
+// Config 客户端配置，可以从 JSON 文件读取：
+//
+//	{"transport": "http", "addr": "localhost:1236", "timeout": "2s"}
+type Config struct {
+	Transport string   `json:"transport"` // tcp、jsonrpc 或 http，默认 tcp
+	Addr      string   `json:"addr"`      // 服务端地址，默认见 DefaultAddr，http 传输可以是完整 URL，如 http://localhost:1236/jsonrpc
+	Timeout   Duration `json:"timeout"`   // 连接和单次调用的默认超时，ctx 带有截止时间时以 ctx 为准
+}
+
+// Duration 支持 "2s"、"500ms" 格式的 JSON 时长
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"2s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// LoadConfig 从 JSON 文件读取配置
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// validate 检查配置并填充默认值
+func (c *Config) validate() error {
+	if c.Transport == "" {
+		c.Transport = TransportTCP
+	}
+	switch c.Transport {
+	case TransportTCP, TransportJSONRPC, TransportHTTP:
+	default:
+		return fmt.Errorf("unknown transport %q, must be %s, %s or %s", c.Transport, TransportTCP, TransportJSONRPC, TransportHTTP)
+	}
+	if c.Addr == "" {
+		c.Addr = DefaultAddr[c.Transport]
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = Duration(DefaultTimeout)
+	}
+	return nil
+}
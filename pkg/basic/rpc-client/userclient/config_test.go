@@ -0,0 +1,50 @@
+package userclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			cfg:  Config{},
+			want: Config{Transport: TransportTCP, Addr: "localhost:1234", Timeout: Duration(DefaultTimeout)},
+		},
+		{
+			name: "jsonrpc default addr",
+			cfg:  Config{Transport: TransportJSONRPC},
+			want: Config{Transport: TransportJSONRPC, Addr: "localhost:1235", Timeout: Duration(DefaultTimeout)},
+		},
+		{
+			name: "http default addr",
+			cfg:  Config{Transport: TransportHTTP, Timeout: Duration(time.Second)},
+			want: Config{Transport: TransportHTTP, Addr: "localhost:1236", Timeout: Duration(time.Second)},
+		},
+		{
+			name: "explicit addr",
+			cfg:  Config{Transport: TransportHTTP, Addr: "http://example.com/jsonrpc"},
+			want: Config{Transport: TransportHTTP, Addr: "http://example.com/jsonrpc", Timeout: Duration(DefaultTimeout)},
+		},
+		{name: "unknown transport", cfg: Config{Transport: "udp"}, wantErr: true},
+		{name: "negative timeout", cfg: Config{Timeout: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg != tt.want {
+				t.Errorf("cfg = %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
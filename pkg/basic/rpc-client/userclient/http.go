@@ -0,0 +1,89 @@
+package userclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/rpc"
+	"strings"
+	"sync/atomic"
+)
+
+// httpCaller 每次调用发送一个 JSON-RPC 1.0 的 HTTP POST 请求
+type httpCaller struct {
+	url    string
+	client *http.Client
+	seq    atomic.Uint64
+}
+
+// jsonRequest JSON-RPC 1.0 请求，params 固定为单元素数组
+type jsonRequest struct {
+	Method string         `json:"method"`
+	Params [1]interface{} `json:"params"`
+	Id     uint64         `json:"id"`
+}
+
+// jsonResponse JSON-RPC 1.0 响应，error 为字符串或 null
+type jsonResponse struct {
+	Id     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// newHTTPCaller addr 没有协议前缀时补全为 http://<addr>/jsonrpc
+func newHTTPCaller(addr string) *httpCaller {
+	url := addr
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + strings.TrimSuffix(addr, "/") + defaultJSONPath
+	}
+	return &httpCaller{url: url, client: &http.Client{}}
+}
+
+// call 超时通过请求的 ctx 控制，超时后连接被关闭
+func (c *httpCaller) call(ctx context.Context, method string, args, reply interface{}) error {
+	body, err := json.Marshal(jsonRequest{Method: method, Params: [1]interface{}{args}, Id: c.seq.Add(1)})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("call %s: http status %d: %s", method, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var result jsonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if result.Error != nil {
+		msg, ok := result.Error.(string)
+		if !ok {
+			return errors.New("invalid error in response")
+		}
+		// 与 net/rpc 客户端一致，业务错误返回 rpc.ServerError
+		return rpc.ServerError(msg)
+	}
+	if err := json.Unmarshal(result.Result, reply); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+	return nil
+}
+
+func (c *httpCaller) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
@@ -1,40 +1,97 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"example/pkg/basic/tcp/tcpserver"
+	"example/pkg/basic/tlsconf"
+	"flag"
 	"fmt"
-	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
-func handleConnection(conn net.Conn) {
-	defer conn.Close()
-	for {
-		message, err := bufio.NewReader(conn).ReadString('\n')
-		if err != nil {
-			fmt.Println("Error: ", err)
-			return
-		}
-		fmt.Print("Message Received:", string(message))
-		conn.Write([]byte("Message received.\n"))
+// echoHandler 回显收到的消息
+type echoHandler struct{}
+
+func (echoHandler) OnConnect(c *tcpserver.Conn) {
+	fmt.Printf("Connection %d from %s\n", c.ID(), c.RemoteAddr())
+}
+
+func (echoHandler) OnMessage(c *tcpserver.Conn, msg []byte) {
+	from := fmt.Sprint(c.ID())
+	// mTLS 时用客户端证书标识对端
+	if state, ok := c.TLSState(); ok && len(state.PeerCertificates) > 0 {
+		from += " (" + state.PeerCertificates[0].Subject.CommonName + ")"
+	}
+	fmt.Printf("Message Received from %s: %s\n", from, msg)
+	if err := c.Send(append([]byte("Message received: "), msg...)); err != nil {
+		fmt.Println("Error sending: ", err)
+	}
+}
+
+func (echoHandler) OnClose(c *tcpserver.Conn, err error) {
+	fmt.Printf("Connection %d closed: %v\n", c.ID(), err)
+}
+
+// newCodec 根据名称创建分帧协议，客户端需要使用相同的协议
+func newCodec(name string) (tcpserver.Codec, error) {
+	switch name {
+	case "line":
+		return tcpserver.NewLineCodec(0), nil
+	case "length":
+		return tcpserver.NewLengthCodec(0), nil
+	default:
+		return nil, fmt.Errorf("unknown codec %q, must be line or length", name)
 	}
 }
 
 func main() {
-	listener, err := net.Listen("tcp", ":8080")
+	addr := flag.String("addr", ":8080", "监听地址")
+	codecName := flag.String("codec", "line", "分帧协议: line 或 length")
+	maxConns := flag.Int("max-conns", 100, "最大连接数")
+	idleTimeout := flag.Duration("idle-timeout", 5*time.Minute, "空闲超时")
+	tlsCfg := tlsconf.Flags(flag.CommandLine)
+	flag.Parse()
+
+	codec, err := newCodec(*codecName)
 	if err != nil {
-		fmt.Println("Error starting TCP server: ", err)
+		fmt.Println("Error: ", err)
 		return
 	}
-	defer listener.Close()
-
-	fmt.Println("Server listening on port 8080")
+	cfg := tcpserver.Config{
+		Addr:        *addr,
+		Codec:       codec,
+		MaxConns:    *maxConns,
+		IdleTimeout: *idleTimeout,
+	}
+	if tlsCfg.Enabled() {
+		if cfg.TLSConfig, err = tlsconf.ServerConfig(*tlsCfg); err != nil {
+			fmt.Println("Error loading TLS config: ", err)
+			return
+		}
+	}
+	srv := tcpserver.New(cfg, echoHandler{})
 
-	for {
-		conn, err := listener.Accept()
-		if err != nil {
-			fmt.Println("Error accepting connection: ", err)
-			continue
+	go func() {
+		fmt.Printf("Server listening on %s (tls: %v, mtls: %v)\n", *addr, tlsCfg.Enabled(), tlsCfg.ClientCAFile != "")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, tcpserver.ErrServerClosed) {
+			fmt.Println("Error starting TCP server: ", err)
+			os.Exit(1)
 		}
-		go handleConnection(conn)
+	}()
+
+	// 收到信号后优雅停机
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+
+	fmt.Println("Shutting down...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Println("Error shutting down: ", err)
 	}
 }
@@ -2,26 +2,69 @@ package main
 
 import (
 	"bufio"
+	"crypto/tls"
+	"example/pkg/basic/tcp/tcpserver"
+	"example/pkg/basic/tlsconf"
+	"flag"
 	"fmt"
 	"net"
 	"os"
+	"strings"
 )
 
 func main() {
-	conn, err := net.Dial("tcp", "localhost:8080")
+	addr := flag.String("addr", "localhost:8080", "服务端地址")
+	codecName := flag.String("codec", "line", "分帧协议: line 或 length，需要与服务端一致")
+	tlsCfg := tlsconf.ClientFlags(flag.CommandLine)
+	flag.Parse()
+
+	var codec tcpserver.Codec = tcpserver.NewLineCodec(0)
+	if *codecName == "length" {
+		codec = tcpserver.NewLengthCodec(0)
+	}
+
+	var conn net.Conn
+	var err error
+	if tlsCfg.Enabled {
+		var config *tls.Config
+		if config, err = tlsCfg.TLSConfig(); err != nil {
+			fmt.Println("Error loading TLS config: ", err)
+			return
+		}
+		conn, err = tls.Dial("tcp", *addr, config)
+	} else {
+		conn, err = net.Dial("tcp", *addr)
+	}
 	if err != nil {
 		fmt.Println("Error connecting to server: ", err)
 		return
 	}
 	defer conn.Close()
 
+	// 连接上的 Reader 和 Writer 只创建一次，避免丢失已缓冲的字节
+	connReader := bufio.NewReader(conn)
+	connWriter := bufio.NewWriter(conn)
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("Text to send: ")
-		text, _ := reader.ReadString('\n')
-		fmt.Fprintf(conn, text+"\n")
+		text, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if err := codec.Encode(connWriter, []byte(strings.TrimRight(text, "\r\n"))); err != nil {
+			fmt.Println("Error sending: ", err)
+			return
+		}
+		if err := connWriter.Flush(); err != nil {
+			fmt.Println("Error sending: ", err)
+			return
+		}
 
-		message, _ := bufio.NewReader(conn).ReadString('\n')
-		fmt.Print("Message from server: " + message)
+		message, err := codec.Decode(connReader)
+		if err != nil {
+			fmt.Println("Error receiving: ", err)
+			return
+		}
+		fmt.Println("Message from server: " + string(message))
 	}
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"example/pkg/basic/tcp/session"
+	"example/pkg/basic/tcp/tcpserver"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// chatHandler 聊天室：普通消息广播给其他人，"@id 内容" 私聊
+type chatHandler struct {
+	manager  *session.Manager
+	password string // 不为空时声明 ID 需要提供该口令
+}
+
+func (h *chatHandler) Authenticate(s *session.Session, id, token string) error {
+	if h.password != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.password)) != 1 {
+		return errors.New("wrong password")
+	}
+	return nil
+}
+
+func (h *chatHandler) OnOpen(s *session.Session) {
+	fmt.Printf("%s joined from %s\n", s.ID(), s.Conn().RemoteAddr())
+	h.manager.Broadcast([]byte(s.ID()+" joined"), s.ID())
+}
+
+func (h *chatHandler) OnMessage(s *session.Session, payload []byte) {
+	if bytes.HasPrefix(payload, []byte("@")) {
+		to, msg, _ := bytes.Cut(payload[1:], []byte(" "))
+		if err := h.manager.SendTo(string(to), []byte(fmt.Sprintf("[%s -> you] %s", s.ID(), msg))); err != nil {
+			s.Send([]byte(fmt.Sprintf("%s is offline", to)))
+		}
+		return
+	}
+	h.manager.Broadcast([]byte(fmt.Sprintf("[%s] %s", s.ID(), payload)), s.ID())
+}
+
+func (h *chatHandler) OnClose(s *session.Session, err error) {
+	fmt.Printf("%s left: %v\n", s.ID(), err)
+	h.manager.Broadcast([]byte(s.ID() + " left"))
+}
+
+func main() {
+	addr := flag.String("addr", ":8081", "监听地址")
+	interval := flag.Duration("heartbeat", 5*time.Second, "心跳间隔")
+	timeout := flag.Duration("heartbeat-timeout", 15*time.Second, "心跳超时，超时未收到任何消息的客户端被剔除")
+	password := flag.String("password", "", "声明 ID 需要的口令，为空时任何人都可以使用任意 ID")
+	flag.Parse()
+
+	handler := &chatHandler{password: *password}
+	manager := session.NewManager(session.Config{HeartbeatInterval: *interval, HeartbeatTimeout: *timeout}, handler)
+	defer manager.Close()
+	handler.manager = manager
+
+	srv := tcpserver.New(tcpserver.Config{Addr: *addr, Codec: tcpserver.NewLineCodec(4096)}, manager)
+	go func() {
+		fmt.Println("Chat server listening on", *addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, tcpserver.ErrServerClosed) {
+			fmt.Println("Error starting TCP server: ", err)
+			os.Exit(1)
+		}
+	}()
+
+	// 收到信号后优雅停机
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+
+	fmt.Println("Shutting down...")
+	manager.Broadcast([]byte("server is shutting down"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Println("Error shutting down: ", err)
+	}
+}
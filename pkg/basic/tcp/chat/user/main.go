@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"example/pkg/basic/tcp/session"
+	"example/pkg/basic/tcp/tcpserver"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8081", "服务端地址")
+	name := flag.String("name", "", "客户端 ID，为空时由服务端分配")
+	token := flag.String("token", "", "服务端设置了 -password 时的口令")
+	timeout := flag.Duration("timeout", 30*time.Second, "超过该时间没有收到服务端任何消息时断开")
+	flag.Parse()
+
+	conn, err := net.Dial("tcp", *addr)
+	if err != nil {
+		fmt.Println("Error connecting to server: ", err)
+		return
+	}
+	defer conn.Close()
+
+	codec := tcpserver.NewLineCodec(4096)
+	w := bufio.NewWriter(conn)
+	var mu sync.Mutex
+	send := func(typ byte, payload string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := codec.Encode(w, session.Frame(typ, []byte(payload))); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	if *name != "" {
+		hello := *name
+		if *token != "" {
+			hello += " " + *token
+		}
+		if err := send(session.TypeHello, hello); err != nil {
+			fmt.Println("Error sending: ", err)
+			return
+		}
+	}
+
+	// 接收协程：回复心跳，打印消息；服务端失联时退出
+	go func() {
+		r := bufio.NewReader(conn)
+		for {
+			conn.SetReadDeadline(time.Now().Add(*timeout))
+			frame, err := codec.Decode(r)
+			if err != nil {
+				fmt.Println("Disconnected: ", err)
+				os.Exit(0)
+			}
+			typ, payload, err := session.ParseFrame(frame)
+			if err != nil {
+				continue
+			}
+			switch typ {
+			case session.TypePing:
+				send(session.TypePong, string(payload))
+			case session.TypeData:
+				fmt.Println(string(payload))
+			}
+		}
+	}()
+
+	fmt.Println("Type a message to broadcast, or \"@id message\" to send privately")
+	stdin := bufio.NewScanner(os.Stdin)
+	for stdin.Scan() {
+		text := strings.TrimSpace(stdin.Text())
+		if text == "" {
+			continue
+		}
+		if err := send(session.TypeData, text); err != nil {
+			fmt.Println("Error sending: ", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package session
+
+import "fmt"
+
+// 帧类型，每帧第一个字节为类型，其余为内容，分帧由 tcpserver.Codec 负责
+const (
+	TypeData  byte = 'D' // 业务数据
+	TypePing  byte = 'P' // 心跳请求，收到后回复 TypePong
+	TypePong  byte = 'O' // 心跳响应
+	TypeHello byte = 'H' // 客户端声明自己的 ID，内容为 ID，需要认证时为 "ID 令牌"
+)
+
+// Frame 组装一帧
+func Frame(typ byte, payload []byte) []byte {
+	frame := make([]byte, 1+len(payload))
+	frame[0] = typ
+	copy(frame[1:], payload)
+	return frame
+}
+
+// ParseFrame 拆分帧类型和内容
+func ParseFrame(frame []byte) (byte, []byte, error) {
+	if len(frame) == 0 {
+		return 0, nil, fmt.Errorf("session: empty frame")
+	}
+	return frame[0], frame[1:], nil
+}
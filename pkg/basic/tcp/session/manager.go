@@ -0,0 +1,371 @@
+// Package session 在 tcpserver 之上管理会话：客户端 ID、ping/pong 心跳和失联剔除、按 ID 单播和广播
+//
+// 客户端连接后可以发送 TypeHello 帧声明 ID，否则使用服务端分配的 ID（conn-<连接编号>）；
+// Handler 实现 AuthHandler 时声明 ID 需要通过认证，否则任何客户端都可以声明任意 ID 并顶替在线的会话，只适合可信网络；
+// 服务端定期向一段时间没有消息的会话发送 TypePing，超过 HeartbeatTimeout 没有收到任何帧的会话被关闭
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example/pkg/basic/tcp/tcpserver"
+)
+
+// 默认心跳配置
+const (
+	DefaultHeartbeatInterval = 15 * time.Second
+	DefaultHeartbeatTimeout  = 45 * time.Second
+	maxIDLength              = 64
+	assignedPrefix           = "conn-" // 服务端分配的 ID 前缀，客户端不能使用
+)
+
+var (
+	// ErrSessionNotFound 会话不存在或已关闭
+	ErrSessionNotFound = errors.New("session: not found")
+	// ErrHeartbeatTimeout 超过 HeartbeatTimeout 没有收到对端的任何帧
+	ErrHeartbeatTimeout = errors.New("session: heartbeat timeout")
+	// ErrReplaced 同一个 ID 在其他连接上登录，旧会话被关闭
+	ErrReplaced = errors.New("session: replaced by new connection")
+	// ErrAuthFailed TypeHello 没有通过 AuthHandler 的认证，连接被关闭
+	ErrAuthFailed = errors.New("session: authentication failed")
+)
+
+// sessionKey 会话保存在 tcpserver.Conn 中的 key
+type sessionKey struct{}
+
+// Session 一个客户端会话
+type Session struct {
+	conn     *tcpserver.Conn
+	id       atomic.Value // string
+	lastSeen atomic.Int64 // 最后一次收到帧的时间，UnixNano
+}
+
+// ID 客户端 ID
+func (s *Session) ID() string {
+	return s.id.Load().(string)
+}
+
+// Conn 底层连接
+func (s *Session) Conn() *tcpserver.Conn {
+	return s.conn
+}
+
+// Send 发送业务数据
+func (s *Session) Send(payload []byte) error {
+	return s.conn.Send(Frame(TypeData, payload))
+}
+
+// Close 关闭会话
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
+
+// LastSeen 最后一次收到对端帧的时间
+func (s *Session) LastSeen() time.Time {
+	return time.Unix(0, s.lastSeen.Load())
+}
+
+func (s *Session) touch() {
+	s.lastSeen.Store(time.Now().UnixNano())
+}
+
+// Handler 处理会话上的业务数据，在连接的读协程中调用
+type Handler interface {
+	OnMessage(s *Session, payload []byte)
+}
+
+// OpenHandler 可选，会话确定 ID 后调用：收到 TypeHello 后，或第一帧不是 TypeHello 时使用分配的 ID
+type OpenHandler interface {
+	OnOpen(s *Session)
+}
+
+// AuthHandler 可选，收到 TypeHello 时校验客户端能否使用 id，返回错误时关闭连接，id 不会被占用
+// token 为 TypeHello 内容中第一个空格之后的部分，没有时为空字符串
+type AuthHandler interface {
+	Authenticate(s *Session, id, token string) error
+}
+
+// CloseHandler 可选，已经 OnOpen 的会话关闭后调用，err 为关闭原因
+type CloseHandler interface {
+	OnClose(s *Session, err error)
+}
+
+// HandlerFunc 把函数适配为 Handler
+type HandlerFunc func(s *Session, payload []byte)
+
+// OnMessage 实现 Handler
+func (f HandlerFunc) OnMessage(s *Session, payload []byte) {
+	f(s, payload)
+}
+
+// Config 心跳配置
+type Config struct {
+	HeartbeatInterval time.Duration // 会话空闲超过该时间时发送 ping，默认 DefaultHeartbeatInterval
+	HeartbeatTimeout  time.Duration // 超过该时间没有收到任何帧时关闭会话，默认 DefaultHeartbeatTimeout
+}
+
+// Manager 会话管理器，作为 tcpserver 的 Handler 使用：
+//
+//	m := session.NewManager(session.Config{}, handler)
+//	defer m.Close()
+//	srv := tcpserver.New(tcpserver.Config{Addr: ":8080"}, m)
+type Manager struct {
+	cfg     Config
+	handler Handler
+
+	mu       sync.RWMutex
+	sessions map[string]*Session
+	opened   map[*Session]bool // 已经 OnOpen 的会话
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager 创建会话管理器并启动心跳检查
+func NewManager(cfg Config, handler Handler) *Manager {
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if cfg.HeartbeatTimeout <= 0 {
+		cfg.HeartbeatTimeout = DefaultHeartbeatTimeout
+	}
+	m := &Manager{
+		cfg:      cfg,
+		handler:  handler,
+		sessions: make(map[string]*Session),
+		opened:   make(map[*Session]bool),
+		stop:     make(chan struct{}),
+	}
+	go m.heartbeat()
+	return m
+}
+
+// Close 停止心跳检查，不关闭会话，会话随 tcpserver.Server.Shutdown 关闭
+func (m *Manager) Close() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+// OnConnect 创建会话，实现 tcpserver.ConnectHandler
+func (m *Manager) OnConnect(c *tcpserver.Conn) {
+	s := &Session{conn: c}
+	s.id.Store(fmt.Sprintf("%s%d", assignedPrefix, c.ID()))
+	s.touch()
+	c.Set(sessionKey{}, s)
+
+	m.mu.Lock()
+	m.sessions[s.ID()] = s
+	m.mu.Unlock()
+}
+
+// OnMessage 处理心跳和握手，业务数据交给 Handler，实现 tcpserver.Handler
+func (m *Manager) OnMessage(c *tcpserver.Conn, frame []byte) {
+	s := sessionOf(c)
+	if s == nil {
+		return
+	}
+	s.touch()
+
+	typ, payload, err := ParseFrame(frame)
+	if err != nil {
+		c.CloseWithError(err)
+		return
+	}
+
+	switch typ {
+	case TypePing:
+		c.Send(Frame(TypePong, payload))
+	case TypePong:
+		// 收到任何帧都已经刷新了 lastSeen
+	case TypeHello:
+		if err := m.hello(s, string(payload)); err != nil {
+			c.CloseWithError(err)
+		}
+	case TypeData:
+		m.open(s)
+		m.handler.OnMessage(s, payload)
+	default:
+		c.CloseWithError(fmt.Errorf("session: unknown frame type %q", typ))
+	}
+}
+
+// OnClose 移除会话，实现 tcpserver.CloseHandler
+func (m *Manager) OnClose(c *tcpserver.Conn, err error) {
+	s := sessionOf(c)
+	if s == nil {
+		return
+	}
+
+	m.mu.Lock()
+	if m.sessions[s.ID()] == s {
+		delete(m.sessions, s.ID())
+	}
+	opened := m.opened[s]
+	delete(m.opened, s)
+	m.mu.Unlock()
+
+	if h, ok := m.handler.(CloseHandler); ok && opened {
+		h.OnClose(s, err)
+	}
+}
+
+// hello 把会话改为客户端声明的 ID，只能在第一条业务数据之前声明一次，内容为 "<id>" 或 "<id> <token>"
+// Handler 实现 AuthHandler 时先认证；同一个 ID 已有会话时关闭旧会话，后登录的生效
+func (m *Manager) hello(s *Session, payload string) error {
+	id, token, _ := strings.Cut(payload, " ")
+	if id == "" || len(id) > maxIDLength || strings.HasPrefix(id, assignedPrefix) {
+		return fmt.Errorf("session: invalid id %q", id)
+	}
+
+	// hello 和 open 都在连接的读协程中调用，认证期间 opened 不会改变，认证时不持有锁
+	m.mu.RLock()
+	opened := m.opened[s]
+	m.mu.RUnlock()
+	if opened {
+		return fmt.Errorf("session: hello after session opened")
+	}
+	if h, ok := m.handler.(AuthHandler); ok {
+		if err := h.Authenticate(s, id, token); err != nil {
+			log.Printf("session: %s from %s: %v", id, s.conn.RemoteAddr(), err)
+			return ErrAuthFailed
+		}
+	}
+
+	m.mu.Lock()
+	old := m.sessions[id]
+	if m.sessions[s.ID()] == s {
+		delete(m.sessions, s.ID())
+	}
+	s.id.Store(id)
+	m.sessions[id] = s
+	m.mu.Unlock()
+
+	if old != nil && old != s {
+		log.Printf("session: %s logged in again from %s, closing %s", id, s.conn.RemoteAddr(), old.conn.RemoteAddr())
+		old.conn.CloseWithError(ErrReplaced)
+	}
+	m.open(s)
+	return nil
+}
+
+// open 第一次确定 ID 时调用 OnOpen
+func (m *Manager) open(s *Session) {
+	m.mu.Lock()
+	if m.opened[s] {
+		m.mu.Unlock()
+		return
+	}
+	m.opened[s] = true
+	m.mu.Unlock()
+
+	if h, ok := m.handler.(OpenHandler); ok {
+		h.OnOpen(s)
+	}
+}
+
+// Get 按 ID 查找会话
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id]
+	return s, ok
+}
+
+// Count 当前会话数
+func (m *Manager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sessions)
+}
+
+// Sessions 当前会话的快照
+func (m *Manager) Sessions() []*Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		list = append(list, s)
+	}
+	return list
+}
+
+// SendTo 单播，会话不存在时返回 ErrSessionNotFound
+func (m *Manager) SendTo(id string, payload []byte) error {
+	s, ok := m.Get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return s.Send(payload)
+}
+
+// Broadcast 向除 exclude 以外的所有已经 OnOpen 的会话发送，返回成功放入发送队列的会话数
+// 还在认证或尚未确定 ID 的连接收不到广播；发送队列已满的慢客户端会被跳过，不影响其他会话
+func (m *Manager) Broadcast(payload []byte, exclude ...string) int {
+	m.mu.RLock()
+	list := make([]*Session, 0, len(m.opened))
+	for s := range m.opened {
+		list = append(list, s)
+	}
+	m.mu.RUnlock()
+
+	frame := Frame(TypeData, payload)
+	sent := 0
+	for _, s := range list {
+		if contains(exclude, s.ID()) {
+			continue
+		}
+		if err := s.conn.Send(frame); err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// heartbeat 定期检查所有会话：超时的关闭，空闲的发送 ping
+func (m *Manager) heartbeat() {
+	// 检查间隔不超过心跳间隔的一半，剔除的误差不超过半个间隔
+	ticker := time.NewTicker(m.cfg.HeartbeatInterval / 2)
+	defer ticker.Stop()
+
+	ping := Frame(TypePing, nil)
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			for _, s := range m.Sessions() {
+				idle := now.Sub(s.LastSeen())
+				switch {
+				case idle >= m.cfg.HeartbeatTimeout:
+					log.Printf("session: %s heartbeat timeout after %v", s.ID(), idle.Round(time.Millisecond))
+					s.conn.CloseWithError(ErrHeartbeatTimeout)
+				case idle >= m.cfg.HeartbeatInterval:
+					s.conn.Send(ping)
+				}
+			}
+		}
+	}
+}
+
+// sessionOf 取出连接上的会话
+func sessionOf(c *tcpserver.Conn) *Session {
+	v, ok := c.Get(sessionKey{})
+	if !ok {
+		return nil
+	}
+	return v.(*Session)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
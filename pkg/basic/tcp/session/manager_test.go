@@ -0,0 +1,240 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"example/pkg/basic/tcp/tcpserver"
+)
+
+// recorder 记录会话事件
+type recorder struct {
+	mu       sync.Mutex
+	messages []string
+	closed   chan error
+	tokens   map[string]string // 不为 nil 时按 ID 校验令牌
+}
+
+func (r *recorder) Authenticate(s *Session, id, token string) error {
+	if r.tokens == nil || r.tokens[id] == token {
+		return nil
+	}
+	return fmt.Errorf("invalid token for %s", id)
+}
+
+func (r *recorder) OnMessage(s *Session, payload []byte) {
+	r.mu.Lock()
+	r.messages = append(r.messages, s.ID()+":"+string(payload))
+	r.mu.Unlock()
+}
+
+func (r *recorder) OnClose(s *Session, err error) {
+	r.closed <- err
+}
+
+// testClient 测试客户端，使用和服务端相同的分帧协议
+type testClient struct {
+	t     *testing.T
+	conn  net.Conn
+	codec tcpserver.Codec
+	r     *bufio.Reader
+	w     *bufio.Writer
+}
+
+func (c *testClient) send(typ byte, payload string) {
+	c.t.Helper()
+	if err := c.codec.Encode(c.w, Frame(typ, []byte(payload))); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := c.w.Flush(); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) recv() (byte, string) {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frame, err := c.codec.Decode(c.r)
+	if err != nil {
+		c.t.Fatalf("recv error: %v", err)
+	}
+	typ, payload, _ := ParseFrame(frame)
+	return typ, string(payload)
+}
+
+func startManager(t *testing.T, cfg Config) (*Manager, *recorder, func(id string) *testClient) {
+	t.Helper()
+	return startManagerWith(t, cfg, &recorder{closed: make(chan error, 10)})
+}
+
+// startManagerWith 启动服务端，dial 的参数不为空时作为 TypeHello 的内容发送
+func startManagerWith(t *testing.T, cfg Config, rec *recorder) (*Manager, *recorder, func(hello string) *testClient) {
+	t.Helper()
+	m := NewManager(cfg, rec)
+	codec := tcpserver.NewLengthCodec(0)
+	srv := tcpserver.New(tcpserver.Config{Codec: codec}, m)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(l)
+	t.Cleanup(func() {
+		m.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+
+	dial := func(hello string) *testClient {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		c := &testClient{t: t, conn: conn, codec: codec, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+		if hello != "" {
+			c.send(TypeHello, hello)
+			c.send(TypePing, "sync")
+			if typ, payload := c.recv(); typ != TypePong || payload != "sync" {
+				t.Fatalf("hello sync got %q %q", typ, payload)
+			}
+		}
+		return c
+	}
+	return m, rec, dial
+}
+
+func TestUnicastAndBroadcast(t *testing.T) {
+	m, rec, dial := startManager(t, Config{})
+	alice, bob, carol := dial("alice"), dial("bob"), dial("carol")
+
+	if n := m.Count(); n != 3 {
+		t.Fatalf("Count = %d, want 3", n)
+	}
+	if err := m.SendTo("bob", []byte("hi bob")); err != nil {
+		t.Fatal(err)
+	}
+	if typ, payload := bob.recv(); typ != TypeData || payload != "hi bob" {
+		t.Errorf("bob got %q %q", typ, payload)
+	}
+	if err := m.SendTo("nobody", nil); err != ErrSessionNotFound {
+		t.Errorf("SendTo unknown = %v, want ErrSessionNotFound", err)
+	}
+
+	if n := m.Broadcast([]byte("news"), "alice"); n != 2 {
+		t.Errorf("Broadcast sent %d, want 2", n)
+	}
+	for _, c := range []*testClient{bob, carol} {
+		if _, payload := c.recv(); payload != "news" {
+			t.Errorf("broadcast got %q", payload)
+		}
+	}
+
+	alice.send(TypeData, "hello")
+	alice.send(TypePing, "")
+	alice.recv()
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.messages) != 1 || rec.messages[0] != "alice:hello" {
+		t.Errorf("messages = %v", rec.messages)
+	}
+}
+
+func TestBroadcastSkipsUnopened(t *testing.T) {
+	m, _, dial := startManager(t, Config{})
+	alice := dial("alice")
+	dial("") // 已连接，还没有声明 ID 或发送业务数据
+
+	for deadline := time.Now().Add(time.Second); m.Count() < 2; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("second connection not registered")
+		}
+	}
+	if n := m.Broadcast([]byte("news")); n != 1 {
+		t.Errorf("Broadcast sent %d, want 1", n)
+	}
+	if _, payload := alice.recv(); payload != "news" {
+		t.Errorf("alice got %q", payload)
+	}
+}
+
+func TestHelloAuthentication(t *testing.T) {
+	rec := &recorder{closed: make(chan error, 10), tokens: map[string]string{"alice": "secret"}}
+	m, _, dial := startManagerWith(t, Config{}, rec)
+	alice := dial("alice secret")
+	first, _ := m.Get("alice")
+
+	// 令牌错误时连接被关闭，不能顶替 alice
+	c := dial("")
+	c.send(TypeHello, "alice wrong")
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := c.codec.Decode(c.r); err != io.EOF {
+		t.Errorf("read after failed hello = %v, want io.EOF", err)
+	}
+	if s, _ := m.Get("alice"); s != first {
+		t.Error("alice replaced by unauthenticated session")
+	}
+
+	alice.send(TypePing, "still here")
+	if typ, payload := alice.recv(); typ != TypePong || payload != "still here" {
+		t.Errorf("alice got %q %q", typ, payload)
+	}
+}
+
+func TestHelloReplacesOldSession(t *testing.T) {
+	m, rec, dial := startManager(t, Config{})
+	dial("alice")
+	dial("alice")
+
+	select {
+	case err := <-rec.closed:
+		if err != ErrReplaced {
+			t.Errorf("close reason = %v, want ErrReplaced", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("old session not closed")
+	}
+	if n := m.Count(); n != 1 {
+		t.Errorf("Count = %d, want 1", n)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	m, rec, dial := startManager(t, Config{HeartbeatInterval: 100 * time.Millisecond, HeartbeatTimeout: 300 * time.Millisecond})
+	alive := dial("alive")
+	dial("dead")
+
+	// alive 回复 ping 保持在线，dead 不回复被剔除
+	deadline := time.After(600 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case <-deadline:
+			done = true
+		default:
+			if typ, _ := alive.recv(); typ == TypePing {
+				alive.send(TypePong, "")
+			}
+		}
+	}
+
+	select {
+	case err := <-rec.closed:
+		if err != ErrHeartbeatTimeout {
+			t.Errorf("close reason = %v, want ErrHeartbeatTimeout", err)
+		}
+	default:
+		t.Fatal("dead session not evicted")
+	}
+	if _, ok := m.Get("alive"); !ok {
+		t.Error("alive session evicted")
+	}
+	if _, ok := m.Get("dead"); ok {
+		t.Error("dead session still registered")
+	}
+}
@@ -0,0 +1,168 @@
+package tcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxFrameSize 默认的单帧上限
+const DefaultMaxFrameSize = 1 << 20
+
+var (
+	// ErrFrameTooLarge 帧超过 MaxFrameSize，读取时连接会被关闭，因为剩余的字节已无法正确分帧
+	ErrFrameTooLarge = errors.New("tcpserver: frame too large")
+	// ErrDelimiterInMessage 消息中包含分隔符，无法用 DelimiterCodec 分帧
+	ErrDelimiterInMessage = errors.New("tcpserver: message contains delimiter")
+)
+
+// Codec 分帧协议，TCP 是字节流，需要约定消息边界
+// Decode 从同一个 bufio.Reader 中连续读取，已缓冲的字节不会丢失
+type Codec interface {
+	// Decode 读取一帧，返回的切片归调用方所有
+	Decode(r *bufio.Reader) ([]byte, error)
+	// Encode 写入一帧，由调用方负责 Flush
+	Encode(w *bufio.Writer, msg []byte) error
+	// Validate 检查消息能否被 Encode 写出，Conn.Send 入队前调用，避免写协程编码失败后关闭连接
+	Validate(msg []byte) error
+}
+
+// LengthCodec 长度前缀分帧：4 字节大端长度 + 消息体，适合二进制协议
+type LengthCodec struct {
+	MaxFrameSize int // 为 0 时使用 DefaultMaxFrameSize
+}
+
+// NewLengthCodec 创建长度前缀分帧
+func NewLengthCodec(maxFrameSize int) *LengthCodec {
+	return &LengthCodec{MaxFrameSize: maxFrameSize}
+}
+
+// Decode 实现 Codec
+func (c *LengthCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	if int64(n) > int64(maxFrameSize(c.MaxFrameSize)) {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, n)
+	}
+
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return msg, nil
+}
+
+// Encode 实现 Codec
+func (c *LengthCodec) Encode(w *bufio.Writer, msg []byte) error {
+	if err := c.Validate(msg); err != nil {
+		return err
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(msg)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// Validate 实现 Codec
+func (c *LengthCodec) Validate(msg []byte) error {
+	if len(msg) > maxFrameSize(c.MaxFrameSize) {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, len(msg))
+	}
+	return nil
+}
+
+// DelimiterCodec 分隔符分帧，适合 telnet、nc 等文本协议，消息中不能包含分隔符
+type DelimiterCodec struct {
+	Delimiter    byte
+	MaxFrameSize int // 为 0 时使用 DefaultMaxFrameSize，不含分隔符
+}
+
+// NewDelimiterCodec 创建分隔符分帧
+func NewDelimiterCodec(delimiter byte, maxFrameSize int) *DelimiterCodec {
+	return &DelimiterCodec{Delimiter: delimiter, MaxFrameSize: maxFrameSize}
+}
+
+// NewLineCodec 创建按行分帧，读取时去掉行尾的 \r\n 或 \n
+func NewLineCodec(maxFrameSize int) *DelimiterCodec {
+	return NewDelimiterCodec('\n', maxFrameSize)
+}
+
+// Decode 实现 Codec，超长的行不会一直缓存在内存中
+func (c *DelimiterCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	limit := maxFrameSize(c.MaxFrameSize)
+	var msg []byte
+	for {
+		// ReadSlice 最多返回缓冲区大小的数据，分段读取并检查长度
+		chunk, err := r.ReadSlice(c.Delimiter)
+		size := len(msg) + len(chunk)
+		if err == nil {
+			size-- // 不含分隔符
+		}
+		if size > limit {
+			return nil, fmt.Errorf("%w: more than %d bytes", ErrFrameTooLarge, limit)
+		}
+		msg = append(msg, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			if len(msg) > 0 {
+				return nil, unexpectedEOF(err)
+			}
+			return nil, err
+		}
+		break
+	}
+
+	msg = msg[:len(msg)-1]
+	if c.Delimiter == '\n' {
+		msg = bytes.TrimSuffix(msg, []byte{'\r'})
+	}
+	return msg, nil
+}
+
+// Encode 实现 Codec
+func (c *DelimiterCodec) Encode(w *bufio.Writer, msg []byte) error {
+	if err := c.Validate(msg); err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	return w.WriteByte(c.Delimiter)
+}
+
+// Validate 实现 Codec
+func (c *DelimiterCodec) Validate(msg []byte) error {
+	if len(msg) > maxFrameSize(c.MaxFrameSize) {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, len(msg))
+	}
+	if bytes.IndexByte(msg, c.Delimiter) >= 0 {
+		return fmt.Errorf("%w %q", ErrDelimiterInMessage, c.Delimiter)
+	}
+	return nil
+}
+
+func maxFrameSize(n int) int {
+	if n <= 0 {
+		return DefaultMaxFrameSize
+	}
+	return n
+}
+
+// unexpectedEOF 帧读到一半连接断开
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
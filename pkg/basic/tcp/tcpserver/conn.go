@@ -0,0 +1,227 @@
+package tcpserver
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrConnClosed 连接已关闭，不能再发送
+	ErrConnClosed = errors.New("tcpserver: connection closed")
+	// ErrSendQueueFull 发送队列已满，对端读取太慢
+	ErrSendQueueFull = errors.New("tcpserver: send queue full")
+	// ErrIdleTimeout 超过 IdleTimeout 没有收到任何消息
+	ErrIdleTimeout = errors.New("tcpserver: idle timeout")
+)
+
+// Conn 服务端的一个连接
+// 每个连接有一个读协程和一个写协程：读协程解码消息并依次调用 Handler.OnMessage，
+// 写协程从发送队列取消息编码写出，Send 不会阻塞在网络写上
+type Conn struct {
+	id     uint64
+	conn   net.Conn
+	server *Server
+
+	sendCh     chan []byte
+	mu         sync.Mutex
+	sendClosed bool // 写协程开始收尾后不再接受新消息
+
+	closing   chan struct{} // 关闭开始，读协程停止读取
+	readDone  chan struct{} // 读协程退出，此后不会再有 OnMessage
+	done      chan struct{} // 写协程退出，底层连接已关闭
+	closeOnce sync.Once
+	closeErr  error
+
+	values sync.Map
+}
+
+func newConn(id uint64, conn net.Conn, s *Server) *Conn {
+	return &Conn{
+		id:       id,
+		conn:     conn,
+		server:   s,
+		sendCh:   make(chan []byte, s.cfg.SendQueueSize),
+		closing:  make(chan struct{}),
+		readDone: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// ID 连接编号，在同一个 Server 内唯一
+func (c *Conn) ID() uint64 {
+	return c.id
+}
+
+// RemoteAddr 对端地址
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// TLSState TLS 连接的状态，握手在第一次读取时完成，在 OnMessage 中可以取到对端证书
+// 明文连接返回 false
+func (c *Conn) TLSState() (tls.ConnectionState, bool) {
+	tc, ok := c.conn.(*tls.Conn)
+	if !ok {
+		return tls.ConnectionState{}, false
+	}
+	return tc.ConnectionState(), true
+}
+
+// Set 保存连接级别的数据，如登录后的用户信息
+func (c *Conn) Set(key, value interface{}) {
+	c.values.Store(key, value)
+}
+
+// Get 读取 Set 保存的数据
+func (c *Conn) Get(key interface{}) (interface{}, bool) {
+	return c.values.Load(key)
+}
+
+// Send 把消息放入发送队列，队列满时返回 ErrSendQueueFull 而不是阻塞
+// 分帧协议无法写出的消息（如超过 MaxFrameSize）直接返回 Codec.Validate 的错误，连接不受影响
+// 关闭过程中，在读协程退出前（包括 OnMessage 中）发送的消息仍会在关闭连接前写出
+func (c *Conn) Send(msg []byte) error {
+	if err := c.server.cfg.Codec.Validate(msg); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendClosed {
+		return ErrConnClosed
+	}
+	select {
+	case c.sendCh <- msg:
+		return nil
+	default:
+		return ErrSendQueueFull
+	}
+}
+
+// Close 优雅关闭：停止读取，等当前的 OnMessage 返回后写出队列中的消息，再关闭连接
+func (c *Conn) Close() error {
+	c.closeWithError(nil)
+	return nil
+}
+
+// CloseWithError 与 Close 相同，err 作为关闭原因传给 OnClose，如心跳超时
+func (c *Conn) CloseWithError(err error) {
+	c.closeWithError(err)
+}
+
+// Done 连接完全关闭时关闭的通道
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// closeWithError 开始关闭，只有第一次的原因会被记录并传给 OnClose
+func (c *Conn) closeWithError(err error) {
+	c.closeOnce.Do(func() {
+		c.closeErr = err
+		close(c.closing)
+		// 让阻塞在 Read 上的读协程立即返回
+		c.conn.SetReadDeadline(time.Now())
+	})
+}
+
+// readLoop 读协程，同一个 bufio.Reader 贯穿整个连接，不会丢失已缓冲的字节
+func (c *Conn) readLoop() {
+	defer close(c.readDone)
+
+	r := bufio.NewReader(c.conn)
+	idle := c.server.cfg.IdleTimeout
+	for {
+		// OnMessage 中可能已经开始关闭，此时重新设置截止时间会覆盖 closeWithError 设置的立即超时
+		if c.isClosing() {
+			return
+		}
+		if idle > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(idle))
+			// closeWithError 先关闭 closing 再设置截止时间，设置后再检查一次，不会漏掉刚开始的关闭
+			if c.isClosing() {
+				return
+			}
+		}
+		msg, err := c.server.cfg.Codec.Decode(r)
+		if err != nil {
+			select {
+			case <-c.closing:
+				// 主动关闭导致的读超时
+			default:
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					err = ErrIdleTimeout
+				}
+				c.closeWithError(err)
+			}
+			return
+		}
+		c.server.handler.OnMessage(c, msg)
+	}
+}
+
+// isClosing 是否已经开始关闭
+func (c *Conn) isClosing() bool {
+	select {
+	case <-c.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// writeLoop 写协程，队列为空时才 Flush，连续的消息合并写出
+func (c *Conn) writeLoop() {
+	defer close(c.done)
+	defer c.conn.Close()
+
+	w := bufio.NewWriter(c.conn)
+	for {
+		select {
+		case msg := <-c.sendCh:
+			if err := c.write(w, msg); err != nil {
+				c.closeWithError(err)
+				return
+			}
+		case <-c.closing:
+			<-c.readDone
+			c.mu.Lock()
+			c.sendClosed = true
+			c.mu.Unlock()
+			c.drain(w)
+			return
+		}
+	}
+}
+
+// write 写入一条消息，后面没有待发送的消息时 Flush
+func (c *Conn) write(w *bufio.Writer, msg []byte) error {
+	if t := c.server.cfg.WriteTimeout; t > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(t))
+	}
+	if err := c.server.cfg.Codec.Encode(w, msg); err != nil {
+		return err
+	}
+	if len(c.sendCh) == 0 {
+		return w.Flush()
+	}
+	return nil
+}
+
+// drain 关闭前写出队列中剩余的消息，写失败时放弃
+func (c *Conn) drain(w *bufio.Writer) {
+	for {
+		select {
+		case msg := <-c.sendCh:
+			if err := c.write(w, msg); err != nil {
+				return
+			}
+		default:
+			w.Flush()
+			return
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package tcpserver 可复用的 TCP 服务器：可插拔的分帧协议、每连接读写协程、空闲超时、最大连接数和优雅停机
+//
+//	srv := tcpserver.New(tcpserver.Config{Addr: ":8080", Codec: tcpserver.NewLineCodec(0)},
+//		tcpserver.HandlerFunc(func(c *tcpserver.Conn, msg []byte) { c.Send(msg) }))
+//	go srv.ListenAndServe()
+//	...
+//	srv.Shutdown(ctx)
+package tcpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultSendQueueSize = 64
+	DefaultWriteTimeout  = 10 * time.Second
+)
+
+// ErrServerClosed Shutdown 后 Serve 返回的错误
+var ErrServerClosed = errors.New("tcpserver: server closed")
+
+// Config 服务器配置
+type Config struct {
+	Addr          string
+	Codec         Codec         // 分帧协议，默认按行分帧
+	MaxConns      int           // 最大连接数，超出时新连接被立即关闭，为 0 不限制
+	IdleTimeout   time.Duration // 超过该时间没有收到消息时关闭连接，为 0 不限制
+	WriteTimeout  time.Duration // 单次写超时，默认 DefaultWriteTimeout
+	SendQueueSize int           // 每个连接的发送队列长度，默认 DefaultSendQueueSize
+	TLSConfig     *tls.Config   // 不为 nil 时 ListenAndServe 使用 TLS，可以由 tlsconf.ServerConfig 创建
+}
+
+// Handler 处理连接上的消息，同一个连接的 OnMessage 在读协程中依次调用，不同连接并发调用
+// OnMessage 阻塞会暂停该连接的读取，耗时的处理应放到其他协程，再通过 Conn.Send 回复
+type Handler interface {
+	OnMessage(c *Conn, msg []byte)
+}
+
+// ConnectHandler 可选，连接建立后、开始读取前调用
+type ConnectHandler interface {
+	OnConnect(c *Conn)
+}
+
+// CloseHandler 可选，连接完全关闭后调用，err 为关闭原因，主动关闭和对端正常断开时为 nil 或 io.EOF
+type CloseHandler interface {
+	OnClose(c *Conn, err error)
+}
+
+// HandlerFunc 把函数适配为 Handler
+type HandlerFunc func(c *Conn, msg []byte)
+
+// OnMessage 实现 Handler
+func (f HandlerFunc) OnMessage(c *Conn, msg []byte) {
+	f(c, msg)
+}
+
+// Server TCP 服务器
+type Server struct {
+	cfg     Config
+	handler Handler
+
+	nextID atomic.Uint64
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[uint64]*Conn
+	shutdown bool
+	wg       sync.WaitGroup
+}
+
+// New 创建服务器，未设置的配置使用默认值
+func New(cfg Config, handler Handler) *Server {
+	if cfg.Codec == nil {
+		cfg.Codec = NewLineCodec(0)
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+	if cfg.SendQueueSize <= 0 {
+		cfg.SendQueueSize = DefaultSendQueueSize
+	}
+	return &Server{cfg: cfg, handler: handler, conns: make(map[uint64]*Conn)}
+}
+
+// ListenAndServe 监听 Config.Addr 并提供服务，设置了 TLSConfig 时使用 TLS
+func (s *Server) ListenAndServe() error {
+	l, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	if s.cfg.TLSConfig != nil {
+		l = tls.NewListener(l, s.cfg.TLSConfig)
+	}
+	return s.Serve(l)
+}
+
+// Serve 在 l 上接受连接，直到出错或 Shutdown，Shutdown 时返回 ErrServerClosed
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	var delay time.Duration
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isShutdown() {
+				return ErrServerClosed
+			}
+			// 临时错误（如文件描述符耗尽）退避后重试
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() || errors.Is(err, syscall.EMFILE) {
+				delay = backoff(delay)
+				log.Printf("tcpserver: accept error: %v; retrying in %v", err, delay)
+				time.Sleep(delay)
+				continue
+			}
+			return err
+		}
+		delay = 0
+
+		if !s.add(conn) {
+			conn.Close()
+		}
+	}
+}
+
+// add 登记并启动连接，超过最大连接数或已停机时返回 false
+func (s *Server) add(conn net.Conn) bool {
+	s.mu.Lock()
+	if s.shutdown {
+		s.mu.Unlock()
+		return false
+	}
+	if s.cfg.MaxConns > 0 && len(s.conns) >= s.cfg.MaxConns {
+		s.mu.Unlock()
+		log.Printf("tcpserver: too many connections (%d), rejecting %s", s.cfg.MaxConns, conn.RemoteAddr())
+		return false
+	}
+	c := newConn(s.nextID.Add(1), conn, s)
+	s.conns[c.id] = c
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go s.serveConn(c)
+	return true
+}
+
+// serveConn 运行连接的读写协程，结束后注销连接
+func (s *Server) serveConn(c *Conn) {
+	defer s.wg.Done()
+
+	if h, ok := s.handler.(ConnectHandler); ok {
+		h.OnConnect(c)
+	}
+	go c.writeLoop()
+	c.readLoop()
+	<-c.done
+
+	s.mu.Lock()
+	delete(s.conns, c.id)
+	s.mu.Unlock()
+
+	if h, ok := s.handler.(CloseHandler); ok {
+		h.OnClose(c, c.closeErr)
+	}
+}
+
+// ConnCount 当前连接数
+func (s *Server) ConnCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Range 遍历当前连接，f 返回 false 时停止，可用于广播
+func (s *Server) Range(f func(c *Conn) bool) {
+	s.mu.Lock()
+	conns := make([]*Conn, 0, len(s.conns))
+	for _, c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		if !f(c) {
+			return
+		}
+	}
+}
+
+// Shutdown 优雅停机：停止接受新连接，关闭所有连接（处理完当前消息、写完发送队列），
+// 等待全部连接关闭或 ctx 结束，ctx 结束时强制关闭剩余连接并返回 ctx 的错误
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shutdown = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	conns := make([]*Conn, 0, len(s.conns))
+	for _, c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		c.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		for _, c := range conns {
+			c.conn.Close()
+		}
+		return ctx.Err()
+	}
+}
+
+func (s *Server) isShutdown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shutdown
+}
+
+// backoff 重试间隔从 5ms 开始翻倍，最长 1s
+func backoff(d time.Duration) time.Duration {
+	if d == 0 {
+		return 5 * time.Millisecond
+	}
+	if d *= 2; d > time.Second {
+		d = time.Second
+	}
+	return d
+}
@@ -0,0 +1,273 @@
+package tcpserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodecs(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+		msgs  []string
+	}{
+		{"长度前缀", NewLengthCodec(0), []string{"hello", "", "包含\n换行"}},
+		{"按行", NewLineCodec(0), []string{"hello", "", "世界"}},
+		{"分隔符", NewDelimiterCodec(0, 0), []string{"a", "b\nc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+			for _, msg := range tt.msgs {
+				if err := tt.codec.Encode(w, []byte(msg)); err != nil {
+					t.Fatalf("Encode(%q) error: %v", msg, err)
+				}
+			}
+			w.Flush()
+
+			r := bufio.NewReader(&buf)
+			for _, want := range tt.msgs {
+				got, err := tt.codec.Decode(r)
+				if err != nil {
+					t.Fatalf("Decode error: %v", err)
+				}
+				if string(got) != want {
+					t.Errorf("Decode = %q, want %q", got, want)
+				}
+			}
+			if _, err := tt.codec.Decode(r); err != io.EOF {
+				t.Errorf("Decode at end = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	line := NewLineCodec(8)
+	if _, err := line.Decode(bufio.NewReaderSize(strings.NewReader(strings.Repeat("x", 100)+"\n"), 16)); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("line Decode too large = %v, want ErrFrameTooLarge", err)
+	}
+	if got, err := line.Decode(bufio.NewReader(strings.NewReader("12345678\n"))); err != nil || string(got) != "12345678" {
+		t.Errorf("line Decode at limit = %q, %v", got, err)
+	}
+	if got, err := line.Decode(bufio.NewReader(strings.NewReader("1234567\r\n"))); err != nil || string(got) != "1234567" {
+		t.Errorf("line Decode with \\r = %q, %v", got, err)
+	}
+	if _, err := line.Decode(bufio.NewReader(strings.NewReader("half"))); err != io.ErrUnexpectedEOF {
+		t.Errorf("line Decode partial = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if err := line.Encode(bufio.NewWriter(io.Discard), []byte("a\nb")); !errors.Is(err, ErrDelimiterInMessage) {
+		t.Errorf("line Encode with delimiter = %v, want ErrDelimiterInMessage", err)
+	}
+	if err := line.Validate([]byte("123456789")); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("line Validate too large = %v, want ErrFrameTooLarge", err)
+	}
+
+	length := NewLengthCodec(4)
+	if _, err := length.Decode(bufio.NewReader(bytes.NewReader([]byte{0, 0, 0, 5, 1, 2, 3, 4, 5}))); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("length Decode too large = %v, want ErrFrameTooLarge", err)
+	}
+	if _, err := length.Decode(bufio.NewReader(bytes.NewReader([]byte{0, 0, 0, 3, 1}))); err != io.ErrUnexpectedEOF {
+		t.Errorf("length Decode partial = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if err := length.Validate([]byte("12345")); !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("length Validate too large = %v, want ErrFrameTooLarge", err)
+	}
+	if err := length.Validate([]byte("a\nb")); err != nil {
+		t.Errorf("length Validate with newline = %v, want nil", err)
+	}
+}
+
+// closeRecorder 回显消息并记录关闭原因
+type closeRecorder struct {
+	closed chan error
+}
+
+func (h *closeRecorder) OnMessage(c *Conn, msg []byte) {
+	switch string(msg) {
+	case "slow":
+		time.Sleep(200 * time.Millisecond)
+	case "close":
+		defer c.Close()
+	case "invalid":
+		// 无法分帧的消息由 Send 返回错误，不影响连接
+		if err := c.Send([]byte("a\nb")); err != nil {
+			c.Send([]byte("send error: " + err.Error()))
+		}
+		return
+	}
+	c.Send(append([]byte("echo: "), msg...))
+}
+
+func (h *closeRecorder) OnClose(c *Conn, err error) {
+	h.closed <- err
+}
+
+func startServer(t *testing.T, cfg Config) (*Server, *closeRecorder, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &closeRecorder{closed: make(chan error, 10)}
+	s := New(cfg, h)
+	go s.Serve(l)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	})
+	return s, h, l.Addr().String()
+}
+
+func TestServerEcho(t *testing.T) {
+	_, _, addr := startServer(t, Config{})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// 一次写入多条消息，服务端不能丢失缓冲的字节
+	conn.Write([]byte("a\nb\r\nc\n"))
+	r := bufio.NewReader(conn)
+	for _, want := range []string{"echo: a\n", "echo: b\n", "echo: c\n"} {
+		got, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestServerIdleTimeout(t *testing.T) {
+	_, h, addr := startServer(t, Config{IdleTimeout: 100 * time.Millisecond})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	select {
+	case err := <-h.closed:
+		if err != ErrIdleTimeout {
+			t.Errorf("close reason = %v, want ErrIdleTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle connection not closed")
+	}
+}
+
+func TestServerCloseInOnMessage(t *testing.T) {
+	// IdleTimeout 很长时，OnMessage 中关闭连接也要立即生效
+	_, h, addr := startServer(t, Config{IdleTimeout: time.Hour})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("close\n"))
+	select {
+	case err := <-h.closed:
+		if err != nil {
+			t.Errorf("close reason = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("connection not closed after Close in OnMessage")
+	}
+	r := bufio.NewReader(conn)
+	if got, err := r.ReadString('\n'); err != nil || got != "echo: close\n" {
+		t.Errorf("reply = %q, %v", got, err)
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("read after close = %v, want io.EOF", err)
+	}
+}
+
+func TestServerSendInvalidFrame(t *testing.T) {
+	_, _, addr := startServer(t, Config{})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("invalid\nhi\n"))
+	r := bufio.NewReader(conn)
+	for _, want := range []string{"send error: tcpserver: message contains delimiter '\\n'\n", "echo: hi\n"} {
+		got, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestServerMaxConns(t *testing.T) {
+	s, _, addr := startServer(t, Config{MaxConns: 1})
+	first, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	first.Write([]byte("hi\n"))
+	bufio.NewReader(first).ReadString('\n')
+
+	second, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := second.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("rejected connection read = %v, want io.EOF", err)
+	}
+	if n := s.ConnCount(); n != 1 {
+		t.Errorf("ConnCount = %d, want 1", n)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	s, h, addr := startServer(t, Config{})
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// 停机时正在处理的消息的回复仍会写出
+	conn.Write([]byte("slow\n"))
+	time.Sleep(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	if got, err := r.ReadString('\n'); err != nil || got != "echo: slow\n" {
+		t.Errorf("reply = %q, %v", got, err)
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("read after shutdown = %v, want io.EOF", err)
+	}
+	if err := <-h.closed; err != nil {
+		t.Errorf("close reason = %v, want nil", err)
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Error("dial after shutdown should fail")
+	}
+}
@@ -0,0 +1,47 @@
+package tlsconf
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+)
+
+// ClientConfig 客户端 TLS 配置
+type ClientConfig struct {
+	CAFile     string // 校验服务端证书的 CA，为空时使用系统根证书
+	CertFile   string // 客户端证书，服务端开启 mTLS 时需要
+	KeyFile    string
+	ServerName string // 校验的服务端名称，为空时使用连接地址中的主机名
+	Enabled    bool   // 是否使用 TLS
+}
+
+// ClientFlags 在 fs 上注册客户端 TLS 参数，返回的配置在 fs.Parse 之后生效
+func ClientFlags(fs *flag.FlagSet) *ClientConfig {
+	cfg := &ClientConfig{}
+	fs.BoolVar(&cfg.Enabled, "tls", false, "使用 TLS 连接")
+	fs.StringVar(&cfg.CAFile, "tls-ca", "", "服务端 CA 文件，为空时使用系统根证书")
+	fs.StringVar(&cfg.CertFile, "tls-cert", "", "客户端证书文件，服务端开启 mTLS 时需要")
+	fs.StringVar(&cfg.KeyFile, "tls-key", "", "客户端私钥文件")
+	fs.StringVar(&cfg.ServerName, "tls-server-name", "", "校验的服务端名称")
+	return cfg
+}
+
+// TLSConfig 创建客户端 tls.Config
+func (c ClientConfig) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: c.ServerName}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tlsconf: load ca: %w", err)
+		}
+		cfg.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tlsconf: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"example/pkg/basic/tlsconf"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 生成本地测试用的 CA、服务端证书和客户端证书：
+//
+//	go run ./pkg/basic/tlsconf/gencerts -dir certs
+//	go run ./pkg/basic/http/server -tls-cert certs/server.pem -tls-key certs/server-key.pem -tls-client-ca certs/ca.pem
+//	go run ./pkg/basic/http/user -tls -tls-ca certs/ca.pem -tls-cert certs/client.pem -tls-key certs/client-key.pem
+func main() {
+	dir := flag.String("dir", "certs", "输出目录")
+	hosts := flag.String("hosts", "", "服务端证书额外的主机名或 IP，逗号分隔")
+	client := flag.String("client", "test-client", "客户端证书的 CommonName")
+	flag.Parse()
+
+	var extra []string
+	for _, h := range strings.Split(*hosts, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			extra = append(extra, h)
+		}
+	}
+
+	ca, err := tlsconf.GenerateTestCA(*dir, *client, extra...)
+	if err != nil {
+		fmt.Println("Error generating certificates:", err)
+		os.Exit(1)
+	}
+	for _, name := range []string{tlsconf.CAFile, tlsconf.ServerCertFile, tlsconf.ServerKeyFile, tlsconf.ClientCertFile, tlsconf.ClientKeyFile} {
+		fmt.Println(ca.Path(name))
+	}
+}
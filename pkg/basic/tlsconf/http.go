@@ -0,0 +1,59 @@
+package tlsconf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// NewHTTPServer 创建 http.Server，cfg.Enabled() 时设置 TLSConfig，gin.Engine 可以直接作为 handler
+func NewHTTPServer(addr string, handler http.Handler, cfg Config) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if cfg.Enabled() {
+		tlsConfig, err := ServerConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
+	}
+	return srv, nil
+}
+
+// ListenAndServe 替代 http.ListenAndServe，cfg.Enabled() 时使用 HTTPS，否则使用明文
+// ctx 结束时优雅停机，等待进行中的请求最多 10 秒，正常停机返回 nil
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler, cfg Config) error {
+	srv, err := NewHTTPServer(addr, handler, cfg)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			// 证书由 TLSConfig 提供，文件名留空
+			errCh <- srv.ListenAndServeTLS("", "")
+		} else {
+			errCh <- srv.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
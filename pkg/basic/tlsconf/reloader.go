@@ -0,0 +1,139 @@
+package tlsconf
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader 持有当前的证书配置，握手时检查文件是否变化，变化后重新加载
+// 加载失败时继续使用旧配置，证书轮换时写了一半的文件不会导致服务中断
+type Reloader struct {
+	cfg Config
+
+	mu        sync.Mutex
+	current   *tls.Config
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader 加载证书，文件错误时返回错误
+func NewReloader(cfg Config) (*Reloader, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.ReloadInterval == 0 {
+		cfg.ReloadInterval = DefaultReloadInterval
+	}
+	r := &Reloader{cfg: cfg}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig 返回服务端 tls.Config，每次握手通过 GetConfigForClient 取当前配置
+func (r *Reloader) TLSConfig() *tls.Config {
+	cfg := baseConfig()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return r.config(), nil
+	}
+	return cfg
+}
+
+// Reload 立即重新加载证书和客户端 CA，可以在收到 SIGHUP 时调用
+func (r *Reloader) Reload() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tlsconf: load certificate: %w", err)
+	}
+	cfg := baseConfig()
+	cfg.Certificates = []tls.Certificate{cert}
+	if r.cfg.ClientCAFile != "" {
+		pool, err := loadCertPool(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tlsconf: load client ca: %w", err)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		if r.cfg.ClientAuthOptional {
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	r.mu.Lock()
+	r.current = cfg
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// config 返回当前配置，到了检查间隔时先检查文件
+func (r *Reloader) config() *tls.Config {
+	r.mu.Lock()
+	check := r.cfg.ReloadInterval > 0 && time.Since(r.lastCheck) >= r.cfg.ReloadInterval
+	if check {
+		r.lastCheck = time.Now()
+	}
+	current, modTimes := r.current, r.modTimes
+	r.mu.Unlock()
+
+	if !check {
+		return current
+	}
+	latest, err := r.stat()
+	if err != nil {
+		log.Printf("tlsconf: %v, keep using current certificate", err)
+		return current
+	}
+	if equalTimes(latest, modTimes) {
+		return current
+	}
+	if err := r.Reload(); err != nil {
+		log.Printf("tlsconf: %v, keep using current certificate", err)
+		return current
+	}
+	log.Printf("tlsconf: reloaded certificate %s", r.cfg.CertFile)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// stat 读取证书相关文件的修改时间
+func (r *Reloader) stat() (map[string]time.Time, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	modTimes := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, fmt.Errorf("tlsconf: %w", err)
+		}
+		modTimes[f] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func equalTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !b[k].Equal(v) {
+			return false
+		}
+	}
+	return true
+}
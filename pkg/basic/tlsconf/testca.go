@@ -0,0 +1,156 @@
+package tlsconf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 测试 CA 生成的文件名
+const (
+	CAFile         = "ca.pem"
+	CAKeyFile      = "ca-key.pem"
+	ServerCertFile = "server.pem"
+	ServerKeyFile  = "server-key.pem"
+	ClientCertFile = "client.pem"
+	ClientKeyFile  = "client-key.pem"
+)
+
+// TestCA 本地测试用的 CA 和证书路径，只能用于开发和测试
+type TestCA struct {
+	Dir string
+}
+
+// Path 返回目录下的文件路径
+func (ca TestCA) Path(name string) string {
+	return filepath.Join(ca.Dir, name)
+}
+
+// ServerConfig 使用测试证书的服务端配置，mtls 为 true 时要求客户端证书
+func (ca TestCA) ServerConfig(mtls bool) Config {
+	cfg := Config{CertFile: ca.Path(ServerCertFile), KeyFile: ca.Path(ServerKeyFile)}
+	if mtls {
+		cfg.ClientCAFile = ca.Path(CAFile)
+	}
+	return cfg
+}
+
+// ClientConfig 使用测试证书的客户端配置，mtls 为 true 时带上客户端证书
+func (ca TestCA) ClientConfig(mtls bool) ClientConfig {
+	cfg := ClientConfig{Enabled: true, CAFile: ca.Path(CAFile)}
+	if mtls {
+		cfg.CertFile = ca.Path(ClientCertFile)
+		cfg.KeyFile = ca.Path(ClientKeyFile)
+	}
+	return cfg
+}
+
+// GenerateTestCA 在 dir 下生成自签名 CA、服务端证书和客户端证书（ECDSA P-256，有效期 1 年）
+// 服务端证书包含 localhost、127.0.0.1、::1 和 hosts，客户端证书的 CommonName 为 clientName
+func GenerateTestCA(dir, clientName string, hosts ...string) (TestCA, error) {
+	ca := TestCA{Dir: dir}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ca, err
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return ca, err
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "go-language-learning test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caCert, err := createCert(ca.Path(CAFile), ca.Path(CAKeyFile), caTemplate, nil, caKey, caKey)
+	if err != nil {
+		return ca, err
+	}
+
+	server := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, h)
+		}
+	}
+	if err := createLeaf(ca.Path(ServerCertFile), ca.Path(ServerKeyFile), server, caCert, caKey); err != nil {
+		return ca, err
+	}
+
+	client := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: clientName},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if err := createLeaf(ca.Path(ClientCertFile), ca.Path(ClientKeyFile), client, caCert, caKey); err != nil {
+		return ca, err
+	}
+	return ca, nil
+}
+
+// createLeaf 生成由 CA 签发的证书
+func createLeaf(certPath, keyPath string, template, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	_, err = createCert(certPath, keyPath, template, caCert, key, caKey)
+	return err
+}
+
+// createCert 签发证书并写入 PEM 文件，parent 为 nil 时自签名
+func createCert(certPath, keyPath string, template, parent *x509.Certificate, key, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().AddDate(1, 0, 0)
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate %s: %w", certPath, err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writePEM(certPath, "CERTIFICATE", der, 0o644); err != nil {
+		return nil, err
+	}
+	if err := writePEM(keyPath, "EC PRIVATE KEY", keyDER, 0o600); err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// writePEM 先写临时文件再改名，热加载不会读到写了一半的文件
+func writePEM(path, typ string, der []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
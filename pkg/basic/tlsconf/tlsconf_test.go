@@ -0,0 +1,191 @@
+package tlsconf
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// serveTLS 在本机启动 TLS 监听，对每个连接完成握手后回复客户端证书的 CommonName
+func serveTLS(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	l, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn *tls.Conn) {
+				defer conn.Close()
+				if err := conn.Handshake(); err != nil {
+					return
+				}
+				name := "anonymous"
+				if certs := conn.ConnectionState().PeerCertificates; len(certs) > 0 {
+					name = certs[0].Subject.CommonName
+				}
+				io.WriteString(conn, name)
+			}(conn.(*tls.Conn))
+		}
+	}()
+	return l.Addr().String()
+}
+
+// dial 连接并读取服务端的回复
+func dial(addr string, cfg ClientConfig) (string, error) {
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return "", err
+	}
+	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	data, err := io.ReadAll(conn)
+	return string(data), err
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca, err := GenerateTestCA(t.TempDir(), "order-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	optional := ca.ServerConfig(true)
+	optional.ClientAuthOptional = true
+
+	tests := []struct {
+		name    string
+		server  Config
+		client  ClientConfig
+		want    string
+		wantErr bool
+	}{
+		{"单向 TLS", ca.ServerConfig(false), ca.ClientConfig(false), "anonymous", false},
+		{"mTLS", ca.ServerConfig(true), ca.ClientConfig(true), "order-service", false},
+		{"mTLS 缺少客户端证书", ca.ServerConfig(true), ca.ClientConfig(false), "", true},
+		{"mTLS 可选", optional, ca.ClientConfig(false), "anonymous", false},
+		{"不信任服务端 CA", ca.ServerConfig(false), ClientConfig{Enabled: true}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ServerConfig(tt.server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := dial(serveTLS(t, cfg), tt.client)
+			if tt.wantErr {
+				if err == nil && got != "" {
+					t.Fatalf("expected handshake failure, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dial error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := GenerateTestCA(dir, "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := ca.ServerConfig(false)
+	cfg.ReloadInterval = time.Millisecond
+	tlsConfig, err := ServerConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := serveTLS(t, tlsConfig)
+
+	serial := func() string {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.String()
+	}
+	before := serial()
+
+	// 写入损坏的证书时继续使用旧证书
+	os.WriteFile(ca.Path(ServerCertFile), []byte("broken"), 0o644)
+	time.Sleep(5 * time.Millisecond)
+	if got := serial(); got != before {
+		t.Fatalf("serial changed to %s after broken certificate", got)
+	}
+
+	// 重新签发后使用新证书
+	if _, err := GenerateTestCA(dir, "client"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if got := serial(); got == before {
+		t.Error("certificate not reloaded")
+	}
+}
+
+func TestListenAndServe(t *testing.T) {
+	ca, err := GenerateTestCA(t.TempDir(), "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	})
+	go func() { errCh <- ListenAndServe(ctx, addr, handler, ca.ServerConfig(true)) }()
+
+	tlsConfig, err := ca.ClientConfig(true).TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, ForceAttemptHTTP2: true}}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get("https://" + addr); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "client" || resp.ProtoMajor != 2 {
+		t.Errorf("got %q over %s, want client over HTTP/2", body, resp.Proto)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("ListenAndServe = %v, want nil", err)
+	}
+}
@@ -2,24 +2,41 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"example/pkg/basic/udp/rudp"
+	"flag"
 	"fmt"
 	"net"
 	"os"
+	"strings"
+	"time"
 )
 
 func main() {
 	// 服务器地址和端口
-	serverAddr := "127.0.0.1:9999"
-	udpAddr, err := net.ResolveUDPAddr("udp", serverAddr)
+	serverAddr := flag.String("addr", "127.0.0.1:9999", "服务器地址")
+	loss := flag.Float64("loss", 0, "模拟丢包率，0 到 1")
+	flag.Parse()
+
+	udpAddr, err := net.ResolveUDPAddr("udp", *serverAddr)
 	if err != nil {
 		fmt.Println("Error resolving address:", err)
 		return
 	}
 
-	// 创建UDP连接
-	conn, err := net.DialUDP("udp", nil, udpAddr)
+	// 创建可靠连接
+	pc, err := net.ListenPacket("udp", ":0")
+	if err != nil {
+		fmt.Println("Error listening:", err)
+		return
+	}
+	var packetConn net.PacketConn = pc
+	if *loss > 0 {
+		packetConn = rudp.NewLossyConn(pc, rudp.LossConfig{DropRate: *loss})
+	}
+	conn, err := rudp.New(packetConn, rudp.Config{})
 	if err != nil {
-		fmt.Println("Error dialing UDP:", err)
+		fmt.Println("Error creating reliable connection:", err)
 		return
 	}
 	defer conn.Close()
@@ -27,28 +44,32 @@ func main() {
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Print("Enter message to send (or type 'exit' to quit): ")
-		text, _ := reader.ReadString('\n')
-		text = text[:len(text)-1] // 去除换行符
+		text, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		text = strings.TrimRight(text, "\r\n") // 去除换行符
 
 		if text == "exit" {
 			fmt.Println("Exiting...")
 			break
 		}
 
-		_, err := conn.Write([]byte(text))
-		if err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := conn.Send(ctx, udpAddr, []byte(text)); err != nil {
+			cancel()
 			fmt.Println("Error sending data:", err)
-			return
+			continue
 		}
-		fmt.Println("Message sent to server")
+		fmt.Println("Message acknowledged by server")
 
 		// 准备接收服务器的回复
-		buffer := make([]byte, 1024)
-		n, _, err := conn.ReadFromUDP(buffer)
+		reply, err := conn.Receive(ctx)
+		cancel()
 		if err != nil {
 			fmt.Println("Error reading response:", err)
-			return
+			continue
 		}
-		fmt.Println("Server response:", string(buffer[:n]))
+		fmt.Println("Server response:", string(reply.Data))
 	}
 }
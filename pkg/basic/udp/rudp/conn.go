@@ -0,0 +1,541 @@
+// Package rudp 基于 UDP 的可靠消息：每条消息有序号，超过 MTU 时分片，每个分片单独确认，
+// 超时未确认的分片按指数退避重传，接收端按序号去重并重组后交付
+//
+// 只保证消息完整、不重复地送达，不保证多条消息之间的顺序
+package rudp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultMTU                  = 1200 // 单个 UDP 包的大小上限，低于常见链路 MTU，避免 IP 分片
+	DefaultRetransmitTimeout    = 100 * time.Millisecond
+	DefaultMaxRetransmitTimeout = time.Second
+	DefaultMaxRetries           = 8
+	DefaultWindow               = 64
+	DefaultMaxMessageSize       = 1 << 20
+	DefaultReceiveQueueSize     = 128
+	DefaultDedupWindow          = time.Minute
+	maxIncomingPerPeer          = 64 // 每个对端同时重组中的消息数上限
+	maxDatagramSize             = 65535
+)
+
+var (
+	// ErrClosed 连接已关闭
+	ErrClosed = errors.New("rudp: connection closed")
+	// ErrTimeout 重试 MaxRetries 次后仍有分片未被确认
+	ErrTimeout = errors.New("rudp: delivery timeout")
+	// ErrMessageTooLarge 消息超过 MaxMessageSize
+	ErrMessageTooLarge = errors.New("rudp: message too large")
+)
+
+// Config 可靠传输配置，零值使用默认值
+type Config struct {
+	MTU                  int           // 单个 UDP 包的大小上限，含包头
+	RetransmitTimeout    time.Duration // 首次重传超时，之后每次翻倍
+	MaxRetransmitTimeout time.Duration // 重传超时上限
+	MaxRetries           int           // 单个分片的最大重传次数
+	Window               int           // 单条消息同时在途（已发送未确认）的分片数上限
+	MaxMessageSize       int
+	ReceiveQueueSize     int           // 已重组、等待 Receive 的消息数，满时不确认最后一个分片，由发送端重传
+	DedupWindow          time.Duration // 已交付消息序号的保留时间，应大于发送端的最长重传时间
+}
+
+func (c *Config) setDefaults() error {
+	if c.MTU == 0 {
+		c.MTU = DefaultMTU
+	}
+	if c.RetransmitTimeout == 0 {
+		c.RetransmitTimeout = DefaultRetransmitTimeout
+	}
+	if c.MaxRetransmitTimeout == 0 {
+		c.MaxRetransmitTimeout = DefaultMaxRetransmitTimeout
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultMaxRetries
+	}
+	if c.Window == 0 {
+		c.Window = DefaultWindow
+	}
+	if c.MaxMessageSize == 0 {
+		c.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if c.ReceiveQueueSize == 0 {
+		c.ReceiveQueueSize = DefaultReceiveQueueSize
+	}
+	if c.DedupWindow == 0 {
+		c.DedupWindow = DefaultDedupWindow
+	}
+
+	if c.MTU <= headerSize || c.MTU > maxDatagramSize {
+		return fmt.Errorf("rudp: mtu must be between %d and %d", headerSize+1, maxDatagramSize)
+	}
+	if frags := (c.MaxMessageSize + c.fragmentSize() - 1) / c.fragmentSize(); frags > 0xFFFF {
+		return fmt.Errorf("rudp: max message size needs %d fragments, more than 65535", frags)
+	}
+	if c.RetransmitTimeout < 0 || c.MaxRetries < 0 || c.Window < 0 || c.MaxMessageSize < 0 || c.ReceiveQueueSize < 0 {
+		return fmt.Errorf("rudp: negative config value")
+	}
+	return nil
+}
+
+// fragmentSize 每个分片的数据长度
+func (c *Config) fragmentSize() int {
+	return c.MTU - headerSize
+}
+
+// Message 收到的消息
+type Message struct {
+	Addr net.Addr
+	Data []byte
+}
+
+// Stats 统计计数
+type Stats struct {
+	PacketsSent     uint64 // 发送的数据分片，含重传
+	Retransmits     uint64 // 重传的分片
+	AcksSent        uint64
+	Duplicates      uint64 // 收到的重复分片
+	Delivered       uint64 // 交付给 Receive 的消息
+	DroppedOverflow uint64 // 接收队列满而丢弃的分片
+}
+
+// Conn 可靠消息连接，同一个 Conn 可以和多个对端通信，方法可以被并发调用
+type Conn struct {
+	pc  net.PacketConn
+	cfg Config
+
+	mu    sync.Mutex
+	peers map[string]*peer
+
+	recvCh    chan Message
+	closed    chan struct{}
+	closeOnce sync.Once
+	readDone  chan struct{}
+	readErr   error
+
+	packetsSent, retransmits, acksSent, duplicates, delivered, droppedOverflow atomic.Uint64
+}
+
+// peer 一个对端的收发状态
+type peer struct {
+	addr   net.Addr
+	nextID atomic.Uint32 // 随机初始值，对端重启后的序号不会被误判为重复
+
+	mu        sync.Mutex
+	removed   bool                 // 已被 janitor 移除，需要重新获取
+	outgoing  map[uint32]*outgoing // 发送中的消息
+	incoming  map[uint32]*incoming // 重组中的消息
+	delivered map[uint32]time.Time // 已交付的消息序号，用于去重
+}
+
+// outgoing 发送中的消息，acked 由读协程写入
+type outgoing struct {
+	acked     []bool
+	remaining int
+	ackCh     chan struct{}
+}
+
+// incoming 重组中的消息
+type incoming struct {
+	frags    [][]byte
+	received int
+	created  time.Time
+}
+
+// Listen 监听本地 UDP 地址，如 ":9999"
+func Listen(addr string, cfg Config) (*Conn, error) {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(pc, cfg)
+	if err != nil {
+		pc.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// New 在已有的 PacketConn 上创建可靠连接，Conn 关闭时一起关闭 pc
+func New(pc net.PacketConn, cfg Config) (*Conn, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+	c := &Conn{
+		pc:       pc,
+		cfg:      cfg,
+		peers:    make(map[string]*peer),
+		recvCh:   make(chan Message, cfg.ReceiveQueueSize),
+		closed:   make(chan struct{}),
+		readDone: make(chan struct{}),
+	}
+	go c.readLoop()
+	go c.janitor()
+	return c, nil
+}
+
+// LocalAddr 本地地址
+func (c *Conn) LocalAddr() net.Addr {
+	return c.pc.LocalAddr()
+}
+
+// Close 关闭连接，进行中的 Send 和 Receive 返回 ErrClosed
+func (c *Conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.pc.Close()
+		<-c.readDone
+	})
+	return err
+}
+
+// Stats 返回统计计数
+func (c *Conn) Stats() Stats {
+	return Stats{
+		PacketsSent:     c.packetsSent.Load(),
+		Retransmits:     c.retransmits.Load(),
+		AcksSent:        c.acksSent.Load(),
+		Duplicates:      c.duplicates.Load(),
+		Delivered:       c.delivered.Load(),
+		DroppedOverflow: c.droppedOverflow.Load(),
+	}
+}
+
+// Send 可靠发送一条消息，所有分片都被确认后返回 nil
+// 返回 ErrTimeout 时对端可能已经收到部分分片，但不会交付不完整的消息
+func (c *Conn) Send(ctx context.Context, addr net.Addr, data []byte) error {
+	if len(data) > c.cfg.MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, len(data))
+	}
+	select {
+	case <-c.closed:
+		return ErrClosed
+	default:
+	}
+
+	p := c.lockPeer(addr)
+	id := p.nextID.Add(1)
+	packets := c.fragment(id, data)
+	out := &outgoing{acked: make([]bool, len(packets)), remaining: len(packets), ackCh: make(chan struct{}, 1)}
+	p.outgoing[id] = out
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.outgoing, id)
+		p.mu.Unlock()
+	}()
+
+	sends := make([]int, len(packets))
+	lastSent := make([]time.Time, len(packets))
+	tick := c.cfg.RetransmitTimeout / 4
+	if tick < 5*time.Millisecond {
+		tick = 5 * time.Millisecond
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.closed:
+			return ErrClosed
+		case <-out.ackCh:
+		case <-timer.C:
+			timer.Reset(tick)
+		}
+
+		p.mu.Lock()
+		if out.remaining == 0 {
+			p.mu.Unlock()
+			return nil
+		}
+		acked := append([]bool(nil), out.acked...)
+		p.mu.Unlock()
+
+		// 在途分片：已发送、未确认、未超时
+		now := time.Now()
+		inflight := 0
+		for i := range packets {
+			if !acked[i] && sends[i] > 0 && now.Sub(lastSent[i]) < c.rto(sends[i]) {
+				inflight++
+			}
+		}
+
+		for i, pkt := range packets {
+			if acked[i] {
+				continue
+			}
+			switch {
+			case sends[i] == 0:
+				if inflight >= c.cfg.Window {
+					continue
+				}
+				inflight++
+			case now.Sub(lastSent[i]) >= c.rto(sends[i]):
+				if sends[i] > c.cfg.MaxRetries {
+					return fmt.Errorf("%w: message %d to %s, fragment %d unacknowledged after %d attempts",
+						ErrTimeout, id, addr, i, sends[i])
+				}
+				c.retransmits.Add(1)
+			default:
+				continue
+			}
+			sends[i]++
+			lastSent[i] = now
+			c.packetsSent.Add(1)
+			if _, err := c.pc.WriteTo(pkt, addr); err != nil {
+				if c.isClosed() {
+					return ErrClosed
+				}
+				// 发送失败（如缓冲区满）等同于丢包，由重传处理
+			}
+		}
+	}
+}
+
+// Receive 等待下一条完整的消息
+func (c *Conn) Receive(ctx context.Context) (Message, error) {
+	select {
+	case msg := <-c.recvCh:
+		return msg, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	case <-c.closed:
+		return Message{}, ErrClosed
+	case <-c.readDone:
+		return Message{}, fmt.Errorf("rudp: read: %w", c.readErr)
+	}
+}
+
+// rto 第 n 次发送后的重传超时，指数退避
+func (c *Conn) rto(sends int) time.Duration {
+	d := c.cfg.RetransmitTimeout
+	for i := 1; i < sends && d < c.cfg.MaxRetransmitTimeout; i++ {
+		d *= 2
+	}
+	if d > c.cfg.MaxRetransmitTimeout {
+		d = c.cfg.MaxRetransmitTimeout
+	}
+	return d
+}
+
+// fragment 把消息切分为数据包，空消息也有一个分片
+func (c *Conn) fragment(id uint32, data []byte) [][]byte {
+	size := c.cfg.fragmentSize()
+	count := (len(data) + size - 1) / size
+	if count == 0 {
+		count = 1
+	}
+	packets := make([][]byte, count)
+	for i := range packets {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+		h := header{typ: typeData, msgID: id, fragIndex: uint16(i), fragCount: uint16(count)}
+		packets[i] = encodePacket(h, data[i*size:end])
+	}
+	return packets
+}
+
+// lockPeer 获取或创建对端状态并加锁，跳过刚被 janitor 移除的对端
+func (c *Conn) lockPeer(addr net.Addr) *peer {
+	for {
+		p := c.peer(addr)
+		p.mu.Lock()
+		if !p.removed {
+			return p
+		}
+		p.mu.Unlock()
+	}
+}
+
+// peer 获取或创建对端状态
+func (c *Conn) peer(addr net.Addr) *peer {
+	key := addr.String()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.peers[key]
+	if !ok {
+		p = &peer{
+			addr:      addr,
+			outgoing:  make(map[uint32]*outgoing),
+			incoming:  make(map[uint32]*incoming),
+			delivered: make(map[uint32]time.Time),
+		}
+		p.nextID.Store(rand.Uint32())
+		c.peers[key] = p
+	}
+	return p
+}
+
+// readLoop 读取所有数据包，缓冲区按 UDP 最大包长分配，不会截断
+func (c *Conn) readLoop() {
+	defer close(c.readDone)
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := c.pc.ReadFrom(buf)
+		if err != nil {
+			if c.isClosed() {
+				c.readErr = ErrClosed
+				return
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			c.readErr = err
+			return
+		}
+
+		h, payload, err := decodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		switch h.typ {
+		case typeAck:
+			c.handleAck(addr, h)
+		case typeData:
+			if c.handleData(addr, h, payload) {
+				c.ack(addr, h)
+			}
+		}
+	}
+}
+
+// handleAck 标记分片已确认
+func (c *Conn) handleAck(addr net.Addr, h header) {
+	p := c.lockPeer(addr)
+	defer p.mu.Unlock()
+	out, ok := p.outgoing[h.msgID]
+	if !ok || int(h.fragIndex) >= len(out.acked) || out.acked[h.fragIndex] {
+		return
+	}
+	out.acked[h.fragIndex] = true
+	out.remaining--
+	select {
+	case out.ackCh <- struct{}{}:
+	default:
+	}
+}
+
+// handleData 保存分片，消息完整时交付，返回是否需要确认
+// 重复的分片也要确认，因为对端可能没有收到上一次的 ACK
+func (c *Conn) handleData(addr net.Addr, h header, payload []byte) bool {
+	p := c.lockPeer(addr)
+	defer p.mu.Unlock()
+
+	if _, ok := p.delivered[h.msgID]; ok {
+		c.duplicates.Add(1)
+		return true
+	}
+	in, ok := p.incoming[h.msgID]
+	if !ok {
+		if len(p.incoming) >= maxIncomingPerPeer {
+			return false
+		}
+		if int(h.fragCount)*c.cfg.fragmentSize() > c.cfg.MaxMessageSize+c.cfg.fragmentSize() {
+			return false
+		}
+		in = &incoming{frags: make([][]byte, h.fragCount), created: time.Now()}
+		p.incoming[h.msgID] = in
+	}
+	if len(in.frags) != int(h.fragCount) {
+		return false
+	}
+	if in.frags[h.fragIndex] != nil {
+		c.duplicates.Add(1)
+		return true
+	}
+
+	if in.received+1 < len(in.frags) {
+		in.frags[h.fragIndex] = append([]byte{}, payload...)
+		in.received++
+		return true
+	}
+
+	// 最后一个分片：重组并交付，接收队列满时丢弃该分片，等待重传
+	in.frags[h.fragIndex] = payload
+	size := 0
+	for _, f := range in.frags {
+		size += len(f)
+	}
+	data := make([]byte, 0, size)
+	for _, f := range in.frags {
+		data = append(data, f...)
+	}
+	select {
+	case c.recvCh <- Message{Addr: addr, Data: data}:
+	default:
+		in.frags[h.fragIndex] = nil
+		c.droppedOverflow.Add(1)
+		return false
+	}
+	delete(p.incoming, h.msgID)
+	p.delivered[h.msgID] = time.Now()
+	c.delivered.Add(1)
+	return true
+}
+
+// ack 确认一个分片
+func (c *Conn) ack(addr net.Addr, h header) {
+	pkt := encodePacket(header{typ: typeAck, msgID: h.msgID, fragIndex: h.fragIndex, fragCount: h.fragCount}, nil)
+	if _, err := c.pc.WriteTo(pkt, addr); err == nil {
+		c.acksSent.Add(1)
+	}
+}
+
+// janitor 定期清理过期的去重记录、未完成的重组和空闲的对端
+func (c *Conn) janitor() {
+	ticker := time.NewTicker(c.cfg.DedupWindow / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for key, p := range c.peers {
+				p.mu.Lock()
+				for id, t := range p.delivered {
+					if now.Sub(t) > c.cfg.DedupWindow {
+						delete(p.delivered, id)
+					}
+				}
+				for id, in := range p.incoming {
+					if now.Sub(in.created) > c.cfg.DedupWindow {
+						delete(p.incoming, id)
+					}
+				}
+				if len(p.delivered) == 0 && len(p.incoming) == 0 && len(p.outgoing) == 0 {
+					p.removed = true
+					delete(c.peers, key)
+				}
+				p.mu.Unlock()
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *Conn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
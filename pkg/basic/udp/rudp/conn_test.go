@@ -0,0 +1,127 @@
+package rudp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"testing"
+	"time"
+)
+
+// newPair 创建两个经过丢包模拟的本机连接
+func newPair(t *testing.T, loss LossConfig, cfg Config) (*Conn, *Conn) {
+	t.Helper()
+	newConn := func(seed int64) *Conn {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := loss
+		l.Seed = seed
+		c, err := New(NewLossyConn(pc, l), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+	return newConn(1), newConn(2)
+}
+
+func TestSendReceive(t *testing.T) {
+	a, b := newPair(t, LossConfig{}, Config{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, msg := range []string{"hello", ""} {
+		if err := a.Send(ctx, b.LocalAddr(), []byte(msg)); err != nil {
+			t.Fatalf("Send(%q) error: %v", msg, err)
+		}
+		got, err := b.Receive(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got.Data) != msg || got.Addr.String() != a.LocalAddr().String() {
+			t.Errorf("Receive = %q from %v, want %q from %v", got.Data, got.Addr, msg, a.LocalAddr())
+		}
+	}
+}
+
+func TestLossyNetwork(t *testing.T) {
+	loss := LossConfig{DropRate: 0.2, DuplicateRate: 0.2, MaxDelay: 5 * time.Millisecond}
+	a, b := newPair(t, loss, Config{RetransmitTimeout: 20 * time.Millisecond, MaxRetries: 20})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// 小消息和需要分片的大消息混合并发发送
+	rnd := rand.New(rand.NewSource(42))
+	msgs := make([][]byte, 20)
+	for i := range msgs {
+		size := rnd.Intn(100)
+		if i%4 == 0 {
+			size = 50*1024 + rnd.Intn(1024)
+		}
+		msgs[i] = make([]byte, size)
+		rnd.Read(msgs[i])
+		msgs[i] = append([]byte{byte(i)}, msgs[i]...)
+	}
+
+	errCh := make(chan error, len(msgs))
+	for _, msg := range msgs {
+		go func(msg []byte) { errCh <- a.Send(ctx, b.LocalAddr(), msg) }(msg)
+	}
+
+	received := make(map[byte]bool)
+	for range msgs {
+		got, err := b.Receive(ctx)
+		if err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+		i := got.Data[0]
+		if received[i] {
+			t.Fatalf("message %d delivered twice", i)
+		}
+		received[i] = true
+		if !bytes.Equal(got.Data, msgs[i]) {
+			t.Fatalf("message %d corrupted: %d bytes, want %d", i, len(got.Data), len(msgs[i]))
+		}
+	}
+	for range msgs {
+		if err := <-errCh; err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	// 所有发送都已确认，之后到达的重传和重复包不能再次交付
+	shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer shortCancel()
+	if got, err := b.Receive(shortCtx); err == nil {
+		t.Fatalf("unexpected extra message %d", got.Data[0])
+	}
+
+	stats := a.Stats()
+	if stats.Retransmits == 0 {
+		t.Error("expected retransmits on lossy network")
+	}
+	t.Logf("sender %+v, receiver %+v", stats, b.Stats())
+}
+
+func TestSendTimeout(t *testing.T) {
+	a, b := newPair(t, LossConfig{}, Config{RetransmitTimeout: 10 * time.Millisecond, MaxRetries: 3})
+	addr := b.LocalAddr()
+	b.Close()
+
+	err := a.Send(context.Background(), addr, []byte("anyone?"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Send to closed peer = %v, want ErrTimeout", err)
+	}
+}
+
+func TestMessageTooLarge(t *testing.T) {
+	a, b := newPair(t, LossConfig{}, Config{MaxMessageSize: 10})
+	if err := a.Send(context.Background(), b.LocalAddr(), make([]byte, 11)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Send = %v, want ErrMessageTooLarge", err)
+	}
+}
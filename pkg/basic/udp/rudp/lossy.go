@@ -0,0 +1,71 @@
+package rudp
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// LossConfig 丢包模拟配置，概率取值 0 到 1
+type LossConfig struct {
+	DropRate      float64       // 丢包概率
+	DuplicateRate float64       // 重复发送概率
+	MaxDelay      time.Duration // 每个包随机延迟 0 到 MaxDelay，延迟不同的包会乱序
+	Seed          int64         // 随机种子，为 0 时使用当前时间
+}
+
+// LossyConn 在发送方向模拟丢包、重复和乱序的 PacketConn，用于在本机测试可靠传输
+// 通信双方都包装后两个方向都会丢包
+type LossyConn struct {
+	net.PacketConn
+	cfg LossConfig
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewLossyConn 包装 pc
+func NewLossyConn(pc net.PacketConn, cfg LossConfig) *LossyConn {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &LossyConn{PacketConn: pc, cfg: cfg, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// WriteTo 按配置丢弃、重复或延迟发送，丢弃时也返回成功，和真实网络一样发送方无法感知
+func (c *LossyConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.mu.Lock()
+	drop := c.rnd.Float64() < c.cfg.DropRate
+	copies := 1
+	if c.rnd.Float64() < c.cfg.DuplicateRate {
+		copies = 2
+	}
+	delays := make([]time.Duration, copies)
+	if c.cfg.MaxDelay > 0 {
+		for i := range delays {
+			delays[i] = time.Duration(c.rnd.Int63n(int64(c.cfg.MaxDelay)))
+		}
+	}
+	c.mu.Unlock()
+
+	if drop {
+		return len(p), nil
+	}
+	if c.cfg.MaxDelay <= 0 {
+		for i := 0; i < copies; i++ {
+			if _, err := c.PacketConn.WriteTo(p, addr); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+
+	// 调用方可能复用 p，延迟发送需要复制
+	pkt := append([]byte(nil), p...)
+	for _, d := range delays {
+		time.AfterFunc(d, func() { c.PacketConn.WriteTo(pkt, addr) })
+	}
+	return len(p), nil
+}
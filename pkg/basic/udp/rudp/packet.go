@@ -0,0 +1,59 @@
+package rudp
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// 包类型
+const (
+	typeData byte = 1 // 数据分片
+	typeAck  byte = 2 // 确认一个分片
+)
+
+// headerSize 包头长度：类型(1) 保留(1) 消息序号(4) 分片序号(2) 分片总数(2)，大端
+const headerSize = 10
+
+var errInvalidPacket = errors.New("rudp: invalid packet")
+
+// header 包头，ACK 包只有包头，分片序号为被确认的分片
+type header struct {
+	typ       byte
+	msgID     uint32
+	fragIndex uint16
+	fragCount uint16
+}
+
+// encodePacket 组装一个包
+func encodePacket(h header, payload []byte) []byte {
+	b := make([]byte, headerSize+len(payload))
+	b[0] = h.typ
+	binary.BigEndian.PutUint32(b[2:6], h.msgID)
+	binary.BigEndian.PutUint16(b[6:8], h.fragIndex)
+	binary.BigEndian.PutUint16(b[8:10], h.fragCount)
+	copy(b[headerSize:], payload)
+	return b
+}
+
+// decodePacket 解析包，返回的 payload 引用 b
+func decodePacket(b []byte) (header, []byte, error) {
+	if len(b) < headerSize {
+		return header{}, nil, errInvalidPacket
+	}
+	h := header{
+		typ:       b[0],
+		msgID:     binary.BigEndian.Uint32(b[2:6]),
+		fragIndex: binary.BigEndian.Uint16(b[6:8]),
+		fragCount: binary.BigEndian.Uint16(b[8:10]),
+	}
+	switch h.typ {
+	case typeData:
+		if h.fragCount == 0 || h.fragIndex >= h.fragCount {
+			return header{}, nil, errInvalidPacket
+		}
+	case typeAck:
+	default:
+		return header{}, nil, errInvalidPacket
+	}
+	return h, b[headerSize:], nil
+}
@@ -1,42 +1,61 @@
 package main
 
 import (
+	"context"
+	"example/pkg/basic/udp/rudp"
+	"flag"
 	"fmt"
 	"net"
 )
 
 func main() {
 	// 监听地址和端口
-	addr := "127.0.0.1:9999"
-	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	addr := flag.String("addr", "127.0.0.1:9999", "监听地址")
+	loss := flag.Float64("loss", 0, "模拟丢包率，0 到 1")
+	flag.Parse()
+
+	pc, err := net.ListenPacket("udp", *addr)
 	if err != nil {
-		fmt.Println("Error resolving address:", err)
+		fmt.Println("Error listening:", err)
 		return
 	}
-
-	conn, err := net.ListenUDP("udp", udpAddr)
+	var packetConn net.PacketConn = pc
+	if *loss > 0 {
+		packetConn = rudp.NewLossyConn(pc, rudp.LossConfig{DropRate: *loss})
+	}
+	conn, err := rudp.New(packetConn, rudp.Config{})
 	if err != nil {
-		fmt.Println("Error listening:", err)
+		fmt.Println("Error creating reliable connection:", err)
 		return
 	}
 	defer conn.Close()
 
-	fmt.Println("UDP server listening on", addr)
+	fmt.Println("Reliable UDP server listening on", *addr)
 
+	ctx := context.Background()
 	for {
-		// 缓冲区用于存放接收的数据
-		buf := make([]byte, 1024)
-		n, remoteAddr, err := conn.ReadFromUDP(buf)
+		msg, err := conn.Receive(ctx)
 		if err != nil {
-			fmt.Println("Error reading from UDP:", err)
-			continue
+			fmt.Println("Error receiving:", err)
+			return
 		}
-		fmt.Printf("Received %d bytes from %v: %s\n", n, remoteAddr, string(buf[:n]))
+		fmt.Printf("Received %d bytes from %v: %s\n", len(msg.Data), msg.Addr, preview(msg.Data))
 
-		// 回复消息给客户端
-		_, err = conn.WriteToUDP([]byte("Message received"), remoteAddr)
-		if err != nil {
-			fmt.Println("Error writing to UDP:", err)
-		}
+		// 回复消息给客户端，等待确认不阻塞接收
+		go func(msg rudp.Message) {
+			reply := []byte(fmt.Sprintf("Message received: %d bytes", len(msg.Data)))
+			if err := conn.Send(ctx, msg.Addr, reply); err != nil {
+				fmt.Println("Error replying:", err)
+			}
+			fmt.Printf("Stats: %+v\n", conn.Stats())
+		}(msg)
+	}
+}
+
+// preview 长消息只打印开头
+func preview(data []byte) string {
+	if len(data) > 64 {
+		return string(data[:64]) + "..."
 	}
+	return string(data)
 }
@@ -1,41 +1,62 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"example/pkg/basic/udp/rudp"
+	"flag"
 	"fmt"
 	"net"
+	"time"
 )
 
 func main() {
 	// 服务器地址和端口
-	serverAddr := "127.0.0.1:9999"
-	udpAddr, err := net.ResolveUDPAddr("udp", serverAddr)
+	serverAddr := flag.String("addr", "127.0.0.1:9999", "服务器地址")
+	size := flag.Int("size", 10000, "消息大小，超过 MTU 时自动分片")
+	loss := flag.Float64("loss", 0, "模拟丢包率，0 到 1")
+	flag.Parse()
+
+	udpAddr, err := net.ResolveUDPAddr("udp", *serverAddr)
 	if err != nil {
 		fmt.Println("Error resolving address:", err)
 		return
 	}
 
-	// 建立连接
-	conn, err := net.DialUDP("udp", nil, udpAddr)
+	// 本地随机端口，服务端按来源地址回复
+	pc, err := net.ListenPacket("udp", ":0")
 	if err != nil {
-		fmt.Println("Error dialing UDP:", err)
+		fmt.Println("Error listening:", err)
+		return
+	}
+	var packetConn net.PacketConn = pc
+	if *loss > 0 {
+		packetConn = rudp.NewLossyConn(pc, rudp.LossConfig{DropRate: *loss})
+	}
+	conn, err := rudp.New(packetConn, rudp.Config{})
+	if err != nil {
+		fmt.Println("Error creating reliable connection:", err)
 		return
 	}
 	defer conn.Close()
 
-	message := []byte("Hello, UDP Server!")
-	_, err = conn.Write(message)
-	if err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	message := bytes.Repeat([]byte("Hello, UDP Server! "), *size/19+1)[:*size]
+	start := time.Now()
+	if err := conn.Send(ctx, udpAddr, message); err != nil {
 		fmt.Println("Error sending data:", err)
 		return
 	}
-	fmt.Println("Message sent to server")
+	fmt.Printf("Message of %d bytes acknowledged in %v\n", len(message), time.Since(start))
 
 	// 接收服务器响应
-	buffer := make([]byte, 1024)
-	n, _, err := conn.ReadFromUDP(buffer)
+	reply, err := conn.Receive(ctx)
 	if err != nil {
 		fmt.Println("Error reading response:", err)
 		return
 	}
-	fmt.Println("Server response:", string(buffer[:n]))
+	fmt.Println("Server response:", string(reply.Data))
+	fmt.Printf("Stats: %+v\n", conn.Stats())
 }